@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ntgrrc/pkg/netgear"
+	"os"
+	"strconv"
+)
+
+type SnapshotCommand struct {
+	Address string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+}
+
+type ApplyCommand struct {
+	Address string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	File    string `required:"" help:"snapshot JSON file, as printed by the snapshot command" type:"existingfile"`
+	DryRun  bool   `optional:"" help:"only show the changes, without applying them" name:"dry-run"`
+}
+
+func (snapshot *SnapshotCommand) Run(args *GlobalOptions) error {
+	client, err := newLibraryClient(args, snapshot.Address)
+	if err != nil {
+		return err
+	}
+	result, err := client.Snapshot(context.Background())
+	if err != nil {
+		return err
+	}
+	// always JSON, so it can be saved and applied again
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func (apply *ApplyCommand) Run(args *GlobalOptions) error {
+	data, err := os.ReadFile(apply.File)
+	if err != nil {
+		return err
+	}
+	var snapshot netgear.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return errors.New(fmt.Sprintf("invalid snapshot file '%s': %v", apply.File, err))
+	}
+
+	client, err := newLibraryClient(args, apply.Address)
+	if err != nil {
+		return err
+	}
+	changes, err := client.ApplySnapshot(context.Background(), &snapshot, apply.DryRun)
+	if err != nil {
+		return err
+	}
+	prettyPrintSnapshotChanges(args.OutputFormat, changes)
+	return nil
+}
+
+// newLibraryClient creates a netgear.Client, which uses the session (token) of the login command
+func newLibraryClient(args *GlobalOptions, host string) (*netgear.Client, error) {
+	model, token, err := readTokenAndModel2GlobalOptions(args, host)
+	if err != nil {
+		return nil, err
+	}
+	tokenMgr := netgear.NewMemoryTokenManager()
+	_ = tokenMgr.StoreToken(context.Background(), host, token, netgear.Model(model))
+	return netgear.NewClient(host,
+		netgear.WithTokenManager(tokenMgr),
+		netgear.WithTimeout(args.Timeout),
+		netgear.WithVerbose(args.Verbose),
+		netgear.WithQuiet(args.Quiet),
+		netgear.WithEnvironmentAuth(false))
+}
+
+func prettyPrintSnapshotChanges(format OutputFormat, changes *netgear.SnapshotChanges) {
+	var header = []string{"Port ID", "Setting", "Value"}
+	var content [][]string
+	for _, update := range changes.POE {
+		if update.Enabled != nil {
+			content = append(content, []string{strconv.Itoa(update.PortID), "POE enabled", strconv.FormatBool(*update.Enabled)})
+		}
+		if update.Mode != nil {
+			content = append(content, []string{strconv.Itoa(update.PortID), "POE mode", string(*update.Mode)})
+		}
+		if update.Priority != nil {
+			content = append(content, []string{strconv.Itoa(update.PortID), "POE priority", string(*update.Priority)})
+		}
+		if update.PowerLimitType != nil {
+			content = append(content, []string{strconv.Itoa(update.PortID), "POE power limit type", string(*update.PowerLimitType)})
+		}
+		if update.PowerLimitW != nil {
+			content = append(content, []string{strconv.Itoa(update.PortID), "POE power limit (W)", fmt.Sprintf("%.1f", *update.PowerLimitW)})
+		}
+		if update.DetectionType != nil {
+			content = append(content, []string{strconv.Itoa(update.PortID), "POE detection type", *update.DetectionType})
+		}
+	}
+	for _, update := range changes.Ports {
+		if update.Name != nil {
+			content = append(content, []string{strconv.Itoa(update.PortID), "port name", *update.Name})
+		}
+		if update.Speed != nil {
+			content = append(content, []string{strconv.Itoa(update.PortID), "speed", string(*update.Speed)})
+		}
+		if update.IngressLimit != nil {
+			content = append(content, []string{strconv.Itoa(update.PortID), "ingress limit", *update.IngressLimit})
+		}
+		if update.EgressLimit != nil {
+			content = append(content, []string{strconv.Itoa(update.PortID), "egress limit", *update.EgressLimit})
+		}
+		if update.FlowControl != nil {
+			content = append(content, []string{strconv.Itoa(update.PortID), "flow control", strconv.FormatBool(*update.FlowControl)})
+		}
+	}
+	switch format {
+	case MarkdownFormat:
+		printMarkdownTable(header, content)
+	case JsonFormat:
+		printJsonDataTable("changes", header, content)
+	default:
+		panic("not implemented format: " + format)
+	}
+}
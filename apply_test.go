@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestApplyRejectsSnapshotOfOtherModel(t *testing.T) {
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, "localhost:1", "token", GS308EPP)
+	file := filepath.Join(t.TempDir(), "snapshot.json")
+	then.AssertThat(t, os.WriteFile(file, []byte(`{"model": "GS316EP", "poe": [], "ports": []}`), 0644), is.Nil())
+
+	exitCode := run([]string{"--token-dir", tokenDir, "apply", "--address", "localhost:1", "--file", file})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeModelError))
+}
+
+func TestApplyRejectsInvalidSnapshotFile(t *testing.T) {
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, "localhost:1", "token", GS308EPP)
+	file := filepath.Join(t.TempDir(), "snapshot.json")
+	then.AssertThat(t, os.WriteFile(file, []byte(`not json`), 0644), is.Nil())
+
+	exitCode := run([]string{"--token-dir", tokenDir, "apply", "--address", "localhost:1", "--file", file})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeGeneralError))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"ntgrrc/pkg/netgear"
+)
+
+type CapabilitiesCommand struct {
+	Address string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Model   string `optional:"" help:"the switch's model, e.g. GS308EP; if omitted, it will be detected" short:"m"`
+}
+
+func (capabilities *CapabilitiesCommand) Run(args *GlobalOptions) error {
+	model := NetgearModel(capabilities.Model)
+	if len(capabilities.Model) > 0 && !isSupportedModel(capabilities.Model) {
+		return netgear.NewModelError(fmt.Sprintf("model '%s' not supported", capabilities.Model), nil)
+	}
+	if len(model) == 0 {
+		var err error
+		model, err = detectNetgearModel(args, capabilities.Address)
+		if err != nil {
+			return err
+		}
+	}
+
+	prettyPrintCapabilities(args.OutputFormat, model, netgear.Model(model).Capabilities())
+	return nil
+}
+
+func prettyPrintCapabilities(format OutputFormat, model NetgearModel, capabilities netgear.Capabilities) {
+	var header = []string{"Model", "Capability", "Supported"}
+	var content [][]string
+	for _, capability := range []struct {
+		name      string
+		supported bool
+	}{
+		{"POE", capabilities.POE},
+		{"POE schedule", capabilities.POESchedule},
+		{"POE power-up delay", capabilities.PowerUpDelay},
+		{"VLAN", capabilities.VLAN},
+		{"LAG", capabilities.LAG},
+		{"LLDP", capabilities.LLDP},
+		{"Cable test", capabilities.CableTest},
+		{"Port security", capabilities.PortSecurity},
+	} {
+		content = append(content, []string{string(model), capability.name, fmt.Sprintf("%t", capability.supported)})
+	}
+	switch format {
+	case MarkdownFormat:
+		printMarkdownTable(header, content)
+	case JsonFormat:
+		printJsonDataTable("capabilities", header, content)
+	default:
+		panic("not implemented format: " + format)
+	}
+}
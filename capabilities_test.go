@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestCapabilitiesCommand(t *testing.T) {
+	tests := []struct {
+		model       string
+		expectedLAG string
+	}{
+		{model: "GS305EP", expectedLAG: "| GS305EP | LAG                | false     |"},
+		{model: "GS316EPP", expectedLAG: "| GS316EPP | LAG                | true      |"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.model, func(t *testing.T) {
+			var exitCode int
+			output := captureOutput(func() {
+				exitCode = run([]string{"capabilities", "--address", "192.0.2.1", "--model", test.model})
+			})
+
+			then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+			then.AssertThat(t, strings.Contains(output, test.expectedLAG), is.True())
+			then.AssertThat(t, strings.Contains(output, "| POE                | true      |"), is.True())
+		})
+	}
+}
+
+func TestCapabilitiesCommandUnsupportedModel(t *testing.T) {
+	cmd := CapabilitiesCommand{Address: "192.0.2.1", Model: "GS108E"}
+
+	err := cmd.Run(createTestGlobalOptions(false, true, MarkdownFormat))
+
+	then.AssertThat(t, exitCodeForError(err), is.EqualTo(exitCodeModelError))
+}
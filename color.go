@@ -0,0 +1,32 @@
+package main
+
+import (
+	"golang.org/x/term"
+	"os"
+)
+
+// ANSI escape codes for coloring the human-readable output
+const (
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+	ansiReset  = "\033[0m"
+)
+
+// noColor disables colors even on a terminal, set by --no-color or the NO_COLOR environment variable
+var noColor = false
+
+// colorEnabled tells whether output to the file gets colored; only terminals do, never pipes or files
+func colorEnabled(file *os.File) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return term.IsTerminal(int(file.Fd()))
+}
+
+// colorize wraps the text in the ANSI color, if output to the file gets colored
+func colorize(file *os.File, color string, text string) string {
+	if !colorEnabled(file) {
+		return text
+	}
+	return color + text + ansiReset
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"ntgrrc/pkg/netgear"
+	"strings"
+)
+
+type DashboardCommand struct {
+	Address string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+}
+
+func (dashboard *DashboardCommand) Run(args *GlobalOptions) error {
+	summary, err := requestDashboard(args, dashboard.Address)
+	if err != nil {
+		return err
+	}
+	prettyPrintDashboard(args.OutputFormat, summary)
+	return nil
+}
+
+// requestDashboard summarizes the port settings and POE status pages, like netgear.Client.GetDashboard
+func requestDashboard(args *GlobalOptions, host string) (netgear.Dashboard, error) {
+	summary := netgear.Dashboard{Alarms: []string{}}
+	model, _, err := readTokenAndModel2GlobalOptions(args, host)
+	if err != nil {
+		return summary, err
+	}
+	args.model = model
+
+	settings, _, err := requestPortSettings(args, host)
+	if err != nil {
+		return summary, err
+	}
+
+	summary.TotalPorts = len(settings)
+	for _, setting := range settings {
+		if isPortConnected(setting) {
+			summary.ConnectedPorts++
+		}
+	}
+
+	statuses, err := requestPoeStatus(args, host)
+	if err != nil {
+		return summary, err
+	}
+	for _, status := range statuses {
+		if status.PowerInWatt > 0 || strings.EqualFold(status.PoePortStatus, "Delivering Power") {
+			summary.POEPortsActive++
+		}
+		summary.TotalPOEPowerW += float64(status.PowerInWatt)
+		if status.ErrorStatus != "" && netgear.ParsePOEFault(status.ErrorStatus) != netgear.POEFaultNone {
+			summary.Alarms = append(summary.Alarms, fmt.Sprintf("POE fault on port %d: %s", status.PortIndex, status.ErrorStatus))
+		}
+	}
+	return summary, nil
+}
+
+func prettyPrintDashboard(format OutputFormat, summary netgear.Dashboard) {
+	var header = []string{"Total Ports", "Connected Ports", "POE Ports Active", "Total POE Power (W)", "Alarms"}
+	var content [][]string
+	content = append(content, []string{
+		fmt.Sprintf("%d", summary.TotalPorts),
+		fmt.Sprintf("%d", summary.ConnectedPorts),
+		fmt.Sprintf("%d", summary.POEPortsActive),
+		fmt.Sprintf("%.1f", summary.TotalPOEPowerW),
+		strings.Join(summary.Alarms, "; "),
+	})
+	switch format {
+	case MarkdownFormat:
+		printMarkdownTable(header, content)
+	case JsonFormat:
+		printJsonDataTable("dashboard", header, content)
+	default:
+		panic("not implemented format: " + format)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestDashboard(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dashboard.cgi":
+			_, _ = w.Write([]byte(loadTestFile("GS308EPP", "dashboard.cgi.html")))
+		case "/getPoePortStatus.cgi":
+			_, _ = w.Write([]byte(loadTestFile("GS308EPP", "getPoePortStatus.cgi.html")))
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, "token", GS308EPP)
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "--output-format", "json", "dashboard", "--address", host})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	then.AssertThat(t, output, is.StringContaining(`"Total Ports": "8"`))
+	then.AssertThat(t, output, is.StringContaining(`"Connected Ports": "1"`))
+	then.AssertThat(t, output, is.StringContaining(`"POE Ports Active": "2"`))
+	then.AssertThat(t, output, is.StringContaining(`"Total POE Power (W)": "7.5"`))
+}
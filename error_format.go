@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"ntgrrc/pkg/netgear"
+	"os"
+)
+
+// errorTypeGeneral is the type of JSON errors, which aren't a netgear.Error
+const errorTypeGeneral = "general"
+
+// errorReasonHostResolution tells scripts, that a network error won't go away by retrying
+const errorReasonHostResolution = "host_resolution"
+
+// jsonError is the error object printed with --error-format json
+type jsonError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Cause   string `json:"cause,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// printError prints the error of a failed command as text, or with errorFormat "json" as an error object,
+// whose type lets scripts branch without parsing the message
+func printError(errorFormat string, err error) {
+	if errorFormat != "json" {
+		fmt.Printf("%s %s\n", colorize(os.Stdout, ansiRed, "Error:"), err.Error())
+		return
+	}
+	printJson(map[string]jsonError{"error": toJsonError(err)})
+}
+
+// toJsonError derives the error object from a wrapped netgear.Error, other errors get the general type
+func toJsonError(err error) jsonError {
+	var netgearErr *netgear.Error
+	if !errors.As(err, &netgearErr) {
+		return jsonError{Type: errorTypeGeneral, Message: err.Error()}
+	}
+	result := jsonError{Type: string(netgearErr.Type), Message: netgearErr.Message}
+	if netgearErr.Cause != nil {
+		result.Cause = netgearErr.Cause.Error()
+	}
+	if errors.Is(err, netgear.ErrHostResolution) {
+		result.Reason = errorReasonHostResolution
+	}
+	return result
+}
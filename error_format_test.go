@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ntgrrc/pkg/netgear"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestRunWithJsonErrorFormatPrintsErrorObject(t *testing.T) {
+	tokenDir := t.TempDir()
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--error-format", "json", "poe", "status", "--address", "localhost:1", "--token-dir", tokenDir})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeAuthError))
+	var result map[string]jsonError
+	err := json.Unmarshal([]byte(output), &result)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, result["error"].Type, is.EqualTo("authentication"))
+	then.AssertThat(t, result["error"].Message, is.Not(is.EqualTo("")))
+}
+
+func TestToJsonError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected jsonError
+	}{
+		{"plain error", errors.New("boom"), jsonError{Type: errorTypeGeneral, Message: "boom"}},
+		{"netgear error", netgear.NewParsingError("bad page", nil), jsonError{Type: "parsing", Message: "bad page"}},
+		{"with cause", netgear.NewNetworkError("request failed", errors.New("connection refused")),
+			jsonError{Type: "network", Message: "request failed", Cause: "connection refused"}},
+		{"host resolution", netgear.NewNetworkError("request failed", netgear.ErrHostResolution),
+			jsonError{Type: "network", Message: "request failed", Cause: "network error: host name could not be resolved", Reason: errorReasonHostResolution}},
+		{"wrapped error", fmt.Errorf("context: %w", netgear.NewAuthError("expired", nil)), jsonError{Type: "authentication", Message: "expired"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			then.AssertThat(t, toJsonError(test.err), is.EqualTo(test.expected))
+		})
+	}
+}
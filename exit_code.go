@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"ntgrrc/pkg/netgear"
+)
+
+// process exit codes, documented in README.md
+const (
+	exitCodeOK             = 0
+	exitCodeGeneralError   = 1
+	exitCodeAuthError      = 2
+	exitCodeNetworkError   = 3
+	exitCodeModelError     = 4
+	exitCodeParsingError   = 5
+	exitCodeOperationError = 6
+	// exitCodeThresholdExceeded signals monitoring scripts, that e.g. 'poe budget --alert-at' was triggered
+	exitCodeThresholdExceeded = 7
+	// exitCodeNotApplied tells scripts, that the changes of a --diff were pending, but not confirmed
+	exitCodeNotApplied = 9
+)
+
+// exitCodeForError maps an error to an exit code, based on the type of a wrapped netgear.Error
+func exitCodeForError(err error) int {
+	if err == nil {
+		return exitCodeOK
+	}
+	if errors.Is(err, errPoeBudgetThresholdExceeded) {
+		return exitCodeThresholdExceeded
+	}
+	if errors.Is(err, errChangesNotApplied) {
+		return exitCodeNotApplied
+	}
+	var netgearErr *netgear.Error
+	if !errors.As(err, &netgearErr) {
+		return exitCodeGeneralError
+	}
+	switch netgearErr.Type {
+	case netgear.ErrorTypeAuth:
+		return exitCodeAuthError
+	case netgear.ErrorTypeNetwork:
+		return exitCodeNetworkError
+	case netgear.ErrorTypeModel:
+		return exitCodeModelError
+	case netgear.ErrorTypeParsing:
+		return exitCodeParsingError
+	case netgear.ErrorTypeOperation:
+		return exitCodeOperationError
+	default:
+		return exitCodeGeneralError
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"ntgrrc/pkg/netgear"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestExitCodeForError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"no error", nil, exitCodeOK},
+		{"plain error", errors.New("boom"), exitCodeGeneralError},
+		{"auth error", netgear.NewAuthError("no session", nil), exitCodeAuthError},
+		{"network error", netgear.NewNetworkError("request failed", nil), exitCodeNetworkError},
+		{"model error", netgear.NewModelError("unsupported", nil), exitCodeModelError},
+		{"parsing error", netgear.NewParsingError("bad page", nil), exitCodeParsingError},
+		{"wrapped error", fmt.Errorf("context: %w", netgear.NewAuthError("expired", nil)), exitCodeAuthError},
+		{"threshold exceeded", fmt.Errorf("%w: 95%% used", errPoeBudgetThresholdExceeded), exitCodeThresholdExceeded},
+		{"host resolution error", netgear.NewNetworkError("request failed", netgear.ErrHostResolution), exitCodeNetworkError},
+		{"insufficient privileges", netgear.ErrInsufficientPrivileges, exitCodeAuthError},
+		{"changes not applied", errChangesNotApplied, exitCodeNotApplied},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			then.AssertThat(t, exitCodeForError(test.err), is.EqualTo(test.expected))
+		})
+	}
+}
+
+func TestRunWithoutSessionReturnsAuthExitCode(t *testing.T) {
+	tokenDir := t.TempDir()
+
+	exitCode := run([]string{"poe", "status", "--address", "localhost:1", "--token-dir", tokenDir})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeAuthError))
+}
+
+func TestRunWithVerboseAndQuietFails(t *testing.T) {
+	exitCode := run([]string{"--verbose", "--quiet", "version"})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeGeneralError))
+}
+
+func TestRunWriteWithoutAdminPrivilegesReturnsAuthExitCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/iss/specific/interface.html" {
+			return
+		}
+		if r.Method == http.MethodPost {
+			// the firmware answers with the config page again, rendered without writable controls
+			_, _ = w.Write([]byte(`<form method="post"><input type="text" name="PORT_NAME" value="ap" disabled></form>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<table><tr><th>Port</th><th>Name</th></tr><tr><td>5</td><td>ap</td></tr></table>`))
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, "token", GS316EP)
+
+	exitCode := run([]string{"--token-dir", tokenDir, "port", "describe", "--address", host, "--port", "5", "lobby AP"})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeAuthError))
+}
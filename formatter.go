@@ -1,8 +1,79 @@
 package main
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
 type OutputFormat string
 
 const (
 	MarkdownFormat OutputFormat = "md"
 	JsonFormat     OutputFormat = "json"
 )
+
+// validateFields returns an error listing the valid field names, if any of the fields is unknown
+func validateFields(validFields []string, fields []string) error {
+	for _, field := range fields {
+		if !slices.Contains(validFields, field) {
+			return errors.New(fmt.Sprintf("unknown field '%s'; valid fields: %s", field, strings.Join(validFields, ", ")))
+		}
+	}
+	return nil
+}
+
+// selectColumns keeps the columns of the given fields only, in the requested order.
+// fieldNames name the table's columns; without any fields, the table is returned unchanged.
+func selectColumns(fieldNames []string, header []string, content [][]string, fields []string) ([]string, [][]string) {
+	if len(fields) == 0 {
+		return header, content
+	}
+
+	var columns []int
+	for _, field := range fields {
+		if i := slices.Index(fieldNames, field); i >= 0 {
+			columns = append(columns, i)
+		}
+	}
+
+	selectedHeader := make([]string, len(columns))
+	for i, column := range columns {
+		selectedHeader[i] = header[column]
+	}
+	selectedContent := make([][]string, len(content))
+	for r, row := range content {
+		selectedContent[r] = make([]string, len(columns))
+		for i, column := range columns {
+			if column < len(row) {
+				selectedContent[r][i] = row[column]
+			}
+		}
+	}
+	return selectedHeader, selectedContent
+}
+
+// tableColumn is a column of a table, its field names it for --fields
+type tableColumn struct {
+	field  string
+	header string
+}
+
+// fieldsOf returns the field names of the columns, in their order
+func fieldsOf(columns []tableColumn) []string {
+	fields := make([]string, len(columns))
+	for i, column := range columns {
+		fields[i] = column.field
+	}
+	return fields
+}
+
+// headerOf returns the table header of the columns, in their order
+func headerOf(columns []tableColumn) []string {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.header
+	}
+	return header
+}
@@ -6,6 +6,23 @@ import (
 )
 
 func printJsonDataTable(item string, header []string, content [][]string) {
+	// Create the final structure
+	result := map[string][]map[string]string{
+		item: jsonDataItems(header, content),
+	}
+	printJson(result)
+}
+
+// printJsonDataTableWithSummary prints the table like printJsonDataTable, with an additional "summary" object
+func printJsonDataTableWithSummary(item string, header []string, content [][]string, summary any) {
+	result := map[string]any{
+		item:      jsonDataItems(header, content),
+		"summary": summary,
+	}
+	printJson(result)
+}
+
+func jsonDataItems(header []string, content [][]string) []map[string]string {
 	// Create slice of maps for proper JSON structure
 	var items []map[string]string
 	
@@ -21,12 +38,10 @@ func printJsonDataTable(item string, header []string, content [][]string) {
 		}
 		items = append(items, rowData)
 	}
-	
-	// Create the final structure
-	result := map[string][]map[string]string{
-		item: items,
-	}
-	
+	return items
+}
+
+func printJson(result any) {
 	// Use proper JSON marshaling with indentation to handle escaping
 	jsonData, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
@@ -2,9 +2,17 @@ package main
 
 import (
 	"fmt"
+	"golang.org/x/term"
+	"os"
 	"strings"
 )
 
+// markdownTableWidth limits the width of printed markdown tables, 0 keeps the full width
+var markdownTableWidth = 0
+
+// minColumnWidth is the width columns are never truncated below
+const minColumnWidth = 4
+
 func printMarkdownTable(header []string, content [][]string) {
 	var lengths = make([]int, len(header))
 	for i, h := range header {
@@ -17,6 +25,16 @@ func printMarkdownTable(header []string, content [][]string) {
 		}
 	}
 
+	if markdownTableWidth > 0 {
+		shrinkColumnsToWidth(lengths, markdownTableWidth)
+		header = truncateRow(header, lengths)
+		truncated := make([][]string, len(content))
+		for i, row := range content {
+			truncated[i] = truncateRow(row, lengths)
+		}
+		content = truncated
+	}
+
 	line := strings.Builder{}
 
 	line.WriteString("|")
@@ -48,3 +66,51 @@ func printMarkdownTable(header []string, content [][]string) {
 	}
 
 }
+
+// shrinkColumnsToWidth narrows the widest columns, until the table fits into the width
+func shrinkColumnsToWidth(lengths []int, width int) {
+	tableWidth := 1 // the leading |
+	for _, l := range lengths {
+		tableWidth += l + 3 // one space as prefix and suffix, plus |
+	}
+
+	for tableWidth > width {
+		widest := 0
+		for i, l := range lengths {
+			if l > lengths[widest] {
+				widest = i
+			}
+		}
+		if lengths[widest] <= minColumnWidth {
+			return
+		}
+		lengths[widest]--
+		tableWidth--
+	}
+}
+
+// truncateRow shortens all values longer than their column, ending them with an ellipsis
+func truncateRow(row []string, lengths []int) []string {
+	truncated := make([]string, len(row))
+	for i, value := range row {
+		runes := []rune(value)
+		if len(runes) > lengths[i] {
+			value = string(runes[:lengths[i]-1]) + "…"
+		}
+		truncated[i] = value
+	}
+	return truncated
+}
+
+// terminalTableWidth returns the width of the terminal, or 0 if the output isn't a terminal but e.g. a pipe
+func terminalTableWidth() int {
+	fd := int(os.Stdout.Fd())
+	if !term.IsTerminal(fd) {
+		return 0
+	}
+	width, _, err := term.GetSize(fd)
+	if err != nil {
+		return 0
+	}
+	return width
+}
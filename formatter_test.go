@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"io"
 	"os"
 	"strings"
 	"testing"
@@ -369,12 +370,70 @@ func captureOutput(f func()) string {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
+	// read concurrently, so output larger than the pipe buffer doesn't block f
+	outputC := make(chan string)
+	go func() {
+		output, _ := io.ReadAll(r)
+		outputC <- string(output)
+	}()
+
 	f()
 
 	w.Close()
-	output := make([]byte, 4096)
-	n, _ := r.Read(output)
 	os.Stdout = oldStdout
 
-	return string(output[:n])
-}
\ No newline at end of file
+	return <-outputC
+}
+
+func TestPrintMarkdownTableWithinWidth(t *testing.T) {
+	header := []string{"Port ID", "Port Name", "Speed"}
+	content := [][]string{
+		{"1", "uplink to the core switch in the basement server room", "Auto"},
+		{"2", "camera", "Auto"},
+	}
+
+	t.Run("full width by default", func(t *testing.T) {
+		output := captureOutput(func() {
+			printMarkdownTable(header, content)
+		})
+
+		then.AssertThat(t, strings.Contains(output, "| 1       | uplink to the core switch in the basement server room | Auto  |"), is.True())
+	})
+
+	t.Run("compact truncates long cells", func(t *testing.T) {
+		markdownTableWidth = 40
+		defer func() { markdownTableWidth = 0 }()
+
+		output := captureOutput(func() {
+			printMarkdownTable(header, content)
+		})
+
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		then.AssertThat(t, lines[0], is.EqualTo("| Port ID | Port Name          | Speed |"))
+		then.AssertThat(t, lines[2], is.EqualTo("| 1       | uplink to the cor… | Auto  |"))
+		then.AssertThat(t, lines[3], is.EqualTo("| 2       | camera             | Auto  |"))
+		for _, line := range lines {
+			then.AssertThat(t, len([]rune(line)) <= 40, is.True())
+		}
+	})
+}
+
+func TestNoColorWhenPiped(t *testing.T) {
+	tokenDir := t.TempDir()
+
+	output := captureOutput(func() {
+		run([]string{"poe", "status", "--address", "localhost:1", "--token-dir", tokenDir})
+	})
+
+	then.AssertThat(t, output, is.StringContaining("Error: "))
+	then.AssertThat(t, output, is.Not(is.StringContaining("\033[")))
+}
+
+func TestColorize(t *testing.T) {
+	r, w, _ := os.Pipe()
+	defer r.Close()
+	defer w.Close()
+
+	then.AssertThat(t, colorEnabled(w), is.False())
+	then.AssertThat(t, colorize(w, ansiRed, "Error:"), is.EqualTo("Error:"))
+}
@@ -1,12 +1,33 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"ntgrrc/pkg/netgear"
 	"strings"
 )
 
+// httpClient returns a client bounded by the --timeout flag, so a hung switch doesn't hang the CLI forever
+func httpClient(args *GlobalOptions) *http.Client {
+	return &http.Client{Timeout: args.Timeout}
+}
+
+// newRequestError wraps a failed HTTP request into a network error, telling timeouts and unknown host names apart
+func newRequestError(args *GlobalOptions, message string, err error) error {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && !dnsErr.IsTimeout && !dnsErr.IsTemporary {
+		return netgear.NewNetworkError(message, fmt.Errorf("%w: %w", netgear.ErrHostResolution, err))
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return netgear.NewNetworkError(fmt.Sprintf("%s: no response within %s", message, args.Timeout), err)
+	}
+	return netgear.NewNetworkError(message, err)
+}
+
 func requestPage(args *GlobalOptions, host string, url string) (string, error) {
 	return doHttpRequestAndReadResponse(args, http.MethodGet, host, url, "")
 }
@@ -47,10 +68,9 @@ func doHttpRequestAndReadResponse(args *GlobalOptions, httpMethod string, host s
 		panic("model not supported")
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := httpClient(args).Do(req)
 	if err != nil {
-		return "", err
+		return "", newRequestError(args, "request failed", err)
 	}
 	defer resp.Body.Close()
 	if args.Verbose {
@@ -70,10 +90,9 @@ func doUnauthenticatedHttpRequestAndReadResponse(args *GlobalOptions, httpMethod
 		return "", err
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := httpClient(args).Do(req)
 	if err != nil {
-		return "", err
+		return "", newRequestError(args, "request failed", err)
 	}
 	defer resp.Body.Close()
 	if args.Verbose {
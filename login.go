@@ -2,22 +2,32 @@ package main
 
 import (
 	"crypto/md5"
-	"errors"
+	"encoding/json"
 	"fmt"
 	"github.com/PuerkitoBio/goquery"
 	"golang.org/x/term"
 	"io"
 	"math"
 	"net/http"
+	"ntgrrc/pkg/netgear"
 	"strings"
 	"syscall"
 )
 
 const FailedAttempt = "no SID cookie found in response header"
 
+// LoginResult is the JSON output of a successful login; the token itself is never printed
+type LoginResult struct {
+	Address       string `json:"address"`
+	Model         string `json:"model"`
+	Authenticated bool   `json:"authenticated"`
+	TokenStored   bool   `json:"token_stored"`
+}
+
 type LoginCommand struct {
 	Address  string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
 	Password string `optional:"" help:"the admin console's password; if omitted, it will be prompted for" short:"p"`
+	Model    string `optional:"" help:"the switch's model, e.g. GS308EP; if omitted, it will be detected" short:"m"`
 }
 
 func (login *LoginCommand) Run(args *GlobalOptions) error {
@@ -30,12 +40,19 @@ func (login *LoginCommand) Run(args *GlobalOptions) error {
 	}
 
 	if len(login.Password) < 1 {
-		return errors.New("no password given")
+		return netgear.NewAuthError("no password given", nil)
 	}
 
-	model, err := detectNetgearModel(args, login.Address)
-	if err != nil {
-		return err
+	model := NetgearModel(login.Model)
+	if len(login.Model) > 0 && !isSupportedModel(login.Model) {
+		return netgear.NewModelError(fmt.Sprintf("model '%s' not supported", login.Model), nil)
+	}
+	if len(model) == 0 {
+		var err error
+		model, err = detectNetgearModel(args, login.Address)
+		if err != nil {
+			return err
+		}
 	}
 	args.model = model
 
@@ -51,6 +68,18 @@ func (login *LoginCommand) Run(args *GlobalOptions) error {
 		return err
 	}
 
+	if args.OutputFormat == JsonFormat {
+		jsonData, err := json.MarshalIndent(LoginResult{
+			Address:       login.Address,
+			Model:         string(args.model),
+			Authenticated: true,
+			TokenStored:   true,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonData))
+	}
 	return nil
 }
 
@@ -69,7 +98,7 @@ func doLogin(args *GlobalOptions, host string, encryptedPwd string) error {
 	} else if isModel316(args.model) {
 		url = fmt.Sprintf("http://%s/redirect.html", host)
 	} else {
-		return errors.New("Unknown model not supported, please contact the developers ")
+		return netgear.NewModelError("Unknown model not supported, please contact the developers ", nil)
 	}
 	if args.Verbose {
 		fmt.Println("login attempt: " + url)
@@ -82,10 +111,15 @@ func doLogin(args *GlobalOptions, host string, encryptedPwd string) error {
 		formData = "LoginPassword=" + encryptedPwd
 	}
 
-	resp, err := http.Post(url, "application/x-www-form-urlencoded", strings.NewReader(formData))
+	req, err := http.NewRequestWithContext(args.context(), http.MethodPost, url, strings.NewReader(formData))
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := httpClient(args).Do(req)
+	if err != nil {
+		return newRequestError(args, "login request failed", err)
+	}
 	defer resp.Body.Close()
 	if args.Verbose {
 		fmt.Println(resp.Status)
@@ -99,14 +133,14 @@ func doLogin(args *GlobalOptions, host string, encryptedPwd string) error {
 	if isModel30x(args.model) {
 		token = getSessionToken(resp)
 		if token == FailedAttempt && resp.StatusCode == http.StatusOK {
-			return errors.New("login request returned 200 OK, but response did not contain a session token ('SID' cookie). " +
-				"this is known behaviour from the switch. please, wait some minutes and tray again later")
+			return netgear.NewAuthError("login request returned 200 OK, but response did not contain a session token ('SID' cookie). "+
+				"this is known behaviour from the switch. please, wait some minutes and tray again later", nil)
 		}
 	}
 	if isModel316(args.model) {
 		token = findGambitTokenInResponseHtml(strings.NewReader(string(body)))
 		if token == FailedAttempt && resp.StatusCode == http.StatusOK {
-			return errors.New("login request returned 200 OK, but response did not contain a token ('Gambit' value in input field) ")
+			return netgear.NewAuthError("login request returned 200 OK, but response did not contain a token ('Gambit' value in input field) ", nil)
 		}
 	}
 
@@ -156,22 +190,33 @@ func findGambitTokenInResponseHtml(reader io.Reader) (gambitToken string) {
 	return gambitToken
 }
 
+// loginPageUrl returns the page holding the seed value for encrypting the password
+func loginPageUrl(model NetgearModel, host string) (string, error) {
+	if isModel30x(model) {
+		return fmt.Sprintf("http://%s/login.cgi", host), nil
+	}
+	if isModel316(model) {
+		return fmt.Sprintf("http://%s/wmi/login", host), nil
+	}
+	return "", netgear.NewModelError("Unknown model not supported, please contact the developers ", nil)
+}
+
 func getSeedValueFromSwitch(args *GlobalOptions, host string) (string, error) {
-	var url string
-	if isModel30x(args.model) {
-		url = fmt.Sprintf("http://%s/login.cgi", host)
-	} else if isModel316(args.model) {
-		url = fmt.Sprintf("http://%s/wmi/login", host)
-	} else {
-		return "", errors.New("Unknown model not supported, please contact the developers ")
+	url, err := loginPageUrl(args.model, host)
+	if err != nil {
+		return "", err
 	}
 	if args.Verbose {
 		fmt.Println("fetch seed value from: " + url)
 	}
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(args.context(), http.MethodGet, url, nil)
 	if err != nil {
 		return "", err
 	}
+	resp, err := httpClient(args).Do(req)
+	if err != nil {
+		return "", newRequestError(args, "failed to fetch login page", err)
+	}
 	if args.Verbose {
 		fmt.Println(resp.Status)
 	}
@@ -194,8 +239,8 @@ func findSeedValueInLoginHtml(reader io.Reader) (string, error) {
 	if exists {
 		return randVal, nil
 	}
-	return "", errors.New("random seed value not found in login.cgi response. " +
-		"An element with id=rand and an attribute 'value' is expected")
+	return "", netgear.NewParsingError("random seed value not found in login.cgi response. "+
+		"An element with id=rand and an attribute 'value' is expected", nil)
 }
 
 // encryptPassword re-implements some logic from Netgear's GS305EP frontend component, see login.js
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/corbym/gocrest/is"
 	"github.com/corbym/gocrest/then"
+	"ntgrrc/pkg/netgear"
 )
 
 func TestGetSeedValueFromLogin(t *testing.T) {
@@ -54,3 +61,74 @@ func TestFindGambitTokenInResponseHtml(t *testing.T) {
 	then.AssertThat(t, gambit, is.EqualTo("chpbfghbcadbaamekjof"))
 }
 
+
+func TestLoginTimesOutOnNeverRespondingSwitch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	args := createTestGlobalOptions(false, true, MarkdownFormat)
+	args.Timeout = 200 * time.Millisecond
+	login := LoginCommand{Address: strings.TrimPrefix(server.URL, "http://"), Password: "secret", Model: string(GS308EP)}
+
+	start := time.Now()
+	err := login.Run(args)
+
+	then.AssertThat(t, time.Since(start) < 2*time.Second, is.True())
+	var netgearErr *netgear.Error
+	then.AssertThat(t, errors.As(err, &netgearErr), is.True())
+	then.AssertThat(t, netgearErr.Type, is.EqualTo(netgear.ErrorTypeNetwork))
+	then.AssertThat(t, strings.Contains(err.Error(), "no response within 200ms"), is.True())
+}
+
+func TestLoginIsAbortedWhenContextIsCancelled(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login.cgi" && r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`<html><input type="hidden" id="rand" value="1234"></html>`))
+			return
+		}
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+	ctx, cancel := context.WithCancel(context.Background())
+	args := createTestGlobalOptions(false, true, MarkdownFormat)
+	args.Timeout = time.Minute
+	args.ctx = ctx
+	login := LoginCommand{Address: strings.TrimPrefix(server.URL, "http://"), Password: "secret", Model: string(GS308EP)}
+
+	time.AfterFunc(100*time.Millisecond, cancel)
+	start := time.Now()
+	err := login.Run(args)
+
+	then.AssertThat(t, time.Since(start) < 2*time.Second, is.True())
+	then.AssertThat(t, errors.Is(err, context.Canceled), is.True())
+}
+
+func TestLoginPrintsJsonResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wmi/login":
+			_, _ = w.Write([]byte(loadTestFile(string(GS316EP), "login.html")))
+		case "/redirect.html":
+			_, _ = w.Write([]byte(loadTestFile(string(GS316EP), "redirect.html")))
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", t.TempDir(), "-f", "json", "login", "--address", host, "--password", "secret", "--model", string(GS316EP)})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	var result LoginResult
+	then.AssertThat(t, json.Unmarshal([]byte(output), &result), is.Nil())
+	then.AssertThat(t, result, is.EqualTo(LoginResult{Address: host, Model: string(GS316EP), Authenticated: true, TokenStored: true}))
+	then.AssertThat(t, strings.Contains(output, "chpbfghbcadbaamekjof"), is.False())
+}
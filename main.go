@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/alecthomas/kong"
 	"os"
+	"os/signal"
+	"time"
 )
 
 type GlobalOptions struct {
@@ -11,47 +15,123 @@ type GlobalOptions struct {
 	Quiet        bool
 	OutputFormat OutputFormat
 	TokenDir     string
+	Timeout      time.Duration
 	model        NetgearModel
 	token        string
+	switchConfig SwitchConfig    // of the switch given with --address, empty if it isn't configured
+	switchesErr  error           // of a malformed default switches file, which only fails commands naming a port group
+	ctx          context.Context // cancelled by Ctrl-C, nil in tests creating the options directly
+}
+
+// context returns the context of the command, which aborts the requests on Ctrl-C
+func (args *GlobalOptions) context() context.Context {
+	if args.ctx == nil {
+		return context.Background()
+	}
+	return args.ctx
 }
 
 var cli struct {
-	HelpAll      HelpAllFlag  `help:"advanced/full help"`
-	Verbose      bool         `help:"verbose log messages" short:"v"`
-	Debug        bool         `help:"debug output (alias for verbose)" short:"d"`
-	Quiet        bool         `help:"no log messages" short:"q"`
-	OutputFormat OutputFormat `help:"what output format to use [md, json]" enum:"md,json" default:"md" short:"f"`
-	TokenDir     string       `help:"directory to store login tokens" default:"" short:"t"`
-
-	Version   VersionCommand     `cmd:"" name:"version" help:"show version"`
-	Login     LoginCommand       `cmd:"" name:"login" help:"create a session for further commands (requires admin console password)"`
-	Poe       PoeCommand         `cmd:"" name:"poe" help:"show POE status or change the configuration"`
-	Port      PortCommand        `cmd:"" name:"port" help:"show port status or change the configuration for a port"`
-	ShowDebug DebugReportCommand `cmd:"" name:"debug-report" help:"show information from the switch communication, useful for supporting development and bug fixes"`
+	HelpAll      HelpAllFlag   `help:"advanced/full help"`
+	Verbose      bool          `help:"verbose log messages" short:"v"`
+	Debug        bool          `help:"debug output (alias for verbose)" short:"d"`
+	Quiet        bool          `help:"no log messages" short:"q"`
+	OutputFormat OutputFormat  `help:"what output format to use [md, json]" enum:"md,json" default:"md" short:"f"`
+	ErrorFormat  string        `help:"how to print errors [text, json], json prints an object with the error's type, message and cause" enum:"text,json" default:"text"`
+	TokenDir     string        `help:"directory to store login tokens" default:"" short:"t"`
+	Switches     string        `help:"YAML file mapping switch names to addresses; defaults to ~/.config/ntgrrc/switches.yaml" default:""`
+	Timeout      time.Duration `help:"timeout for each HTTP request to the switch, e.g. 5s; 0 disables the timeout" default:"10s"`
+	Compact      bool          `help:"truncate long markdown table cells to fit the terminal width"`
+	TableWidth   int           `help:"truncate long markdown table cells to fit the given width, even when piping" default:"0"`
+	NoColor      bool          `help:"no colored output, which is also disabled by the NO_COLOR environment variable or when piping"`
+
+	Version      VersionCommand      `cmd:"" name:"version" help:"show version"`
+	Login        LoginCommand        `cmd:"" name:"login" help:"create a session for further commands (requires admin console password)"`
+	Tokens       TokensCommand       `cmd:"" name:"tokens" help:"list or remove the stored sessions"`
+	Capabilities CapabilitiesCommand `cmd:"" name:"capabilities" help:"show which features the switch model offers"`
+	Dashboard    DashboardCommand    `cmd:"" name:"dashboard" help:"show a summary of the switch: connected ports, POE consumption and alarms"`
+	Snapshot     SnapshotCommand     `cmd:"" name:"snapshot" help:"print the POE and port settings as JSON, to apply them later"`
+	Apply        ApplyCommand        `cmd:"" name:"apply" help:"apply the POE and port settings of a snapshot JSON file"`
+	SelfTest     SelfTestCommand     `cmd:"" name:"selftest" help:"run read-only operations against the switch and report which succeeded"`
+	Poe          PoeCommand          `cmd:"" name:"poe" help:"show POE status or change the configuration"`
+	Port         PortCommand         `cmd:"" name:"port" help:"show port status or change the configuration for a port"`
+	ShowDebug    DebugReportCommand  `cmd:"" name:"debug-report" help:"show information from the switch communication, useful for supporting development and bug fixes"`
+	RawPage      RawPageCommand      `cmd:"" name:"raw-page" help:"print the raw HTML of a switch page, useful for debugging parser issues"`
 }
 
 func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run parses the command line arguments, runs the selected command and returns the process exit code
+func run(args []string) int {
 	// If running without any extra arguments, default to the --help flag
-	if len(os.Args) < 2 {
-		os.Args = append(os.Args, "--help")
+	if len(args) < 1 {
+		args = append(args, "--help")
 	}
 
-	options := kong.Parse(&cli,
+	parser, err := kong.New(&cli,
 		kong.UsageOnError(),
 		kong.ConfigureHelp(kong.HelpOptions{
 			Compact:             true,
 			NoExpandSubcommands: true,
 		}),
 	)
+	if err != nil {
+		panic(err)
+	}
+	options, err := parser.Parse(args)
+	parser.FatalIfErrorf(err)
+	noColor = cli.NoColor
+	if (cli.Verbose || cli.Debug) && cli.Quiet {
+		err = errors.New("--verbose and --quiet can't be combined")
+		printError(cli.ErrorFormat, err)
+		return exitCodeForError(err)
+	}
+
+	switchesFilename := cli.Switches
+	if switchesFilename == "" {
+		switchesFilename = defaultSwitchesFilename()
+	}
+	switches, switchesErr := loadSwitchesConfig(switchesFilename)
+	if switchesErr != nil && cli.Switches != "" {
+		printError(cli.ErrorFormat, switchesErr)
+		return exitCodeForError(switchesErr)
+	}
+	if !cli.Quiet {
+		warnings := switchesEnvWarnings()
+		if switchesErr != nil {
+			// a broken default file only fails the commands, which need it
+			warnings = append(warnings, fmt.Sprintf("%v, its switch names and port groups are ignored", switchesErr))
+		}
+		for _, warning := range warnings {
+			fmt.Fprintln(os.Stderr, colorize(os.Stderr, ansiYellow, "WARN:")+" "+warning)
+		}
+	}
+	var switchConfig SwitchConfig
+	if selected := options.Selected(); selected != nil {
+		switchConfig = applySwitchConfig(selected.Target, switches)
+	}
+
+	markdownTableWidth = cli.TableWidth
+	if cli.Compact && markdownTableWidth == 0 {
+		markdownTableWidth = terminalTableWidth()
+	}
 
-	err := options.Run(&GlobalOptions{
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err = options.Run(&GlobalOptions{
 		Verbose:      cli.Verbose || cli.Debug, // Debug is an alias for verbose
 		Quiet:        cli.Quiet,
 		OutputFormat: cli.OutputFormat,
 		TokenDir:     cli.TokenDir,
+		Timeout:      cli.Timeout,
+		switchConfig: switchConfig,
+		switchesErr:  switchesErr,
+		ctx:          ctx,
 	})
 	if err != nil {
-		fmt.Printf("Error: %s\n", err.Error())
-		os.Exit(1)
+		printError(cli.ErrorFormat, err)
 	}
+	return exitCodeForError(err)
 }
@@ -1,10 +1,10 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"ntgrrc/pkg/netgear"
 	"strings"
 )
 
@@ -32,15 +32,39 @@ func isSupportedModel(modelName string) bool {
 	return isModel30x(NetgearModel(modelName)) || isModel316(NetgearModel(modelName))
 }
 
+// validatePortIds checks the port ids against the model's port count, before any request changes the switch
+func validatePortIds(model NetgearModel, portIds []int) error {
+	for _, portId := range portIds {
+		if err := netgear.Model(model).ValidatePortID(portId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePoePortIds checks the port ids against the model's number of POE ports
+func validatePoePortIds(model NetgearModel, portIds []int) error {
+	for _, portId := range portIds {
+		if err := netgear.Model(model).ValidatePOEPortID(portId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func detectNetgearModel(args *GlobalOptions, host string) (NetgearModel, error) {
 	url := fmt.Sprintf("http://%s/", host)
 	if args.Verbose {
 		fmt.Println("detecting Netgear switch model: " + url)
 	}
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(args.context(), http.MethodGet, url, nil)
 	if err != nil {
 		return "", err
 	}
+	resp, err := httpClient(args).Do(req)
+	if err != nil {
+		return "", newRequestError(args, "failed to connect to switch", err)
+	}
 	if args.Verbose {
 		fmt.Println(fmt.Sprintf("HTTP response code %d", resp.StatusCode))
 	}
@@ -54,7 +78,7 @@ func detectNetgearModel(args *GlobalOptions, host string) (NetgearModel, error)
 	}
 	model := detectNetgearModelFromResponse(string(responseBody))
 	if model == "" {
-		return "", errors.New("Can't auto-detect Netgear model from response. You may try using --model parameter ")
+		return "", netgear.NewModelError("Can't auto-detect Netgear model from response. You may try using --model parameter ", nil)
 	}
 	if args.Verbose {
 		fmt.Println(fmt.Sprintf("Detected model %s", model))
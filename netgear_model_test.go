@@ -42,3 +42,15 @@ func TestIsSupportedModel(t *testing.T) {
 
 	then.AssertThat(t, isSupportedModel("GS305EP"), is.True())
 }
+
+func TestValidatePortIds(t *testing.T) {
+	then.AssertThat(t, validatePortIds(GS305EP, []int{1, 5}), is.Nil())
+	then.AssertThat(t, validatePortIds(GS305EP, []int{1, 9}).Error(), is.EqualTo("operation error: port 9 out of range (GS305EP has 5 ports)"))
+	then.AssertThat(t, validatePortIds(GS316EPP, []int{16}), is.Nil())
+	then.AssertThat(t, validatePortIds(GS316EPP, []int{17}).Error(), is.EqualTo("operation error: port 17 out of range (GS316EPP has 16 ports)"))
+
+	then.AssertThat(t, validatePoePortIds(GS305EP, []int{4}), is.Nil())
+	then.AssertThat(t, validatePoePortIds(GS305EP, []int{5}), is.Not(is.Nil()))
+	then.AssertThat(t, validatePoePortIds(GS316EPP, []int{15}), is.Nil())
+	then.AssertThat(t, validatePoePortIds(GS316EPP, []int{16}), is.Not(is.Nil()))
+}
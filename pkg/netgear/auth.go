@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+
+	"ntgrrc/pkg/netgear/internal"
 )
 
 // TokenManager handles token persistence
@@ -97,7 +99,11 @@ func (m *FileTokenManager) GetToken(ctx context.Context, address string) (string
 		return "", "", NewAuthError("failed to read token file", err)
 	}
 
-	content := string(data)
+	return parseTokenFileContent(string(data))
+}
+
+// parseTokenFileContent splits the "model:token" content of a token file
+func parseTokenFileContent(content string) (string, Model, error) {
 	if content == "" {
 		return "", "", NewAuthError("token file is empty, please upgrade your token file", nil)
 	}
@@ -124,15 +130,20 @@ func (m *FileTokenManager) GetToken(ctx context.Context, address string) (string
 
 // StoreToken saves a token to file
 func (m *FileTokenManager) StoreToken(ctx context.Context, address string, token string, model Model) error {
+	content := fmt.Sprintf("%s:%s", string(model), token)
+	return m.writeTokenFile(address, []byte(content))
+}
+
+// writeTokenFile writes the content of the address' token file, readable by the user only
+func (m *FileTokenManager) writeTokenFile(address string, content []byte) error {
 	tokenDir := filepath.Join(m.dir, ".config", "ntgrrc")
 	if err := os.MkdirAll(tokenDir, 0755); err != nil {
 		return NewAuthError("failed to create token directory", err)
 	}
 
 	tokenFile := m.getTokenFilename(address)
-	content := fmt.Sprintf("%s:%s", string(model), token)
 
-	err := os.WriteFile(tokenFile, []byte(content), 0600)
+	err := os.WriteFile(tokenFile, content, 0600)
 	if err != nil {
 		return NewAuthError("failed to write token file", err)
 	}
@@ -181,4 +192,33 @@ func GetAuthenticationType(model Model) AuthenticationType {
 		return AuthTypeGambit
 	}
 	return AuthTypeSession
-}
\ No newline at end of file
+}
+
+// DetectAuthenticationType determines the authentication type from the switch's login page,
+// as firmware updates can change the scheme independent of the model.
+// It falls back to the model based default, if the page is inconclusive.
+func DetectAuthenticationType(loginPage string, model Model) AuthenticationType {
+	switch internal.DetectLoginAuthType(loginPage) {
+	case internal.LoginAuthGambit:
+		return AuthTypeGambit
+	case internal.LoginAuthSession:
+		return AuthTypeSession
+	}
+	return GetAuthenticationType(model)
+}
+
+// loginPath returns the path of the login page for the authentication type
+func (t AuthenticationType) loginPath() string {
+	if t == AuthTypeGambit {
+		return "/wmi/login"
+	}
+	return "/login.cgi"
+}
+
+// loginPostPath returns the path the login form of the authentication type is posted to
+func (t AuthenticationType) loginPostPath() string {
+	if t == AuthTypeGambit {
+		return "/redirect.html"
+	}
+	return "/login.cgi"
+}
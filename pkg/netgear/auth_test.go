@@ -0,0 +1,216 @@
+package netgear
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestDetectAuthenticationType(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		model    Model
+		expected AuthenticationType
+	}{
+		{"GS30x login page", "../../test-data/GS305EP/login.cgi.html", ModelGS305EP, AuthTypeSession},
+		{"Gambit login page", "../../test-data/GS316EP/login.html", ModelGS316EP, AuthTypeGambit},
+		{"30x model serving Gambit page", "../../test-data/GS316EP/login.html", ModelGS308EPP, AuthTypeGambit},
+		{"316 model serving GS30x page", "../../test-data/GS308EPP/login.cgi.html", ModelGS316EP, AuthTypeSession},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			content, err := os.ReadFile(test.file)
+			then.AssertThat(t, err, is.Nil())
+
+			then.AssertThat(t, DetectAuthenticationType(string(content), test.model), is.EqualTo(test.expected))
+		})
+	}
+}
+
+func TestDetectAuthenticationTypeFallsBackToModel(t *testing.T) {
+	page := `<html><body><input type="hidden" id="rand" value="1234"></body></html>`
+
+	then.AssertThat(t, DetectAuthenticationType(page, ModelGS305EP), is.EqualTo(AuthTypeSession))
+	then.AssertThat(t, DetectAuthenticationType(page, ModelGS316EPP), is.EqualTo(AuthTypeGambit))
+}
+
+func TestDetectAuthenticationTypeIgnoresGambitText(t *testing.T) {
+	page := `<html><body><form action="/login.cgi"><input name="password" type="hidden"></form>` +
+		`<p>Gambit tokens are not used by this firmware</p></body></html>`
+
+	then.AssertThat(t, DetectAuthenticationType(page, ModelGS316EP), is.EqualTo(AuthTypeSession))
+}
+
+func TestLoginWithDefaultSchemeFetchesLoginPageOnce(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("GET /", `<html><title>NETGEAR GS305EP</title></html>`)
+	mock.respond("GET /login.cgi", `<input type="hidden" id="rand" value="1234">`)
+	mock.handle("POST /login.cgi", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "SID=abc123")
+	})
+	client, err := NewClient(mock.URL(), WithEnvironmentAuth(false))
+	then.AssertThat(t, err, is.Nil())
+
+	err = client.Login(context.Background(), "password")
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, mock.requestsTo(http.MethodGet, "/login.cgi"), has.Length[mockRequest](1))
+}
+
+func TestLoginUsesGambitServedByGS30xModel(t *testing.T) {
+	loginPage, err := os.ReadFile("../../test-data/GS316EP/login.html")
+	then.AssertThat(t, err, is.Nil())
+	mock := newMockSwitch(t)
+	mock.respond("GET /", `<html><title>NETGEAR GS308EPP</title></html>`)
+	mock.respond("GET /login.cgi", string(loginPage))
+	mock.respond("GET /wmi/login", string(loginPage))
+	mock.respond("POST /redirect.html", `<script>var Gambit = "a1b2c3d4";</script>`)
+	mock.respond("GET /getPoePortStatus.cgi", `<ul><li class="poePortStatusListItem"><input type="hidden" class="port" value="1"></li></ul>`)
+
+	client, err := NewClient(mock.URL(), WithEnvironmentAuth(false))
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, client.GetModel(), is.EqualTo(ModelGS308EPP))
+	err = client.Login(context.Background(), "password")
+	then.AssertThat(t, err, is.Nil())
+	_, err = client.POE().GetStatus(context.Background())
+	then.AssertThat(t, err, is.Nil())
+
+	then.AssertThat(t, len(mock.requestsTo(http.MethodPost, "/redirect.html")), is.EqualTo(1))
+	requests := mock.requestsTo(http.MethodGet, "/getPoePortStatus.cgi")
+	then.AssertThat(t, requests[0].Query.Get("Gambit"), is.EqualTo("a1b2c3d4"))
+}
+
+func TestLoginWithOverriddenLoginPaths(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("GET /", `<html><title>NETGEAR GS305EP</title></html>`)
+	mock.respond("GET /login.cgi", `<html>not found</html>`)
+	mock.respond("GET /admin/login.htm", `<input type="hidden" id="rand" value="12345678">`)
+	mock.handle("POST /admin/signin.cgi", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostForm.Get("password") == "d1f4394e3e212ab4f06e08c54477a237" {
+			w.Header().Set("Set-Cookie", "SID=abc123")
+		}
+	})
+	client, err := NewClient(mock.URL(), WithEnvironmentAuth(false), WithLoginPaths("/admin/login.htm", "/admin/signin.cgi"))
+	then.AssertThat(t, err, is.Nil())
+
+	err = client.Login(context.Background(), "foobar")
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, client.IsAuthenticated(), is.True())
+	then.AssertThat(t, mock.requestsTo(http.MethodPost, "/admin/signin.cgi"), has.Length[mockRequest](1))
+	then.AssertThat(t, mock.requestsTo(http.MethodPost, "/login.cgi"), has.Length[mockRequest](0))
+}
+
+func TestVerifyCredentialsDiscardsToken(t *testing.T) {
+	loginPage, err := os.ReadFile("../../test-data/GS316EP/login.html")
+	then.AssertThat(t, err, is.Nil())
+	mock := newMockSwitch(t)
+	mock.respond("GET /", `<html><title>NETGEAR GS316EP</title></html>`)
+	mock.respond("GET /wmi/login", string(loginPage))
+	tokenMgr := NewMemoryTokenManager()
+	client, err := NewClient(mock.URL(), WithTokenManager(tokenMgr), WithEnvironmentAuth(false))
+	then.AssertThat(t, err, is.Nil())
+	seed, err := client.getSeedValue(context.Background(), "/wmi/login")
+	then.AssertThat(t, err, is.Nil())
+	mock.handle("POST /redirect.html", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostForm.Get("LoginPassword") == client.encryptPassword("secret", seed) {
+			_, _ = w.Write([]byte(`<script>var Gambit = "a1b2c3d4";</script>`))
+		}
+	})
+
+	err = client.VerifyCredentials(context.Background(), "secret")
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, client.IsAuthenticated(), is.False())
+	_, _, err = tokenMgr.GetToken(context.Background(), mock.URL())
+	then.AssertThat(t, err, is.Not(is.Nil()))
+
+	err = client.VerifyCredentials(context.Background(), "wrong")
+
+	then.AssertThat(t, errors.Is(err, ErrInvalidCredentials), is.True())
+	then.AssertThat(t, client.IsAuthenticated(), is.False())
+}
+
+func TestVerifyCredentialsAccountLocked(t *testing.T) {
+	tests := []struct {
+		model     Model
+		loginPath string
+		fixture   string
+	}{
+		{ModelGS305EP, "/login.cgi", "../../test-data/GS305EP/login.cgi_locked.html"},
+		{ModelGS316EP, "/redirect.html", "../../test-data/GS316EP/login_locked.html"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.model), func(t *testing.T) {
+			lockedPage, err := os.ReadFile(test.fixture)
+			then.AssertThat(t, err, is.Nil())
+			mock := newMockSwitch(t)
+			mock.respond("GET /", fmt.Sprintf(`<html><title>NETGEAR %s</title></html>`, test.model))
+			mock.respond("GET /login.cgi", `<input type="hidden" id="rand" value="1234">`)
+			mock.respond("GET /wmi/login", `<input type="hidden" id="rand" value="1234">`)
+			mock.respond("POST "+test.loginPath, string(lockedPage))
+			client, err := NewClient(mock.URL(), WithEnvironmentAuth(false))
+			then.AssertThat(t, err, is.Nil())
+
+			err = client.VerifyCredentials(context.Background(), "secret")
+
+			then.AssertThat(t, errors.Is(err, ErrAccountLocked), is.True())
+		})
+	}
+}
+
+func TestLoginShowsOtherFirmwareErrors(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("GET /", `<html><title>NETGEAR GS305EP</title></html>`)
+	mock.respond("GET /login.cgi", `<input type="hidden" id="rand" value="1234">`)
+	mock.respond("POST /login.cgi", `<script>alert("too many sessions are open")</script>`)
+	client, err := NewClient(mock.URL(), WithEnvironmentAuth(false))
+	then.AssertThat(t, err, is.Nil())
+
+	err = client.VerifyCredentials(context.Background(), "secret")
+
+	then.AssertThat(t, errors.Is(err, ErrAccountLocked), is.False())
+	then.AssertThat(t, err.Error(), is.StringContaining("too many sessions are open"))
+}
+
+func TestLoginEncryptsPasswordWithSeedOfProvider(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("GET /", `<html><title>NETGEAR GS305EP</title></html>`)
+	mock.handle("POST /login.cgi", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "SID=abc123")
+	})
+	fixedSeed := func(ctx context.Context) (string, error) { return "12345678", nil }
+	client, err := NewClient(mock.URL(), WithEnvironmentAuth(false), WithSeedProvider(fixedSeed))
+	then.AssertThat(t, err, is.Nil())
+
+	err = client.Login(context.Background(), "foobar")
+
+	then.AssertThat(t, err, is.Nil())
+	requests := mock.requestsTo(http.MethodPost, "/login.cgi")
+	then.AssertThat(t, requests[0].Form.Get("password"), is.EqualTo("d1f4394e3e212ab4f06e08c54477a237"))
+}
+
+func TestLoginFailsWithSeedProviderError(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("GET /", `<html><title>NETGEAR GS316EP</title></html>`)
+	failingSeed := func(ctx context.Context) (string, error) { return "", errors.New("no seed") }
+	client, err := NewClient(mock.URL(), WithEnvironmentAuth(false), WithSeedProvider(failingSeed))
+	then.AssertThat(t, err, is.Nil())
+
+	err = client.Login(context.Background(), "foobar")
+
+	var netgearErr *Error
+	then.AssertThat(t, errors.As(err, &netgearErr), is.True())
+	then.AssertThat(t, netgearErr.Type, is.EqualTo(ErrorTypeAuth))
+	then.AssertThat(t, mock.requestsTo(http.MethodPost, "/redirect.html"), has.Length[mockRequest](0))
+}
@@ -2,18 +2,25 @@ package netgear
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"ntgrrc/pkg/netgear/internal"
 )
 
-// Client represents a connection to a Netgear switch
+// Client represents a connection to a Netgear switch.
+// It is safe for concurrent use by multiple goroutines, also while logging in again.
 type Client struct {
 	address     string
+	mu          sync.RWMutex // guards model, token and authType, which change on Login and Logout
 	model       Model
 	httpClient  *internal.HTTPClient
 	token       string
@@ -21,11 +28,42 @@ type Client struct {
 	passwordMgr PasswordManager
 	detector    *internal.ModelDetector
 	verbose     bool
+	quiet       bool // no output at all, not even warnings, see WithQuiet
+	progress    ProgressFunc
+	clock       Clock
+	maxRetries  int
+	retryDelay  time.Duration
+	stats       requestStats
+	authType    AuthenticationType // detected from the login page, empty until Login
+	transport   http.RoundTripper  // nil for the default transport
+	autoLogin   bool               // log in with an environment password while constructing the client
+	fwWarning   WarningFunc        // nil to skip checking the firmware on Login, see WithFirmwareWarning
+	checkState  bool               // skip writes which don't change the state, see WithCheckBeforeWrite
+	budgetGuard bool               // refuse enabling POE beyond the power budget, see WithBudgetGuard
+	readBack    bool               // SetPortLimits verifies the applied limits, see WithLimitReadBack
+	cachedModel atomic.Bool        // the model comes from a cached token and wasn't checked against the switch yet
+	jsonWrites  atomic.Bool        // the GS316 firmware rejected a form-encoded write, send JSON instead
+	headers     map[string]string  // added to every request, see WithDefaultHeaders
+	hook        ResponseHook       // nil without capturing the responses, see WithResponseHook
+	basicUser   string             // HTTP Basic Auth user, empty without a proxy, see WithBasicAuth
+	basicPass   string             // HTTP Basic Auth password, see WithBasicAuth
+	seeds       SeedProvider       // nil to read the seed from the login page
+	seedPath    string             // login page with the seed, empty for the default of the authentication type
+	loginPost   string             // path the login is posted to, empty for the default of the authentication type
 }
 
 // ClientOption configures a Client
 type ClientOption func(*Client)
 
+// SeedProvider returns the seed the password is encrypted with before logging in
+type SeedProvider func(ctx context.Context) (string, error)
+
+// ProgressFunc is invoked after each port of a batch operation has been processed
+type ProgressFunc func(done, total int, current string)
+
+// WarningFunc receives warnings of the client, e.g. about untested firmware
+type WarningFunc func(warning string)
+
 // WithTokenManager sets a custom token manager
 func WithTokenManager(tm TokenManager) ClientOption {
 	return func(c *Client) {
@@ -55,6 +93,34 @@ func WithVerbose(verbose bool) ClientOption {
 	}
 }
 
+// WithQuiet silences all output of the client, i.e. verbose logging as well as warnings like the one
+// about untested firmware, e.g. for a CLI's --quiet flag
+func WithQuiet(quiet bool) ClientOption {
+	return func(c *Client) {
+		c.quiet = quiet
+	}
+}
+
+// WithBasicAuth adds HTTP Basic Auth to every request, for switches behind an authenticating reverse proxy.
+// The credentials are set after all options, so they also survive a later WithTimeout.
+func WithBasicAuth(user, pass string) ClientOption {
+	return func(c *Client) {
+		c.basicUser = user
+		c.basicPass = pass
+	}
+}
+
+// ResponseHook receives the method, URL, status code and body of every response the client reads,
+// with the session tokens redacted. It may be called from several goroutines at once.
+type ResponseHook func(method, url string, status int, body string)
+
+// WithResponseHook captures the raw responses, e.g. for a debug report, without verbose output
+func WithResponseHook(hook ResponseHook) ClientOption {
+	return func(c *Client) {
+		c.hook = hook
+	}
+}
+
 // WithPasswordManager sets a custom password manager
 func WithPasswordManager(pm PasswordManager) ClientOption {
 	return func(c *Client) {
@@ -73,6 +139,103 @@ func WithEnvironmentAuth(enabled bool) ClientOption {
 	}
 }
 
+// WithProgress sets a callback that reports progress of batched port operations
+func WithProgress(fn ProgressFunc) ClientOption {
+	return func(c *Client) {
+		c.progress = fn
+	}
+}
+
+// WithClock sets the clock used for retry backoff and time based validation
+func WithClock(clock Clock) ClientOption {
+	return func(c *Client) {
+		c.clock = clock
+	}
+}
+
+// WithRetry retries requests failing with network errors, doubling the delay after each attempt
+// Power cycles aren't retried, the switch may have run them before the connection failed.
+func WithRetry(maxRetries int, baseDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryDelay = baseDelay
+	}
+}
+
+// WithAutoLogin enables/disables logging in with an environment password in NewClient (enabled by default).
+// Disabled, NewClient only detects the model, and Login or LoginAuto must be called explicitly.
+func WithAutoLogin(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.autoLogin = enabled
+	}
+}
+
+// WithFirmwareWarning checks the firmware on Login and passes a warning to handler, if the switch runs
+// firmware which wasn't tested with this library, see CheckFirmwareCompatibility. Disabled by default,
+// as the check reads the dashboard on every Login, and skipped WithQuiet.
+func WithFirmwareWarning(handler WarningFunc) ClientOption {
+	return func(c *Client) {
+		c.fwWarning = handler
+	}
+}
+
+// WithCheckBeforeWrite makes POE EnablePort and DisablePort read the port's state first
+// and skip the write, if the port already is in the desired state (disabled by default)
+func WithCheckBeforeWrite(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.checkState = enabled
+	}
+}
+
+// WithBudgetGuard makes POE EnablePort check the power budget first and refuse with ErrInsufficientBudget,
+// if the port could draw more than remains, instead of over-subscribing the switch (disabled by default)
+func WithBudgetGuard(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.budgetGuard = enabled
+	}
+}
+
+// WithLimitReadBack makes SetPortLimits read the port's settings back after the update, and return
+// ErrRateLimitCoerced if the switch applied other limits than requested (disabled by default)
+func WithLimitReadBack(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.readBack = enabled
+	}
+}
+
+// WithDefaultHeaders adds the headers to every request, e.g. a header required by a corporate proxy.
+// They never replace headers the client sets for a request itself, like the session Cookie or Content-Type.
+func WithDefaultHeaders(headers map[string]string) ClientOption {
+	return func(c *Client) {
+		c.headers = headers
+	}
+}
+
+// WithTransport sets the HTTP transport, e.g. one shared by the clients of a Fleet
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// WithSeedProvider replaces reading the seed from the login page, e.g. with a fixed seed in tests
+// or for firmware serving the seed differently
+func WithSeedProvider(provider SeedProvider) ClientOption {
+	return func(c *Client) {
+		c.seeds = provider
+	}
+}
+
+// WithLoginPaths overrides the login page the seed is read from and the path the login is posted to,
+// for firmware revisions which moved them. An empty path keeps the default of the authentication type:
+// "/login.cgi" for both with session authentication, "/wmi/login" and "/redirect.html" with Gambit.
+func WithLoginPaths(seedPath, postPath string) ClientOption {
+	return func(c *Client) {
+		c.seedPath = seedPath
+		c.loginPost = postPath
+	}
+}
+
 // NewClient creates a new Netgear switch client
 func NewClient(address string, opts ...ClientOption) (*Client, error) {
 	client := &Client{
@@ -82,12 +245,31 @@ func NewClient(address string, opts ...ClientOption) (*Client, error) {
 		passwordMgr: NewEnvironmentPasswordManager(), // Default to environment password manager
 		detector:    internal.NewModelDetector(),
 		verbose:     false,
+		clock:       realClock{},
+		autoLogin:   true,
 	}
 
 	// Apply options (may override defaults)
 	for _, opt := range opts {
 		opt(client)
 	}
+	// quiet wins over verbose, in whichever order both are given
+	if client.quiet {
+		WithVerbose(false)(client)
+	}
+	// set after all options, since WithTimeout replaces the HTTP client
+	if client.transport != nil {
+		client.httpClient.SetTransport(client.transport)
+	}
+	if client.headers != nil {
+		client.httpClient.SetDefaultHeaders(client.headers)
+	}
+	if client.hook != nil {
+		client.httpClient.SetResponseHook(internal.ResponseHook(client.hook))
+	}
+	if client.basicUser != "" {
+		client.httpClient.SetBasicAuth(client.basicUser, client.basicPass)
+	}
 
 	// Try to load existing cached token first
 	ctx := context.Background()
@@ -95,6 +277,8 @@ func NewClient(address string, opts ...ClientOption) (*Client, error) {
 	if err == nil {
 		client.token = token
 		client.model = model
+		// checked on the first failing request, so constructing the client needs no request
+		client.cachedModel.Store(true)
 		if client.verbose {
 			fmt.Printf("Loaded existing token for model %s\n", model)
 		}
@@ -102,7 +286,7 @@ func NewClient(address string, opts ...ClientOption) (*Client, error) {
 	}
 
 	// No cached token, check for environment password and auto-authenticate
-	if client.passwordMgr != nil {
+	if client.passwordMgr != nil && client.autoLogin {
 		if config, found := client.passwordMgr.GetSwitchConfig(address); found {
 			// Always detect model from the actual switch (ignore config model)
 			model, err := client.detectModel(ctx)
@@ -140,12 +324,23 @@ func NewClient(address string, opts ...ClientOption) (*Client, error) {
 	return client, nil
 }
 
+// staleCachedModel re-detects the model of the switch, when a token was cached for the address.
+// It returns the detected model, if it is of another series than the cached one, else an empty model.
+// A failed detection keeps the cached token, the switch may just be unreachable for now.
+func (c *Client) staleCachedModel(ctx context.Context, cached Model) Model {
+	detected, err := c.detectModel(ctx)
+	if err != nil || detected.Series() == cached.Series() {
+		return ""
+	}
+	return detected
+}
+
 // detectModel attempts to detect the switch model by making a request to the root page
 func (c *Client) detectModel(ctx context.Context) (Model, error) {
 	// First try the root page
 	resp, err := c.httpClient.Get(ctx, "/", nil)
 	if err != nil {
-		return "", NewNetworkError("failed to connect to switch", err)
+		return "", newConnectionError("failed to connect to switch", err)
 	}
 
 	body, err := c.httpClient.ReadBody(resp)
@@ -182,6 +377,19 @@ func (c *Client) detectModel(ctx context.Context) (Model, error) {
 	return model, nil
 }
 
+// refineModel upgrades the generic GS30xEPx model using the dashboard page, which is only readable after login
+func (c *Client) refineModel(body string) {
+	model := Model(c.detector.DetectFromHTML(body))
+	if model.IsModel30x() && model != ModelGS30xEPx {
+		if c.verbose {
+			fmt.Printf("Refined model from %s to %s\n", c.GetModel(), model)
+		}
+		c.mu.Lock()
+		c.model = model
+		c.mu.Unlock()
+	}
+}
+
 // Login authenticates with the switch
 func (c *Client) Login(ctx context.Context, password string) error {
 	// If no password provided, try environment variables
@@ -201,28 +409,35 @@ func (c *Client) Login(ctx context.Context, password string) error {
 		}
 	}
 
-	// Perform authentication based on model type
-	var token string
-	var err error
-
-	authType := GetAuthenticationType(c.model)
-	switch authType {
-	case AuthTypeSession:
-		token, err = c.loginWithSession(ctx, password)
-	case AuthTypeGambit:
-		token, err = c.loginWithGambit(ctx, password)
-	default:
-		return NewAuthError(fmt.Sprintf("unsupported authentication type for model %s", c.model), nil)
-	}
-
+	token, authType, err := c.authenticate(ctx, password)
 	if err != nil {
 		return err
 	}
 
-	c.token = token
+	c.setSession(token, authType)
+	c.cachedModel.Store(false)
+
+	// The login page doesn't always name the exact model, the dashboard behind it does.
+	// It also shows the firmware version, to warn about untested firmware.
+	checkFirmware := c.fwWarning != nil && !c.quiet
+	if c.GetModel() == ModelGS30xEPx || checkFirmware {
+		body, err := c.readDashboardPage(ctx)
+		if err != nil {
+			if c.verbose {
+				fmt.Printf("Warning: failed to read the dashboard: %v\n", err)
+			}
+		} else {
+			if c.GetModel() == ModelGS30xEPx {
+				c.refineModel(body)
+			}
+			if checkFirmware {
+				c.warnUntestedFirmware(body)
+			}
+		}
+	}
 
 	// Store token for future use
-	err = c.tokenMgr.StoreToken(ctx, c.address, token, c.model)
+	err = c.tokenMgr.StoreToken(ctx, c.address, token, c.GetModel())
 	if err != nil {
 		// Log warning but don't fail login
 		if c.verbose {
@@ -233,15 +448,177 @@ func (c *Client) Login(ctx context.Context, password string) error {
 	return nil
 }
 
+// VerifyCredentials checks the password with a login handshake, but discards the token:
+// nothing is stored and the client stays unauthenticated. Useful for "test credentials" dialogs.
+// Returns ErrInvalidCredentials for a wrong password and ErrAccountLocked after too many attempts.
+//
+// The session, which the switch opens for a successful handshake, stays open until it times out on the
+// switch, as the library doesn't log out of the firmware. The switches allow only a few sessions at a
+// time, so don't verify the credentials in a loop.
+func (c *Client) VerifyCredentials(ctx context.Context, password string) error {
+	if password == "" {
+		return NewAuthError("password cannot be empty", nil)
+	}
+	_, _, err := c.authenticate(ctx, password)
+	return err
+}
+
+// authenticate performs the login handshake of the session's authentication type, the model's default unless
+// an earlier login detected another one, and returns the token. Only if that login fails, the login page is
+// inspected, as firmware updates can change the scheme independent of the model.
+func (c *Client) authenticate(ctx context.Context, password string) (string, AuthenticationType, error) {
+	authType := c.authenticationType()
+	token, err := c.loginWith(ctx, authType, password)
+	if err == nil || errors.Is(err, ErrAccountLocked) || isNetworkError(err) {
+		return token, authType, err
+	}
+
+	detected := c.detectAuthenticationType(ctx, authType)
+	if detected == authType {
+		return token, authType, err
+	}
+	token, err = c.loginWith(ctx, detected, password)
+	return token, detected, err
+}
+
+// loginWith performs the login handshake of the authentication type and returns the token
+func (c *Client) loginWith(ctx context.Context, authType AuthenticationType, password string) (string, error) {
+	switch authType {
+	case AuthTypeSession:
+		return c.loginWithSession(ctx, password)
+	case AuthTypeGambit:
+		return c.loginWithGambit(ctx, password)
+	}
+	return "", NewAuthError(fmt.Sprintf("unsupported authentication type for model %s", c.GetModel()), nil)
+}
+
 // LoginAuto performs automatic authentication using environment variables
 func (c *Client) LoginAuto(ctx context.Context) error {
 	return c.Login(ctx, "") // Empty password triggers environment variable lookup
 }
 
+// Refresh re-detects the model and checks with a read of the dashboard, that the switch still accepts
+// the session. Long-running programs call it periodically, as the switch may reboot or be replaced.
+// A rejected session, or one lost with a replaced model, is renewed with the environment password;
+// without one, ErrSessionExpired is returned. Failed requests are returned as they are, keeping the session.
+func (c *Client) Refresh(ctx context.Context) error {
+	detected, err := c.detectModel(ctx)
+	if err != nil {
+		return NewModelError("failed to detect switch model", err)
+	}
+	c.cachedModel.Store(false)
+	current := c.GetModel()
+	// the root page of GS30x only names the generic model, which the dashboard refined at login
+	if detected != current && !(detected == ModelGS30xEPx && current.IsModel30x()) {
+		if c.verbose {
+			fmt.Printf("Switch model changed from %s to %s\n", current, detected)
+		}
+		c.mu.Lock()
+		c.model = detected
+		c.mu.Unlock()
+		if detected.Series() != current.Series() {
+			c.setSession("", "")
+			_ = c.tokenMgr.DeleteToken(ctx, c.address)
+		}
+	}
+
+	if c.IsAuthenticated() {
+		body, err := c.readDashboardPage(ctx)
+		if err != nil {
+			return err
+		}
+		if !isSessionRejected(body) {
+			return nil
+		}
+		if c.verbose {
+			fmt.Printf("Session of %s was rejected\n", c.address)
+		}
+		c.setSession("", "")
+	}
+
+	if c.passwordMgr == nil {
+		return ErrSessionExpired
+	}
+	if _, found := c.passwordMgr.GetSwitchConfig(c.address); !found {
+		return ErrSessionExpired
+	}
+	return c.Login(ctx, "")
+}
+
+// isSessionRejected reports whether the firmware answered a page with its login page, or nothing at all,
+// as it does for an expired or unknown session
+func isSessionRejected(body string) bool {
+	return len(strings.TrimSpace(body)) < 10 || internal.IsLoginPage(body)
+}
+
+// detectAuthenticationType inspects the login page of the authentication type, which failed to log in,
+// for the scheme its form uses; it returns the failed type, if the page doesn't tell
+func (c *Client) detectAuthenticationType(ctx context.Context, failed AuthenticationType) AuthenticationType {
+	seedPath, _ := c.loginPaths(failed)
+	resp, err := c.httpClient.Get(ctx, seedPath, nil)
+	if err != nil {
+		return failed
+	}
+	body, err := c.httpClient.ReadBody(resp)
+	if err != nil {
+		return failed
+	}
+
+	authType := failed
+	switch internal.DetectLoginAuthType(body) {
+	case internal.LoginAuthGambit:
+		authType = AuthTypeGambit
+	case internal.LoginAuthSession:
+		authType = AuthTypeSession
+	}
+	if authType != failed && c.verbose {
+		fmt.Printf("Login page of %s uses %s authentication\n", c.GetModel(), authType)
+	}
+	return authType
+}
+
+// loginPaths returns the login page with the seed and the path the login is posted to,
+// the defaults of the authentication type unless overridden WithLoginPaths
+func (c *Client) loginPaths(authType AuthenticationType) (seedPath, postPath string) {
+	seedPath, postPath = authType.loginPath(), authType.loginPostPath()
+	if c.seedPath != "" {
+		seedPath = c.seedPath
+	}
+	if c.loginPost != "" {
+		postPath = c.loginPost
+	}
+	return seedPath, postPath
+}
+
+// authenticationType returns the authentication type detected on login, or the model based default
+func (c *Client) authenticationType() AuthenticationType {
+	_, authType := c.session()
+	return authType
+}
+
+// session returns the token and the authentication type, consistent with each other while Login runs concurrently
+func (c *Client) session() (string, AuthenticationType) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.authType != "" {
+		return c.token, c.authType
+	}
+	return c.token, GetAuthenticationType(c.model)
+}
+
+// setSession replaces the session, an empty token ends it
+func (c *Client) setSession(token string, authType AuthenticationType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.authType = authType
+}
+
 // loginWithSession performs session-based authentication (30x series)
 func (c *Client) loginWithSession(ctx context.Context, password string) (string, error) {
 	// Step 1: Get seed value from login page
-	seedValue, err := c.getSeedValue(ctx, "/login.cgi")
+	seedPath, postPath := c.loginPaths(AuthTypeSession)
+	seedValue, err := c.getSeedValue(ctx, seedPath)
 	if err != nil {
 		return "", NewAuthError("failed to get seed value", err)
 	}
@@ -254,15 +631,18 @@ func (c *Client) loginWithSession(ctx context.Context, password string) (string,
 	data.Set("password", encryptedPassword)
 
 	// Step 4: Make login request
-	resp, err := c.httpClient.Post(ctx, "/login.cgi", data, nil)
+	resp, err := c.httpClient.Post(ctx, postPath, data, nil)
 	if err != nil {
-		return "", NewNetworkError("login request failed", err)
+		return "", newConnectionError("login request failed", err)
 	}
 
 	// Step 5: Extract session token from response headers
 	token := c.extractSessionToken(resp)
 	if token == "" {
 		body, _ := c.httpClient.ReadBody(resp)
+		if isAccountLockedMessage(internal.ExtractLoginErrorMessage(body)) {
+			return "", ErrAccountLocked
+		}
 		if errorMsg := internal.ExtractErrorMessage(body); errorMsg != "" {
 			return "", NewAuthError(fmt.Sprintf("login failed: %s", errorMsg), nil)
 		}
@@ -275,7 +655,8 @@ func (c *Client) loginWithSession(ctx context.Context, password string) (string,
 // loginWithGambit performs Gambit-based authentication (316 series)
 func (c *Client) loginWithGambit(ctx context.Context, password string) (string, error) {
 	// Step 1: Get seed value from login page
-	seedValue, err := c.getSeedValue(ctx, "/wmi/login")
+	seedPath, postPath := c.loginPaths(AuthTypeGambit)
+	seedValue, err := c.getSeedValue(ctx, seedPath)
 	if err != nil {
 		return "", NewAuthError("failed to get seed value", err)
 	}
@@ -288,9 +669,9 @@ func (c *Client) loginWithGambit(ctx context.Context, password string) (string,
 	data.Set("LoginPassword", encryptedPassword)
 
 	// Step 4: Make login request to correct endpoint
-	resp, err := c.httpClient.Post(ctx, "/redirect.html", data, nil)
+	resp, err := c.httpClient.Post(ctx, postPath, data, nil)
 	if err != nil {
-		return "", NewNetworkError("gambit login request failed", err)
+		return "", newConnectionError("gambit login request failed", err)
 	}
 
 	body, err := c.httpClient.ReadBody(resp)
@@ -301,6 +682,9 @@ func (c *Client) loginWithGambit(ctx context.Context, password string) (string,
 	// Step 5: Extract Gambit token from response body
 	token := internal.ExtractGambitToken(body)
 	if token == "" {
+		if isAccountLockedMessage(internal.ExtractLoginErrorMessage(body)) {
+			return "", ErrAccountLocked
+		}
 		if errorMsg := internal.ExtractErrorMessage(body); errorMsg != "" {
 			return "", NewAuthError(fmt.Sprintf("gambit login failed: %s", errorMsg), nil)
 		}
@@ -310,13 +694,26 @@ func (c *Client) loginWithGambit(ctx context.Context, password string) (string,
 	return token, nil
 }
 
+// accountLockedMessages are the login errors, which the firmware shows after too many failed attempts
+var accountLockedMessages = []string{
+	"The maximum number of attempts has been reached. Wait a few minutes and then try again.",
+}
+
+// isAccountLockedMessage reports whether a login error of the firmware means the login is blocked
+func isAccountLockedMessage(message string) bool {
+	return slices.Contains(accountLockedMessages, message)
+}
+
 // IsAuthenticated returns true if the client has a valid token
 func (c *Client) IsAuthenticated() bool {
-	return c.token != ""
+	token, _ := c.session()
+	return token != ""
 }
 
 // GetModel returns the detected switch model
 func (c *Client) GetModel() Model {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.model
 }
 
@@ -325,6 +722,11 @@ func (c *Client) GetAddress() string {
 	return c.address
 }
 
+// Capabilities returns the features the switch model offers
+func (c *Client) Capabilities() Capabilities {
+	return c.GetModel().Capabilities()
+}
+
 // POE returns the POE management interface
 func (c *Client) POE() *POEManager {
 	return newPOEManager(c)
@@ -337,7 +739,7 @@ func (c *Client) Ports() *PortManager {
 
 // Logout clears the authentication token
 func (c *Client) Logout(ctx context.Context) error {
-	c.token = ""
+	c.setSession("", "")
 	
 	// Remove stored token
 	err := c.tokenMgr.DeleteToken(ctx, c.address)
@@ -350,47 +752,160 @@ func (c *Client) Logout(ctx context.Context) error {
 
 // makeAuthenticatedRequest makes an HTTP request with appropriate authentication
 func (c *Client) makeAuthenticatedRequest(ctx context.Context, method, path string, data url.Values) (string, error) {
-	if !c.IsAuthenticated() {
-		return "", ErrNotAuthenticated
+	req, err := c.authenticatedRequest(method, path, data)
+	if err != nil {
+		return "", err
+	}
+	return c.do(ctx, req)
+}
+
+// authenticatedRequest builds a request carrying the session token the way the authentication type expects it
+func (c *Client) authenticatedRequest(method, path string, data url.Values) (*request, error) {
+	token, authType := c.session()
+	if token == "" {
+		return nil, ErrNotAuthenticated
 	}
 
 	headers := make(map[string]string)
 
 	// Add authentication based on model type
-	authType := GetAuthenticationType(c.model)
 	switch authType {
 	case AuthTypeSession:
 		// Use session cookie
-		headers["Cookie"] = fmt.Sprintf("SID=%s", c.token)
+		headers["Cookie"] = fmt.Sprintf("SID=%s", token)
 	case AuthTypeGambit:
 		// Add Gambit parameter to URL
 		if data == nil {
 			data = url.Values{}
 		}
-		data.Set("Gambit", c.token)
+		data.Set("Gambit", token)
 	}
 
-	if method == "GET" {
-		if len(data) > 0 {
-			// Add query parameters for GET requests
-			path += "?" + data.Encode()
-		}
-		httpResp, err := c.httpClient.Get(ctx, path, headers)
-		if err != nil {
-			return "", NewNetworkError("GET request failed", err)
+	if method == "GET" && len(data) > 0 {
+		// Add query parameters for GET requests
+		path = withQuery(path, data)
+	}
+
+	return &request{method: method, path: path, data: data, headers: headers}, nil
+}
+
+// withQuery adds the parameters to the query of the path, merging them with a query the path already has,
+// e.g. the Gambit token with "/iss/specific/poePortStatus.html?GetData=TRUE"
+func withQuery(path string, params url.Values) string {
+	base, rawQuery, _ := strings.Cut(path, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		query = url.Values{}
+	}
+	for key, values := range params {
+		query[key] = values
+	}
+	return base + "?" + query.Encode()
+}
+
+// makeWriteRequest posts a configuration change. Switches answer writes of accounts without admin
+// privileges with the same page, just without writable form controls, which is reported as ErrInsufficientPrivileges.
+func (c *Client) makeWriteRequest(ctx context.Context, path string, data url.Values) (string, error) {
+	response, err := c.makeAuthenticatedRequest(ctx, "POST", path, data)
+	if err != nil {
+		return response, err
+	}
+	if internal.IsReadOnlyForm(response) {
+		return response, ErrInsufficientPrivileges
+	}
+	return response, nil
+}
+
+// makeActionRequest posts an action like a power cycle. Unlike a configuration change it isn't
+// idempotent, so it's never retried: the switch may have run it before the connection failed.
+func (c *Client) makeActionRequest(ctx context.Context, path string, data url.Values) (string, error) {
+	req, err := c.authenticatedRequest("POST", path, data)
+	if err != nil {
+		return "", err
+	}
+	req.once = true
+	response, err := c.do(ctx, req)
+	if err != nil {
+		return response, err
+	}
+	if internal.IsReadOnlyForm(response) {
+		return response, ErrInsufficientPrivileges
+	}
+	return response, nil
+}
+
+// makeGS316WriteRequest posts a configuration change to a GS316 config page. It's form encoded unless
+// the firmware answered a form with HTTP 415, then this and all following writes are sent as JSON.
+func (c *Client) makeGS316WriteRequest(ctx context.Context, path string, data url.Values) (string, error) {
+	if !c.jsonWrites.Load() {
+		response, err := c.makeWriteRequest(ctx, path, data)
+		if !errors.Is(err, ErrUnsupportedContentType) {
+			return response, err
 		}
-		return c.httpClient.ReadBody(httpResp)
-	} else {
-		httpResp, err := c.httpClient.Post(ctx, path, data, headers)
-		if err != nil {
-			return "", NewNetworkError("POST request failed", err)
+		c.jsonWrites.Store(true)
+	}
+	return c.makeJSONWriteRequest(ctx, path, data)
+}
+
+// makeJSONWriteRequest posts a configuration change as a JSON object with a string per form field
+func (c *Client) makeJSONWriteRequest(ctx context.Context, path string, data url.Values) (string, error) {
+	token, authType := c.session()
+	if token == "" {
+		return "", ErrNotAuthenticated
+	}
+
+	headers := make(map[string]string)
+	fields := make(map[string]string, len(data)+1)
+	for key := range data {
+		fields[key] = data.Get(key)
+	}
+	switch authType {
+	case AuthTypeSession:
+		headers["Cookie"] = fmt.Sprintf("SID=%s", token)
+	case AuthTypeGambit:
+		fields["Gambit"] = token
+	}
+
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return "", NewOperationError("failed to encode the JSON request", err)
+	}
+	response, err := c.do(ctx, &request{method: "POST", path: path, body: body, contentType: "application/json", headers: headers})
+	if err != nil {
+		return response, err
+	}
+	if internal.IsReadOnlyForm(response) {
+		return response, ErrInsufficientPrivileges
+	}
+	return response, nil
+}
+
+// formToken returns the hidden security token of a config form, as some firmware rejects writes
+// which don't echo it back. It's extracted from the page in hand, only without one the config page
+// is read. The name is empty, if there's no token or the page can't be read.
+func (c *Client) formToken(ctx context.Context, path string, page string) (name string, value string) {
+	if page == "" {
+		var err error
+		if page, err = c.makeAuthenticatedRequest(ctx, "GET", path, nil); err != nil {
+			return "", ""
 		}
-		return c.httpClient.ReadBody(httpResp)
+	}
+	return internal.ExtractFormToken(page)
+}
+
+// reportProgress invokes the progress callback, if one is configured
+func (c *Client) reportProgress(done, total int, current string) {
+	if c.progress != nil {
+		c.progress(done, total, current)
 	}
 }
 
 // getSeedValue retrieves the random seed value from the login page
 func (c *Client) getSeedValue(ctx context.Context, loginPath string) (string, error) {
+	if c.seeds != nil {
+		return c.seeds(ctx)
+	}
+
 	resp, err := c.httpClient.Get(ctx, loginPath, nil)
 	if err != nil {
 		return "", err
@@ -0,0 +1,441 @@
+package netgear
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestWithResponseHookCapturesLoginAndStatus(t *testing.T) {
+	loginPage, err := os.ReadFile("../../test-data/GS316EP/login.html")
+	then.AssertThat(t, err, is.Nil())
+	mock := newMockSwitch(t)
+	mock.respond("GET /", `<html><title>NETGEAR GS316EP</title></html>`)
+	mock.respond("GET /wmi/login", string(loginPage))
+	mock.respond("POST /redirect.html", `<script>var Gambit = "a1b2c3d4";</script>`)
+	mock.respond("GET /iss/specific/poePortStatus.html", `<div class="port-wrap"><span class="port-number">1</span></div>`)
+
+	type entry struct {
+		method, url string
+		status      int
+		body        string
+	}
+	var mu sync.Mutex
+	var entries []entry
+	hook := func(method, url string, status int, body string) {
+		mu.Lock()
+		defer mu.Unlock()
+		entries = append(entries, entry{method, url, status, body})
+	}
+	client, err := NewClient(mock.URL(), WithEnvironmentAuth(false), WithTokenManager(NewMemoryTokenManager()), WithResponseHook(hook))
+	then.AssertThat(t, err, is.Nil())
+
+	err = client.Login(context.Background(), "password")
+	then.AssertThat(t, err, is.Nil())
+	_, err = client.POE().GetStatus(context.Background())
+	then.AssertThat(t, err, is.Nil())
+
+	var login, status *entry
+	for i := range entries {
+		if entries[i].method == http.MethodPost && strings.HasSuffix(entries[i].url, "/redirect.html") {
+			login = &entries[i]
+		}
+		if entries[i].method == http.MethodGet && strings.Contains(entries[i].url, "/iss/specific/poePortStatus.html") {
+			status = &entries[i]
+		}
+	}
+	then.AssertThat(t, login != nil && status != nil, is.True())
+	then.AssertThat(t, login.status, is.EqualTo(http.StatusOK))
+	then.AssertThat(t, login.body, is.EqualTo(`<script>var Gambit = "<redacted>";</script>`))
+	then.AssertThat(t, status.url, is.StringContaining("Gambit=<redacted>"))
+	then.AssertThat(t, strings.Contains(status.url, "a1b2c3d4"), is.False())
+}
+
+func TestWithResponseHookSurvivesWithTimeout(t *testing.T) {
+	for name, order := range map[string]func(ClientOption) []ClientOption{
+		"hook first":    func(hook ClientOption) []ClientOption { return []ClientOption{hook, WithTimeout(time.Second)} },
+		"timeout first": func(hook ClientOption) []ClientOption { return []ClientOption{WithTimeout(time.Second), hook} },
+	} {
+		t.Run(name, func(t *testing.T) {
+			mock := newMockSwitch(t)
+			mock.respond("GET /getPoePortStatus.cgi", "")
+			var urls []string
+			hook := WithResponseHook(func(method, url string, status int, body string) {
+				urls = append(urls, url)
+			})
+			client := newTestClient(t, mock, ModelGS308EPP, order(hook)...)
+
+			_, _ = client.POE().GetStatus(context.Background())
+
+			then.AssertThat(t, slices.ContainsFunc(urls, func(url string) bool {
+				return strings.HasSuffix(url, "/getPoePortStatus.cgi")
+			}), is.True())
+		})
+	}
+}
+
+func TestWithBasicAuth(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.handle("/getPoePortStatus.cgi", func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "proxy" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`<ul><li class="poePortStatusListItem"><input type="hidden" class="port" value="1">` +
+			`<span class="poe-power-mode"><span>Delivering Power</span></span></li></ul>`))
+	})
+
+	withoutAuth := newTestClient(t, mock, ModelGS308EPP)
+	_, err := withoutAuth.POE().GetStatus(context.Background())
+	var netgearErr *Error
+	then.AssertThat(t, errors.As(err, &netgearErr), is.True())
+
+	withAuth := newTestClient(t, mock, ModelGS308EPP, WithBasicAuth("proxy", "secret"))
+	statuses, err := withAuth.POE().GetStatus(context.Background())
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, statuses, has.Length[POEPortStatus](1))
+
+	withAuthThenTimeout := newTestClient(t, mock, ModelGS308EPP, WithBasicAuth("proxy", "secret"), WithTimeout(5*time.Second))
+	statuses, err = withAuthThenTimeout.POE().GetStatus(context.Background())
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, statuses, has.Length[POEPortStatus](1))
+}
+
+func TestLoginRefinesGenericModelFromDashboard(t *testing.T) {
+	root, err := os.ReadFile("../../test-data/GS308EPP/_root.html")
+	then.AssertThat(t, err, is.Nil())
+	dashboard, err := os.ReadFile("../../test-data/GS308EPP/dashboard.cgi.html")
+	then.AssertThat(t, err, is.Nil())
+
+	mock := newMockSwitch(t)
+	mock.respond("GET /", string(root))
+	mock.respond("GET /login.cgi", `<html><body><input type="hidden" id="rand" value="1234"></body></html>`)
+	mock.handle("POST /login.cgi", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "SID=session-token; path=/")
+	})
+	mock.respond("GET /dashboard.cgi", string(dashboard))
+	tokenMgr := NewMemoryTokenManager()
+
+	client, err := NewClient(mock.URL(), WithTokenManager(tokenMgr), WithEnvironmentAuth(false))
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, client.GetModel(), is.EqualTo(ModelGS30xEPx))
+
+	err = client.Login(context.Background(), "password")
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, client.GetModel(), is.EqualTo(ModelGS308EPP))
+	_, storedModel, err := tokenMgr.GetToken(context.Background(), mock.URL())
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, storedModel, is.EqualTo(ModelGS308EPP))
+}
+
+func TestNewClientWithCachedTokenSendsNoRequest(t *testing.T) {
+	mock := newMockSwitch(t)
+
+	newTestClient(t, mock, ModelGS305EP)
+
+	then.AssertThat(t, mock.requestsTo("GET", "/"), has.Length[mockRequest](0))
+}
+
+func TestStaleCachedModelIsRedetected(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("GET /", `<html><title>NETGEAR GS316EP</title></html>`)
+	mock.handle("GET /getPoePortStatus.cgi", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	tokenMgr := NewMemoryTokenManager()
+	_ = tokenMgr.StoreToken(context.Background(), mock.URL(), testToken, ModelGS305EP)
+
+	client, err := NewClient(mock.URL(), WithTokenManager(tokenMgr), WithEnvironmentAuth(false))
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, client.GetModel(), is.EqualTo(ModelGS305EP))
+
+	_, err = client.POE().GetStatus(context.Background())
+
+	then.AssertThat(t, errors.Is(err, ErrSessionExpired), is.True())
+	then.AssertThat(t, client.GetModel(), is.EqualTo(ModelGS316EP))
+	then.AssertThat(t, client.IsAuthenticated(), is.False())
+	_, _, err = tokenMgr.GetToken(context.Background(), mock.URL())
+	then.AssertThat(t, err, is.Not(is.Nil()))
+
+	mock.respond("GET /wmi/login", `<html><input type="hidden" id="rand" value="1234"></html>`)
+	mock.respond("POST /redirect.html", `<script>var Gambit = "f00d";</script>`)
+	mock.respond("GET /iss/specific/poePortStatus.html", `<div class="port-wrap"><span class="port-number">1</span></div>`)
+	err = client.Login(context.Background(), "password")
+	then.AssertThat(t, err, is.Nil())
+	_, err = client.POE().GetStatus(context.Background())
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, mock.requestsTo("GET", "/iss/specific/poePortStatus.html"), has.Length[mockRequest](1))
+	then.AssertThat(t, mock.requestsTo("GET", "/getPoePortStatus.cgi"), has.Length[mockRequest](1))
+}
+
+func TestCachedModelIsKeptWhenDetectionFails(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.handle("GET /getPoePortStatus.cgi", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	tokenMgr := NewMemoryTokenManager()
+	_ = tokenMgr.StoreToken(context.Background(), mock.URL(), testToken, ModelGS305EP)
+
+	client, err := NewClient(mock.URL(), WithTokenManager(tokenMgr), WithEnvironmentAuth(false))
+	then.AssertThat(t, err, is.Nil())
+	_, _ = client.POE().GetStatus(context.Background())
+
+	then.AssertThat(t, client.GetModel(), is.EqualTo(ModelGS305EP))
+	then.AssertThat(t, client.IsAuthenticated(), is.True())
+}
+
+// serveGS316Session makes the mock answer the dashboard with the login page, unless the session
+// is the Gambit token of its login
+func serveGS316Session(t *testing.T, mock *mockSwitch) {
+	loginPage, err := os.ReadFile("../../test-data/GS316EP/login.html")
+	then.AssertThat(t, err, is.Nil())
+	dashboard, err := os.ReadFile("../../test-data/GS316EP/dashboard.html")
+	then.AssertThat(t, err, is.Nil())
+	mock.respond("GET /", `<html><title>NETGEAR GS316EP</title></html>`)
+	mock.respond("GET /wmi/login", string(loginPage))
+	mock.respond("POST /redirect.html", `<script>var Gambit = "f00d";</script>`)
+	mock.handle("GET /iss/specific/dashboard.html", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("Gambit") == "f00d" {
+			_, _ = w.Write(dashboard)
+			return
+		}
+		_, _ = w.Write(loginPage)
+	})
+}
+
+func TestRefreshRenewsRejectedSession(t *testing.T) {
+	mock := newMockSwitch(t)
+	serveGS316Session(t, mock)
+	t.Setenv("NETGEAR_SWITCHES", mock.URL()+"=secret")
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	err := client.Refresh(context.Background())
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, mock.requestsTo("POST", "/redirect.html"), has.Length[mockRequest](1))
+	token, _ := client.session()
+	then.AssertThat(t, token, is.EqualTo("f00d"))
+
+	// a valid session is kept
+	err = client.Refresh(context.Background())
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, mock.requestsTo("POST", "/redirect.html"), has.Length[mockRequest](1))
+}
+
+func TestRefreshWithoutPasswordReturnsSessionExpired(t *testing.T) {
+	mock := newMockSwitch(t)
+	serveGS316Session(t, mock)
+	client := newTestClient(t, mock, ModelGS316EP, WithEnvironmentAuth(false))
+
+	err := client.Refresh(context.Background())
+
+	then.AssertThat(t, errors.Is(err, ErrSessionExpired), is.True())
+	then.AssertThat(t, client.IsAuthenticated(), is.False())
+}
+
+func TestRefreshDetectsReplacedSwitch(t *testing.T) {
+	mock := newMockSwitch(t)
+	client := newTestClient(t, mock, ModelGS305EP, WithEnvironmentAuth(false))
+	serveGS316Session(t, mock)
+
+	err := client.Refresh(context.Background())
+
+	then.AssertThat(t, errors.Is(err, ErrSessionExpired), is.True())
+	then.AssertThat(t, client.GetModel(), is.EqualTo(ModelGS316EP))
+	then.AssertThat(t, mock.requestsTo("GET", "/dashboard.cgi"), has.Length[mockRequest](0))
+}
+
+func TestWithQuietWinsOverVerbose(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("GET /", `<html><title>NETGEAR GS316EP</title></html>`)
+	tokenMgr := NewMemoryTokenManager()
+	_ = tokenMgr.StoreToken(context.Background(), mock.URL(), testToken, ModelGS316EP)
+
+	output := captureStdout(t, func() {
+		_, err := NewClient(mock.URL(), WithTokenManager(tokenMgr), WithQuiet(true), WithVerbose(true))
+		then.AssertThat(t, err, is.Nil())
+	})
+
+	then.AssertThat(t, output, is.EqualTo(""))
+}
+
+func TestWithQuietLoadingFromEnvironmentPrintsNothing(t *testing.T) {
+	mock := newMockSwitch(t)
+	serveGS316Session(t, mock)
+	t.Setenv("NETGEAR_SWITCHES", mock.URL()+"=secret")
+	t.Setenv(TokenKeyEnvVar, "passphrase")
+	tokenMgr, err := NewEncryptedFileTokenManager(t.TempDir(), nil)
+	then.AssertThat(t, err, is.Nil())
+
+	var client *Client
+	output := captureStdout(t, func() {
+		// logs in with the environment password, then loads the stored token with the environment key
+		_, err = NewClient(mock.URL(), WithTokenManager(tokenMgr), WithQuiet(true), WithVerbose(true))
+		then.AssertThat(t, err, is.Nil())
+		client, err = NewClient(mock.URL(), WithTokenManager(tokenMgr), WithQuiet(true), WithVerbose(true))
+		then.AssertThat(t, err, is.Nil())
+	})
+
+	then.AssertThat(t, output, is.EqualTo(""))
+	then.AssertThat(t, client.IsAuthenticated(), is.True())
+	then.AssertThat(t, mock.requestsTo("POST", "/redirect.html"), has.Length[mockRequest](1))
+}
+
+func TestWithAutoLoginDisabledIgnoresEnvironmentPassword(t *testing.T) {
+	root, err := os.ReadFile("../../test-data/GS308EPP/_root.html")
+	then.AssertThat(t, err, is.Nil())
+	mock := newMockSwitch(t)
+	mock.respond("GET /", string(root))
+	t.Setenv("NETGEAR_SWITCHES", mock.URL()+"=secret")
+
+	client, err := NewClient(mock.URL(), WithTokenManager(NewMemoryTokenManager()), WithAutoLogin(false))
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, client.IsAuthenticated(), is.False())
+	then.AssertThat(t, client.GetModel(), is.EqualTo(ModelGS30xEPx))
+	then.AssertThat(t, mock.requestsTo("POST", "/login.cgi"), has.Length[mockRequest](0))
+}
+
+func TestGambitTokenIsMergedIntoExistingQuery(t *testing.T) {
+	mock := newMockSwitch(t)
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	_, err := client.makeAuthenticatedRequest(context.Background(), "GET", "/iss/specific/poePortStatus.html?GetData=TRUE", nil)
+
+	then.AssertThat(t, err, is.Nil())
+	requests := mock.requestsTo("GET", "/iss/specific/poePortStatus.html")
+	then.AssertThat(t, requests, has.Length[mockRequest](1))
+	then.AssertThat(t, requests[0].Query.Get("GetData"), is.EqualTo("TRUE"))
+	then.AssertThat(t, requests[0].Query.Get("Gambit"), is.EqualTo(testToken))
+}
+
+func TestWithDefaultHeaders(t *testing.T) {
+	mock := newMockSwitch(t)
+	client := newTestClient(t, mock, ModelGS308EPP, WithTimeout(time.Second), WithDefaultHeaders(map[string]string{
+		"X-Proxy-Auth": "corp",
+		"Cookie":       "SID=stale",
+		"Content-Type": "text/plain",
+	}))
+
+	_, err := client.makeAuthenticatedRequest(context.Background(), "GET", "/getPoePortStatus.cgi", nil)
+	then.AssertThat(t, err, is.Nil())
+	_, err = client.makeAuthenticatedRequest(context.Background(), "POST", "/PoEPortConfig.cgi", url.Values{"port": {"1"}})
+	then.AssertThat(t, err, is.Nil())
+
+	get := mock.requestsTo("GET", "/getPoePortStatus.cgi")
+	post := mock.requestsTo("POST", "/PoEPortConfig.cgi")
+	then.AssertThat(t, get, has.Length[mockRequest](1))
+	then.AssertThat(t, post, has.Length[mockRequest](1))
+	then.AssertThat(t, get[0].Header.Get("X-Proxy-Auth"), is.EqualTo("corp"))
+	then.AssertThat(t, post[0].Header.Get("X-Proxy-Auth"), is.EqualTo("corp"))
+	then.AssertThat(t, post[0].Header.Get("Cookie"), is.EqualTo("SID="+testToken))
+	then.AssertThat(t, post[0].Header.Get("Content-Type"), is.EqualTo("application/x-www-form-urlencoded"))
+}
+
+func TestWithQuery(t *testing.T) {
+	gambit := url.Values{"Gambit": {"token"}}
+
+	then.AssertThat(t, withQuery("/iss/specific/dashboard.html", gambit), is.EqualTo("/iss/specific/dashboard.html?Gambit=token"))
+	then.AssertThat(t, withQuery("/iss/specific/poePortStatus.html?GetData=TRUE", gambit),
+		is.EqualTo("/iss/specific/poePortStatus.html?Gambit=token&GetData=TRUE"))
+	then.AssertThat(t, withQuery("/iss/specific/poePortStatus.html?Gambit=expired", gambit),
+		is.EqualTo("/iss/specific/poePortStatus.html?Gambit=token"))
+}
+
+func TestWritesOfNonAdminAccountReturnPrivilegeError(t *testing.T) {
+	// the firmware answers with the config page again, rendered without writable controls
+	readOnlyPage := `<html><body><form action="/port_status.cgi" method="post">` +
+		`<input type="hidden" name="hash" value="1234"><input type="text" name="name" value="camera" disabled>` +
+		`</form></body></html>`
+	mock := newMockSwitch(t)
+	mock.respond("GET /PortStatistics.cgi", "<table></table>")
+	mock.respond("POST /port_status.cgi", readOnlyPage)
+	mock.respond("GET /iss/specific/poePortConf.html", "")
+	mock.respond("POST /iss/specific/poePortConf.html", readOnlyPage)
+
+	err := newTestClient(t, mock, ModelGS308EPP).Ports().SetPortName(context.Background(), 1, "doorbell")
+	then.AssertThat(t, errors.Is(err, ErrInsufficientPrivileges), is.True())
+	var netgearErr *Error
+	then.AssertThat(t, errors.As(err, &netgearErr), is.True())
+	then.AssertThat(t, netgearErr.Type, is.EqualTo(ErrorTypeAuth))
+
+	err = newTestClient(t, mock, ModelGS316EP).POE().EnablePort(context.Background(), 1)
+	then.AssertThat(t, errors.Is(err, ErrInsufficientPrivileges), is.True())
+}
+
+func TestWritesWithWritableResponsePage(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("GET /PortStatistics.cgi", "<table></table>")
+	mock.respond("POST /port_status.cgi", `<html><body><form action="/port_status.cgi" method="post">`+
+		`<input type="text" name="name" value="doorbell"><button type="submit">Apply</button></form></body></html>`)
+
+	err := newTestClient(t, mock, ModelGS308EPP).Ports().SetPortName(context.Background(), 1, "doorbell")
+
+	then.AssertThat(t, err, is.Nil())
+}
+
+func TestCapabilities(t *testing.T) {
+	mock := newMockSwitch(t)
+
+	gs305ep := newTestClient(t, mock, ModelGS305EP).Capabilities()
+	then.AssertThat(t, gs305ep.POE, is.True())
+	then.AssertThat(t, gs305ep.LAG, is.False())
+
+	gs316epp := newTestClient(t, mock, ModelGS316EPP).Capabilities()
+	then.AssertThat(t, gs316epp.POE, is.True())
+	then.AssertThat(t, gs316epp.LAG, is.True())
+
+	then.AssertThat(t, Model("GS108E").Capabilities(), is.EqualTo(Capabilities{}))
+}
+
+// TestClientIsSafeForConcurrentUse is meant for the race detector: go test -race
+func TestClientIsSafeForConcurrentUse(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("GET /getPoePortStatus.cgi", `<ul><li class="poePortStatusListItem"><input type="hidden" class="port" value="1"></li></ul>`)
+	mock.respond("GET /login.cgi", `<input type="hidden" id="rand" value="1234">`)
+	mock.handle("POST /login.cgi", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "SID=refreshed")
+	})
+	fixedSeed := func(ctx context.Context) (string, error) { return "12345678", nil }
+	client := newTestClient(t, mock, ModelGS305EP, WithSeedProvider(fixedSeed))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20*5+5)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 5; i++ {
+			errs <- client.Login(context.Background(), "secret")
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				_, err := client.POE().GetStatus(context.Background())
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		then.AssertThat(t, err, is.Nil())
+	}
+	then.AssertThat(t, client.IsAuthenticated(), is.True())
+}
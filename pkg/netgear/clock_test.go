@@ -0,0 +1,51 @@
+package netgear
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+// fakeClock records requested delays and fires immediately
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	delays []time.Duration
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delays = append(f.delays, d)
+	f.now = f.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- f.now
+	return ch
+}
+
+func TestRetryBackoffUsesClock(t *testing.T) {
+	mock := newMockSwitch(t)
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	client := newTestClient(t, mock, ModelGS308EPP,
+		WithClock(clock), WithRetry(3, 100*time.Millisecond))
+	mock.server.Close()
+
+	_, err := client.POE().GetStatus(context.Background())
+
+	var netgearErr *Error
+	then.AssertThat(t, errors.As(err, &netgearErr), is.True())
+	then.AssertThat(t, clock.delays, is.EqualTo([]time.Duration{
+		100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond,
+	}))
+}
@@ -0,0 +1,56 @@
+package netgear
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ntgrrc/pkg/netgear/internal"
+)
+
+// GetDashboard summarizes the switch: the connected ports of the dashboard page, the POE ports
+// delivering power with their total consumption, and alarms for POE faults and a high temperature
+func (c *Client) GetDashboard(ctx context.Context) (*Dashboard, error) {
+	response, err := c.readDashboardPage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := internal.NewSystemDataParser().ParseDashboard(response)
+	if err != nil {
+		return nil, NewParsingError("failed to parse dashboard", err)
+	}
+
+	dashboard := &Dashboard{Alarms: []string{}}
+	if total, ok := raw["total_ports"].(int); ok {
+		dashboard.TotalPorts = total
+	}
+	if connected, ok := raw["connected_ports"].(int); ok {
+		dashboard.ConnectedPorts = connected
+	}
+
+	// the dashboard page also carries the system temperature, if the switch has a sensor
+	thermal, err := internal.NewPOEDataParser().ParseThermalStatus(response)
+	if err == nil {
+		if status, ok := thermal["status"].(string); ok && (status == TemperatureStatusWarning || status == TemperatureStatusCritical) {
+			temperature, _ := thermal["temperature_c"].(float64)
+			dashboard.Alarms = append(dashboard.Alarms, fmt.Sprintf("system temperature %.0f °C is %s", temperature, strings.ToLower(status)))
+		}
+	}
+
+	statuses, err := c.POE().GetStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		if status.IsDelivering() {
+			dashboard.POEPortsActive++
+		}
+		dashboard.TotalPOEPowerW += status.PowerW
+		if status.Fault != "" && status.Fault != POEFaultNone {
+			dashboard.Alarms = append(dashboard.Alarms, fmt.Sprintf("POE fault on port %d: %s", status.PortID, status.ErrorStatus))
+		}
+	}
+
+	return dashboard, nil
+}
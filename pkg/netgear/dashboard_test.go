@@ -0,0 +1,62 @@
+package netgear
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestGetDashboardFromFixtures(t *testing.T) {
+	fixtures := map[Model]struct {
+		dashboardPath, dashboardFile, statusPath, statusFile string
+		expected                                             Dashboard
+	}{
+		ModelGS308EPP: {"/dashboard.cgi", "../../test-data/GS308EPP/dashboard.cgi.html",
+			"/getPoePortStatus.cgi", "../../test-data/GS308EPP/getPoePortStatus.cgi.html",
+			Dashboard{TotalPorts: 8, ConnectedPorts: 1, POEPortsActive: 2, TotalPOEPowerW: 7.5, Alarms: []string{}}},
+		ModelGS316EP: {"/iss/specific/dashboard.html", "../../test-data/GS316EP/dashboard.html",
+			"/iss/specific/poePortStatus.html", "../../test-data/GS316EP/poePortStatus_GetData_true.html",
+			Dashboard{TotalPorts: 16, ConnectedPorts: 2, POEPortsActive: 1, TotalPOEPowerW: 1.1, Alarms: []string{}}},
+	}
+
+	for model, fixture := range fixtures {
+		t.Run(string(model), func(t *testing.T) {
+			dashboard, err := os.ReadFile(fixture.dashboardFile)
+			then.AssertThat(t, err, is.Nil())
+			status, err := os.ReadFile(fixture.statusFile)
+			then.AssertThat(t, err, is.Nil())
+			mock := newMockSwitch(t)
+			mock.respond(fixture.dashboardPath, string(dashboard))
+			mock.respond(fixture.statusPath, string(status))
+			client := newTestClient(t, mock, model)
+
+			result, err := client.GetDashboard(context.Background())
+
+			then.AssertThat(t, err, is.Nil())
+			then.AssertThat(t, *result, is.EqualTo(fixture.expected))
+		})
+	}
+}
+
+func TestGetDashboardReportsPOEFaults(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("/iss/specific/dashboard.html", `<div class="dashboard-port-status">`+
+		`<span class="status-on-port">CONNECTED</span><span class="status-on-port">AVAILABLE</span></div>`)
+	mock.respond("/iss/specific/poePortStatus.html", `[
+		{"port_id": 1, "status": "Delivering Power", "power_w": 4.5, "error_status": "No Error"},
+		{"port_id": 2, "status": "Searching", "power_w": 0, "error_status": "Overload"}
+	]`)
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	result, err := client.GetDashboard(context.Background())
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, result.ConnectedPorts, is.EqualTo(1))
+	then.AssertThat(t, result.POEPortsActive, is.EqualTo(1))
+	then.AssertThat(t, result.Alarms, has.Length[string](1))
+	then.AssertThat(t, result.Alarms[0], is.EqualTo("POE fault on port 2: Overload"))
+}
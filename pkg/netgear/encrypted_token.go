@@ -0,0 +1,118 @@
+package netgear
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// TokenKeyEnvVar names the environment variable holding the passphrase of encrypted token files,
+// used when NewEncryptedFileTokenManager is given no key
+const TokenKeyEnvVar = "NTGRRC_TOKEN_KEY"
+
+// scrypt parameters recommended for interactive logins
+const (
+	tokenKeySaltSize = 16
+	tokenKeyScryptN  = 1 << 15
+	tokenKeyScryptR  = 8
+	tokenKeyScryptP  = 1
+	tokenKeySize     = 32 // AES-256
+)
+
+// EncryptedFileTokenManager stores tokens in files like FileTokenManager, but encrypted with AES-GCM,
+// so the token can't be read by other processes of the user without knowing the passphrase.
+// The key is derived from the passphrase with scrypt and a random salt stored in each file.
+type EncryptedFileTokenManager struct {
+	files      *FileTokenManager
+	passphrase []byte
+}
+
+// NewEncryptedFileTokenManager creates a file-based token manager encrypting the token files with a key
+// derived from the passphrase. Without a passphrase, the one in NTGRRC_TOKEN_KEY is used.
+func NewEncryptedFileTokenManager(dir string, key []byte) (*EncryptedFileTokenManager, error) {
+	if len(key) == 0 {
+		key = []byte(os.Getenv(TokenKeyEnvVar))
+	}
+	if len(key) == 0 {
+		return nil, NewAuthError("no key for encrypting tokens given, set "+TokenKeyEnvVar, nil)
+	}
+	return &EncryptedFileTokenManager{
+		files:      NewFileTokenManager(dir),
+		passphrase: key,
+	}, nil
+}
+
+// GetToken retrieves and decrypts a stored token
+func (m *EncryptedFileTokenManager) GetToken(ctx context.Context, address string) (string, Model, error) {
+	data, err := os.ReadFile(m.files.getTokenFilename(address))
+	if err != nil {
+		return "", "", NewAuthError("failed to read token file", err)
+	}
+
+	if len(data) < tokenKeySaltSize {
+		return "", "", NewAuthError("malformed token file", nil)
+	}
+	salt, sealed := data[:tokenKeySaltSize], data[tokenKeySaltSize:]
+	aead, err := m.cipher(salt)
+	if err != nil {
+		return "", "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", "", NewAuthError("malformed token file", nil)
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	// the address is authenticated too, so a token file can't be swapped for another switch's
+	content, err := aead.Open(nil, nonce, ciphertext, []byte(address))
+	if err != nil {
+		return "", "", NewAuthError("failed to decrypt token file, the key may be wrong", err)
+	}
+
+	return parseTokenFileContent(string(content))
+}
+
+// StoreToken encrypts and saves a token to file
+func (m *EncryptedFileTokenManager) StoreToken(ctx context.Context, address string, token string, model Model) error {
+	salt := make([]byte, tokenKeySaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return NewAuthError("failed to generate salt", err)
+	}
+	aead, err := m.cipher(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return NewAuthError("failed to generate nonce", err)
+	}
+
+	data := append(salt, nonce...)
+	data = aead.Seal(data, nonce, []byte(string(model)+":"+token), []byte(address))
+	return m.files.writeTokenFile(address, data)
+}
+
+// DeleteToken removes a stored token file
+func (m *EncryptedFileTokenManager) DeleteToken(ctx context.Context, address string) error {
+	return m.files.DeleteToken(ctx, address)
+}
+
+// cipher derives the key of a token file from the passphrase and the file's salt
+func (m *EncryptedFileTokenManager) cipher(salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(m.passphrase, salt, tokenKeyScryptN, tokenKeyScryptR, tokenKeyScryptP, tokenKeySize)
+	if err != nil {
+		return nil, NewAuthError("failed to derive token key", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, NewAuthError("failed to create token cipher", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, NewAuthError("failed to create token cipher", err)
+	}
+	return aead, nil
+}
@@ -0,0 +1,69 @@
+package netgear
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestEncryptedFileTokenManagerRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	tm, err := NewEncryptedFileTokenManager(dir, []byte("passphrase"))
+	then.AssertThat(t, err, is.Nil())
+
+	err = tm.StoreToken(context.Background(), "192.168.0.1", "secret-token", ModelGS316EP)
+	then.AssertThat(t, err, is.Nil())
+
+	token, model, err := tm.GetToken(context.Background(), "192.168.0.1")
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, token, is.EqualTo("secret-token"))
+	then.AssertThat(t, model, is.EqualTo(ModelGS316EP))
+
+	content, err := os.ReadFile(tm.files.getTokenFilename("192.168.0.1"))
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, strings.Contains(string(content), "secret-token"), is.False())
+	then.AssertThat(t, strings.Contains(string(content), string(ModelGS316EP)), is.False())
+}
+
+func TestEncryptedFileTokenManagerWrongKey(t *testing.T) {
+	dir := t.TempDir()
+	tm, err := NewEncryptedFileTokenManager(dir, []byte("passphrase"))
+	then.AssertThat(t, err, is.Nil())
+	err = tm.StoreToken(context.Background(), "192.168.0.1", "secret-token", ModelGS316EP)
+	then.AssertThat(t, err, is.Nil())
+
+	other, err := NewEncryptedFileTokenManager(dir, []byte("wrong"))
+	then.AssertThat(t, err, is.Nil())
+	_, _, err = other.GetToken(context.Background(), "192.168.0.1")
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+	then.AssertThat(t, err.Error(), is.StringContaining("failed to decrypt token file"))
+}
+
+func TestEncryptedFileTokenManagerKeyFromEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(TokenKeyEnvVar, "from-env")
+	tm, err := NewEncryptedFileTokenManager(dir, nil)
+	then.AssertThat(t, err, is.Nil())
+	err = tm.StoreToken(context.Background(), "switch1", "secret-token", ModelGS305EP)
+	then.AssertThat(t, err, is.Nil())
+
+	explicit, err := NewEncryptedFileTokenManager(dir, []byte("from-env"))
+	then.AssertThat(t, err, is.Nil())
+	token, _, err := explicit.GetToken(context.Background(), "switch1")
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, token, is.EqualTo("secret-token"))
+}
+
+func TestEncryptedFileTokenManagerRequiresKey(t *testing.T) {
+	t.Setenv(TokenKeyEnvVar, "")
+
+	_, err := NewEncryptedFileTokenManager(t.TempDir(), nil)
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+}
@@ -1,6 +1,11 @@
 package netgear
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
 
 // ErrorType represents the category of error
 type ErrorType string
@@ -38,8 +43,19 @@ var (
 	ErrModelNotSupported  = &Error{Type: ErrorTypeModel, Message: "model not supported"}
 	ErrModelNotDetected   = &Error{Type: ErrorTypeModel, Message: "could not detect switch model"}
 	ErrInvalidCredentials = &Error{Type: ErrorTypeAuth, Message: "invalid credentials"}
+	ErrAccountLocked      = &Error{Type: ErrorTypeAuth, Message: "login blocked after too many failed attempts"}
 	ErrNetworkTimeout     = &Error{Type: ErrorTypeNetwork, Message: "network timeout"}
 	ErrInvalidResponse    = &Error{Type: ErrorTypeParsing, Message: "invalid response format"}
+	// ErrHostResolution is wrapped by network errors for a host name, which doesn't resolve; unlike timeouts they aren't retried
+	ErrHostResolution = &Error{Type: ErrorTypeNetwork, Message: "host name could not be resolved"}
+	// ErrInsufficientPrivileges is returned by writes, which the switch refused because the account isn't an admin
+	ErrInsufficientPrivileges = &Error{Type: ErrorTypeAuth, Message: "insufficient privileges, the account can't change the configuration"}
+	// ErrInsufficientBudget is returned by EnablePort with WithBudgetGuard(true), if the port could draw more power than remains
+	ErrInsufficientBudget = &Error{Type: ErrorTypeOperation, Message: "insufficient POE power budget"}
+	// ErrRateLimitCoerced is wrapped by SetPortLimits errors with WithLimitReadBack(true), if the switch applied other limits
+	ErrRateLimitCoerced = &Error{Type: ErrorTypeOperation, Message: "the switch applied another rate limit than requested"}
+	// ErrUnsupportedContentType is wrapped by errors of requests, which the switch answered with HTTP 415
+	ErrUnsupportedContentType = &Error{Type: ErrorTypeOperation, Message: "content type not supported by the switch"}
 )
 
 // NewError creates a new netgear error
@@ -61,6 +77,16 @@ func NewNetworkError(message string, cause error) *Error {
 	return NewError(ErrorTypeNetwork, message, cause)
 }
 
+// newConnectionError creates a network error for a failed request, wrapping ErrHostResolution if the
+// host name doesn't exist. Temporary DNS failures, e.g. an unreachable name server, stay retryable.
+func newConnectionError(message string, cause error) *Error {
+	var dnsErr *net.DNSError
+	if errors.As(cause, &dnsErr) && !dnsErr.IsTimeout && !dnsErr.IsTemporary {
+		return NewNetworkError(message, fmt.Errorf("%w: %w", ErrHostResolution, cause))
+	}
+	return NewNetworkError(message, cause)
+}
+
 // NewParsingError creates a new parsing error
 func NewParsingError(message string, cause error) *Error {
 	return NewError(ErrorTypeParsing, message, cause)
@@ -74,4 +100,46 @@ func NewModelError(message string, cause error) *Error {
 // NewOperationError creates a new operation error
 func NewOperationError(message string, cause error) *Error {
 	return NewError(ErrorTypeOperation, message, cause)
-}
\ No newline at end of file
+}
+
+// newWriteError creates an operation error for a failed write. A write refused for missing admin
+// privileges stays an authentication error, wrapping ErrInsufficientPrivileges.
+func newWriteError(message string, cause error) *Error {
+	if errors.Is(cause, ErrInsufficientPrivileges) {
+		return NewAuthError(message, cause)
+	}
+	return NewOperationError(message, cause)
+}
+
+// MultiError collects the errors of a batch operation, like one error per failed port
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors occurred: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap allows errors.Is and errors.As to match any of the collected errors
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+func (e *MultiError) add(err error) {
+	e.Errors = append(e.Errors, err)
+}
+
+// errorOrNil returns nil, if no errors were collected
+func (e *MultiError) errorOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
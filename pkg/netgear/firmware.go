@@ -0,0 +1,78 @@
+package netgear
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"ntgrrc/pkg/netgear/internal"
+)
+
+// testedFirmware lists the firmware versions of each series the pages were tested with, see the
+// "Supported firmware versions" of README.md. Other versions may have changed the page layouts.
+var testedFirmware = map[ModelSeries][]string{
+	Series30x: {"1.0.0.8", "1.0.0.10", "1.0.1.1"},
+	Series316: {"1.0.3.4", "1.0.3.7", "1.0.4.4"},
+}
+
+// CheckFirmwareCompatibility reports whether the firmware version was tested with the model's series,
+// else a warning to show the user. The version may be given with or without the leading "V".
+func CheckFirmwareCompatibility(model Model, firmware string) (supported bool, warning string) {
+	version := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(firmware), "V"), "v")
+	tested, ok := testedFirmware[model.Series()]
+	if !ok {
+		return false, fmt.Sprintf("model %s is not supported", model)
+	}
+	if slices.Contains(tested, version) {
+		return true, ""
+	}
+	return false, fmt.Sprintf("firmware %s of the %s was not tested, pages may have changed and fail to parse (tested: %s)",
+		firmware, model, strings.Join(tested, ", "))
+}
+
+// GetSystemInfo reads the model and the firmware version of the switch from its dashboard
+func (c *Client) GetSystemInfo(ctx context.Context) (*SystemInfo, error) {
+	body, err := c.readDashboardPage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	version, err := internal.NewSystemDataParser().ParseFirmwareVersion(body)
+	if err != nil {
+		return nil, NewParsingError("failed to parse the firmware version", err)
+	}
+	return &SystemInfo{Model: c.GetModel(), FirmwareVersion: version}, nil
+}
+
+// warnUntestedFirmware passes a warning to the firmware warning handler, if the dashboard shows a firmware
+// version which wasn't tested
+func (c *Client) warnUntestedFirmware(dashboard string) {
+	version, err := internal.NewSystemDataParser().ParseFirmwareVersion(dashboard)
+	if err != nil || version == "" {
+		return
+	}
+	if supported, warning := CheckFirmwareCompatibility(c.GetModel(), version); !supported {
+		c.fwWarning(warning)
+	}
+}
+
+// readDashboardPage reads the raw dashboard page of the model's series
+func (c *Client) readDashboardPage(ctx context.Context) (string, error) {
+	if !c.IsAuthenticated() {
+		return "", ErrNotAuthenticated
+	}
+	var endpoint string
+	switch c.GetModel().Series() {
+	case Series30x:
+		endpoint = "/dashboard.cgi"
+	case Series316:
+		endpoint = "/iss/specific/dashboard.html"
+	default:
+		return "", NewOperationError("dashboard not supported for this model", nil)
+	}
+	body, err := c.makeAuthenticatedRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return "", NewOperationError("failed to get dashboard", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,100 @@
+package netgear
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestCheckFirmwareCompatibility(t *testing.T) {
+	supported, warning := CheckFirmwareCompatibility(ModelGS308EPP, "V1.0.1.1")
+	then.AssertThat(t, supported, is.True())
+	then.AssertThat(t, warning, is.EqualTo(""))
+
+	supported, warning = CheckFirmwareCompatibility(ModelGS316EP, "1.0.4.4")
+	then.AssertThat(t, supported, is.True())
+
+	supported, warning = CheckFirmwareCompatibility(ModelGS316EP, "V1.0.5.2")
+	then.AssertThat(t, supported, is.False())
+	then.AssertThat(t, warning, is.StringContaining("firmware V1.0.5.2 of the GS316EP was not tested"))
+
+	// a tested GS30x version isn't a tested GS316 version
+	supported, _ = CheckFirmwareCompatibility(ModelGS316EP, "V1.0.1.1")
+	then.AssertThat(t, supported, is.False())
+}
+
+func TestGetSystemInfo(t *testing.T) {
+	dashboard, err := os.ReadFile("../../test-data/GS316EP/dashboard.html")
+	then.AssertThat(t, err, is.Nil())
+	mock := newMockSwitch(t)
+	mock.respond("GET /iss/specific/dashboard.html", string(dashboard))
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	info, err := client.GetSystemInfo(context.Background())
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, *info, is.EqualTo(SystemInfo{Model: ModelGS316EP, FirmwareVersion: "1.0.4.4"}))
+}
+
+func TestLoginWarnsAboutUntestedFirmware(t *testing.T) {
+	tests := []struct {
+		name      string
+		firmware  string
+		handler   bool
+		quiet     bool
+		warned    bool
+		dashboard int
+	}{
+		{"untested firmware", "1.0.9.9", true, false, true, 1},
+		{"tested firmware", "1.0.4.4", true, false, false, 1},
+		{"warning disabled", "1.0.9.9", false, false, false, 0},
+		{"quiet", "1.0.9.9", true, true, false, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mock := newMockSwitch(t)
+			mock.respond("GET /", `<html><title>NETGEAR GS316EP</title></html>`)
+			mock.respond("GET /wmi/login", `<html><input type="hidden" id="rand" value="1234"></html>`)
+			mock.respond("POST /redirect.html", `<script>var Gambit = "a1b2c3d4";</script>`)
+			mock.respond("GET /iss/specific/dashboard.html",
+				`<p class="light-title">Firmware Version</p><p class="bold-title">`+test.firmware+`</p>`)
+			var warnings []string
+			opts := []ClientOption{WithEnvironmentAuth(false), WithTokenManager(NewMemoryTokenManager()), WithQuiet(test.quiet)}
+			if test.handler {
+				opts = append(opts, WithFirmwareWarning(func(warning string) { warnings = append(warnings, warning) }))
+			}
+			client, err := NewClient(mock.URL(), opts...)
+			then.AssertThat(t, err, is.Nil())
+
+			output := captureStdout(t, func() {
+				err = client.Login(context.Background(), "password")
+			})
+
+			then.AssertThat(t, err, is.Nil())
+			then.AssertThat(t, output, is.EqualTo(""))
+			warned := len(warnings) == 1 && strings.Contains(warnings[0], "firmware "+test.firmware+" of the GS316EP was not tested")
+			then.AssertThat(t, warned, is.EqualTo(test.warned))
+			then.AssertThat(t, mock.requestsTo("GET", "/iss/specific/dashboard.html"), has.Length[mockRequest](test.dashboard))
+		})
+	}
+}
+
+// captureStdout returns what fn printed to stdout, e.g. the warnings of the client
+func captureStdout(t *testing.T, fn func()) string {
+	reader, writer, err := os.Pipe()
+	then.AssertThat(t, err, is.Nil())
+	stdout := os.Stdout
+	os.Stdout = writer
+	fn()
+	os.Stdout = stdout
+	_ = writer.Close()
+	output, _ := io.ReadAll(reader)
+	return string(output)
+}
@@ -0,0 +1,170 @@
+package netgear
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"ntgrrc/pkg/netgear/internal"
+)
+
+// defaultDNSCacheTTL is how long a shared transport remembers resolved switch host names
+const defaultDNSCacheTTL = 5 * time.Minute
+
+// Fleet manages the clients of a group of switches, e.g. for scanning all of them at once
+type Fleet struct {
+	addresses []string
+	clients   map[string]*Client
+	transport *http.Transport // nil, unless the clients share a transport
+}
+
+// FleetOption configures a Fleet
+type FleetOption func(*fleetConfig)
+
+type fleetConfig struct {
+	sharedTransport bool
+	dnsCacheTTL     time.Duration
+	clientOpts      []ClientOption
+}
+
+// WithSharedTransport lets all clients of the fleet share one transport, with keep-alive connections
+// and a DNS cache remembering host names for the given time (0 for the default of 5 minutes)
+func WithSharedTransport(dnsCacheTTL time.Duration) FleetOption {
+	return func(c *fleetConfig) {
+		c.sharedTransport = true
+		if dnsCacheTTL > 0 {
+			c.dnsCacheTTL = dnsCacheTTL
+		}
+	}
+}
+
+// WithClientOptions sets options applied to every client of the fleet
+func WithClientOptions(opts ...ClientOption) FleetOption {
+	return func(c *fleetConfig) {
+		c.clientOpts = append(c.clientOpts, opts...)
+	}
+}
+
+// NewFleet creates a client for each switch address
+func NewFleet(addresses []string, opts ...FleetOption) (*Fleet, error) {
+	config := fleetConfig{dnsCacheTTL: defaultDNSCacheTTL}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	fleet := &Fleet{
+		addresses: addresses,
+		clients:   make(map[string]*Client, len(addresses)),
+	}
+	clientOpts := config.clientOpts
+	if config.sharedTransport {
+		fleet.transport = internal.NewSharedTransport(config.dnsCacheTTL)
+		clientOpts = append(append([]ClientOption{}, clientOpts...), WithTransport(fleet.transport))
+	}
+
+	failures := &MultiError{}
+	for _, address := range addresses {
+		client, err := NewClient(address, clientOpts...)
+		if err != nil {
+			failures.add(NewOperationError("failed to create client for "+address, err))
+			continue
+		}
+		fleet.clients[address] = client
+	}
+	if err := failures.errorOrNil(); err != nil {
+		fleet.Close()
+		return nil, err
+	}
+	return fleet, nil
+}
+
+// Addresses returns the switch addresses, in the order given to NewFleet
+func (f *Fleet) Addresses() []string {
+	return f.addresses
+}
+
+// Client returns the client of a switch, or nil if the address isn't part of the fleet
+func (f *Fleet) Client(address string) *Client {
+	return f.clients[address]
+}
+
+// Scan calls fn for all switches in parallel and collects the errors, one per failed switch
+func (f *Fleet) Scan(ctx context.Context, fn func(ctx context.Context, client *Client) error) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures = &MultiError{}
+	)
+	for _, address := range f.addresses {
+		client := f.clients[address]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(ctx, client); err != nil {
+				mu.Lock()
+				failures.add(NewOperationError("scan failed for "+client.GetAddress(), err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return failures.errorOrNil()
+}
+
+// Close closes the idle connections of the shared transport
+func (f *Fleet) Close() {
+	if f.transport != nil {
+		f.transport.CloseIdleConnections()
+	}
+}
+
+// poeStatusEntry caches the POE status of a switch for the POEStatusHandler
+type poeStatusEntry struct {
+	mu       sync.Mutex // held while reading the status, so concurrent polls wait for one read
+	statuses []POEPortStatus
+	read     time.Time
+}
+
+// POEStatusHandler serves the live POE status of a switch of the fleet as JSON, a list of POEPortStatus,
+// at GET /api/switch/{host}/poe, e.g. for dashboards or Home Assistant. A status is read from the switch
+// at most once per ttl, so frequent polls don't overload it. Unknown hosts get a 404, failed reads a 502,
+// both with a JSON body {"error": "..."}.
+func (f *Fleet) POEStatusHandler(ttl time.Duration) http.Handler {
+	cache := make(map[string]*poeStatusEntry, len(f.addresses))
+	for _, address := range f.addresses {
+		cache[address] = &poeStatusEntry{}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/switch/{host}/poe", func(w http.ResponseWriter, r *http.Request) {
+		host := r.PathValue("host")
+		client, entry := f.clients[host], cache[host]
+		if client == nil || entry == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown switch " + host})
+			return
+		}
+
+		entry.mu.Lock()
+		defer entry.mu.Unlock()
+		if entry.statuses == nil || client.clock.Now().Sub(entry.read) >= ttl {
+			statuses, err := client.POE().GetStatus(r.Context())
+			if err != nil {
+				writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+				return
+			}
+			entry.statuses = append([]POEPortStatus{}, statuses...) // an empty list is cached as well
+			entry.read = client.clock.Now()
+		}
+		writeJSON(w, http.StatusOK, entry.statuses)
+	})
+	return mux
+}
+
+// writeJSON writes the value as JSON response with the status code
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,166 @@
+package netgear
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+// newTestFleet starts mock switches with distinct port names, addressed by host name to go through the DNS cache
+func newTestFleet(t testing.TB, count int, opts ...FleetOption) (*Fleet, []*mockSwitch) {
+	tokenMgr := NewMemoryTokenManager()
+	var mocks []*mockSwitch
+	var addresses []string
+	for i := 0; i < count; i++ {
+		mock := newMockSwitch(t)
+		ports := newFakeGS30xPorts()
+		ports[1].name = fmt.Sprintf("switch-%d", i)
+		mock.serveGS30xConfig(ports)
+		address := strings.Replace(mock.URL(), "127.0.0.1", "localhost", 1)
+		_ = tokenMgr.StoreToken(context.Background(), address, testToken, ModelGS308EPP)
+		mocks = append(mocks, mock)
+		addresses = append(addresses, address)
+	}
+
+	allOpts := append([]FleetOption{WithClientOptions(WithTokenManager(tokenMgr))}, opts...)
+	fleet, err := NewFleet(addresses, allOpts...)
+	if err != nil {
+		t.Fatalf("failed to create test fleet: %v", err)
+	}
+	t.Cleanup(fleet.Close)
+	return fleet, mocks
+}
+
+func TestFleetScanWithSharedTransport(t *testing.T) {
+	fleet, mocks := newTestFleet(t, 3, WithSharedTransport(0))
+
+	var mu sync.Mutex
+	portNames := make(map[string]string)
+	err := fleet.Scan(context.Background(), func(ctx context.Context, client *Client) error {
+		settings, err := client.Ports().GetPortSettings(ctx, 1)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		portNames[client.GetAddress()] = settings.PortName
+		mu.Unlock()
+		return nil
+	})
+
+	then.AssertThat(t, err, is.Nil())
+	for i, address := range fleet.Addresses() {
+		then.AssertThat(t, portNames[address], is.EqualTo(fmt.Sprintf("switch-%d", i)))
+		then.AssertThat(t, mocks[i].requestsTo("GET", "/PortStatistics.cgi"), has.Length[mockRequest](1))
+	}
+}
+
+func TestFleetScanCollectsFailures(t *testing.T) {
+	fleet, _ := newTestFleet(t, 2, WithSharedTransport(0))
+	failing := fleet.Addresses()[1]
+
+	err := fleet.Scan(context.Background(), func(ctx context.Context, client *Client) error {
+		if client.GetAddress() == failing {
+			return NewNetworkError("switch unreachable", nil)
+		}
+		return nil
+	})
+
+	var multiErr *MultiError
+	then.AssertThat(t, errors.As(err, &multiErr), is.True())
+	then.AssertThat(t, multiErr.Errors, has.Length[error](1))
+	then.AssertThat(t, strings.Contains(multiErr.Error(), failing), is.True())
+}
+
+func TestPOEStatusHandlerCachesWithinTTL(t *testing.T) {
+	status, err := os.ReadFile("../../test-data/GS308EPP/getPoePortStatus.cgi.html")
+	then.AssertThat(t, err, is.Nil())
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	fleet, mocks := newTestFleet(t, 1, WithClientOptions(WithClock(clock)))
+	mocks[0].respond("/getPoePortStatus.cgi", string(status))
+	handler := fleet.POEStatusHandler(10 * time.Second)
+	path := "/api/switch/" + url.PathEscape(fleet.Addresses()[0]) + "/poe"
+
+	poll := func() []POEPortStatus {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		then.AssertThat(t, recorder.Code, is.EqualTo(http.StatusOK))
+		then.AssertThat(t, recorder.Header().Get("Content-Type"), is.EqualTo("application/json"))
+		var statuses []POEPortStatus
+		then.AssertThat(t, json.Unmarshal(recorder.Body.Bytes(), &statuses), is.Nil())
+		return statuses
+	}
+
+	then.AssertThat(t, poll(), has.Length[POEPortStatus](8))
+	then.AssertThat(t, poll(), has.Length[POEPortStatus](8))
+	then.AssertThat(t, mocks[0].requestsTo("GET", "/getPoePortStatus.cgi"), has.Length[mockRequest](1))
+
+	clock.now = clock.now.Add(10 * time.Second)
+	poll()
+	then.AssertThat(t, mocks[0].requestsTo("GET", "/getPoePortStatus.cgi"), has.Length[mockRequest](2))
+}
+
+func TestPOEStatusHandlerErrors(t *testing.T) {
+	fleet, mocks := newTestFleet(t, 1)
+	mocks[0].handle("/getPoePortStatus.cgi", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	handler := fleet.POEStatusHandler(time.Second)
+
+	tests := []struct {
+		name   string
+		host   string
+		status int
+	}{
+		{"unknown switch", "other-switch", http.StatusNotFound},
+		{"failed read", fleet.Addresses()[0], http.StatusBadGateway},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/switch/"+url.PathEscape(test.host)+"/poe", nil))
+
+			then.AssertThat(t, recorder.Code, is.EqualTo(test.status))
+			var body map[string]string
+			then.AssertThat(t, json.Unmarshal(recorder.Body.Bytes(), &body), is.Nil())
+			then.AssertThat(t, body["error"], is.Not(is.EqualTo("")))
+		})
+	}
+}
+
+func BenchmarkFleetScan(b *testing.B) {
+	benchmarks := []struct {
+		name string
+		opts []FleetOption
+	}{
+		{name: "per-client transport"},
+		{name: "shared transport", opts: []FleetOption{WithSharedTransport(0)}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			fleet, _ := newTestFleet(b, 10, bm.opts...)
+			scan := func(ctx context.Context, client *Client) error {
+				_, err := client.Ports().GetSettings(ctx)
+				return err
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := fleet.Scan(context.Background(), scan); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
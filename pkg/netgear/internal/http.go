@@ -5,19 +5,29 @@ import (
 	"crypto/md5"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
 // HTTPClient wraps the standard HTTP client with netgear-specific functionality
 type HTTPClient struct {
-	client  *http.Client
-	baseURL string
-	verbose bool
+	client    *http.Client
+	baseURL   string
+	verbose   bool
+	basicUser string // HTTP Basic Auth, e.g. for switches behind a reverse proxy
+	basicPass string
+	headers   map[string]string // sent with every request, unless the request sets them itself
+	hook      ResponseHook      // nil unless raw responses are captured
 }
 
+// ResponseHook receives each response read by ReadBody, with secrets redacted from the URL and the body
+type ResponseHook func(method, url string, status int, body string)
+
 // NewHTTPClient creates a new HTTP client for netgear switch communication
 func NewHTTPClient(address string, timeout time.Duration, verbose bool) *HTTPClient {
 	// Ensure address has protocol
@@ -38,6 +48,88 @@ func NewHTTPClient(address string, timeout time.Duration, verbose bool) *HTTPCli
 	}
 }
 
+// NewSharedTransport creates a transport with keep-alive connections and a DNS cache,
+// to be shared by the clients of many switches. Requests still go to each client's base URL.
+func NewSharedTransport(dnsTTL time.Duration) *http.Transport {
+	cache := newDNSCache(dnsTTL)
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+	return &http.Transport{
+		DialContext:         cache.dialContext(dialer),
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 2, // the switches' web servers don't cope with many parallel connections
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// dnsCache remembers resolved host names for a while, so scanning many switches by name doesn't resolve them for every request
+type dnsCache struct {
+	ttl     time.Duration
+	lookup  func(ctx context.Context, host string) ([]string, error)
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+}
+
+type dnsEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+func newDNSCache(ttl time.Duration) *dnsCache {
+	return &dnsCache{
+		ttl:     ttl,
+		lookup:  net.DefaultResolver.LookupHost,
+		now:     time.Now,
+		entries: make(map[string]dnsEntry),
+	}
+}
+
+// resolve returns the addresses of a host name, from the cache as long as they didn't expire
+func (c *dnsCache) resolve(ctx context.Context, host string) ([]string, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	addrs, err := c.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.entries[host] = dnsEntry{addrs: addrs, expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+	return addrs, nil
+}
+
+// dialContext dials the cached addresses of the host one after the other, IP addresses are dialed directly
+func (c *dnsCache) dialContext(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil || net.ParseIP(host) != nil {
+			return dialer.DialContext(ctx, network, address)
+		}
+
+		addrs, err := c.resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var lastErr error
+		for _, addr := range addrs {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no addresses found for %s", host)
+		}
+		return nil, lastErr
+	}
+}
+
 // Get performs a GET request
 func (h *HTTPClient) Get(ctx context.Context, path string, headers map[string]string) (*http.Response, error) {
 	return h.request(ctx, "GET", path, nil, headers)
@@ -57,6 +149,15 @@ func (h *HTTPClient) Post(ctx context.Context, path string, data url.Values, hea
 	return h.request(ctx, "POST", path, body, headers)
 }
 
+// PostBody performs a POST request with an already encoded body, e.g. JSON
+func (h *HTTPClient) PostBody(ctx context.Context, path string, contentType string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	headers["Content-Type"] = contentType
+	return h.request(ctx, "POST", path, body, headers)
+}
+
 // request is the internal method for making HTTP requests
 func (h *HTTPClient) request(ctx context.Context, method, path string, body io.Reader, headers map[string]string) (*http.Response, error) {
 	fullURL := h.baseURL + path
@@ -66,11 +167,18 @@ func (h *HTTPClient) request(ctx context.Context, method, path string, body io.R
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Set headers
+	// Set headers, the request's own ones like Cookie and Content-Type win over the defaults
+	for key, value := range h.headers {
+		req.Header.Set(key, value)
+	}
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
 
+	if h.basicUser != "" || h.basicPass != "" {
+		req.SetBasicAuth(h.basicUser, h.basicPass)
+	}
+
 	// Set default User-Agent if not provided
 	if req.Header.Get("User-Agent") == "" {
 		req.Header.Set("User-Agent", "ntgrrc-library/1.0")
@@ -105,6 +213,9 @@ func (h *HTTPClient) ReadBody(resp *http.Response) (string, error) {
 	}
 
 	bodyStr := string(body)
+	if h.hook != nil && resp.Request != nil {
+		h.hook(resp.Request.Method, RedactSecrets(resp.Request.URL.String()), resp.StatusCode, RedactSecrets(bodyStr))
+	}
 	if h.verbose && len(bodyStr) > 0 {
 		// Only show first 500 characters to avoid flooding logs
 		preview := bodyStr
@@ -165,6 +276,45 @@ func (h *HTTPClient) SetVerbose(verbose bool) {
 	h.verbose = verbose
 }
 
+// SetBasicAuth sets credentials sent as HTTP Basic Auth with every request
+func (h *HTTPClient) SetBasicAuth(user, pass string) {
+	h.basicUser = user
+	h.basicPass = pass
+}
+
+// SetDefaultHeaders sets headers sent with every request, e.g. for a proxy
+func (h *HTTPClient) SetDefaultHeaders(headers map[string]string) {
+	h.headers = make(map[string]string, len(headers))
+	for key, value := range headers {
+		h.headers[key] = value
+	}
+}
+
+// SetResponseHook sets the hook receiving every response read by ReadBody, nil removes it
+func (h *HTTPClient) SetResponseHook(hook ResponseHook) {
+	h.hook = hook
+}
+
+// secretPatterns match the session tokens in URLs and pages, the first group is kept
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(Gambit["\s]*[:=]["\s]*)[a-f0-9]+`),
+	regexp.MustCompile(`(?i)(name=["']?Gambit["']?\s+value=["']?)[^"'\s>]+`),
+	regexp.MustCompile(`(SID=)[^;&"'\s]+`),
+}
+
+// RedactSecrets replaces the Gambit and SID session tokens in s with "<redacted>"
+func RedactSecrets(s string) string {
+	for _, pattern := range secretPatterns {
+		s = pattern.ReplaceAllString(s, "${1}<redacted>")
+	}
+	return s
+}
+
+// SetTransport sets the transport for all requests, e.g. one shared by the clients of many switches
+func (h *HTTPClient) SetTransport(transport http.RoundTripper) {
+	h.client.Transport = transport
+}
+
 // GetBaseURL returns the base URL
 func (h *HTTPClient) GetBaseURL() string {
 	return h.baseURL
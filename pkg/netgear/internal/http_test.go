@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestDNSCacheResolvesUntilExpired(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	lookups := 0
+	cache := newDNSCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	cache.lookup = func(ctx context.Context, host string) ([]string, error) {
+		lookups++
+		return []string{"192.168.0.10"}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		addrs, err := cache.resolve(context.Background(), "gs308ep")
+		then.AssertThat(t, err, is.Nil())
+		then.AssertThat(t, addrs, is.EqualTo([]string{"192.168.0.10"}))
+	}
+	then.AssertThat(t, lookups, is.EqualTo(1))
+
+	now = now.Add(2 * time.Minute)
+	_, _ = cache.resolve(context.Background(), "gs308ep")
+	then.AssertThat(t, lookups, is.EqualTo(2))
+}
+
+func TestRedactSecrets(t *testing.T) {
+	then.AssertThat(t, RedactSecrets("/iss/specific/poePortStatus.html?GetData=TRUE&Gambit=a1b2c3"),
+		is.EqualTo("/iss/specific/poePortStatus.html?GetData=TRUE&Gambit=<redacted>"))
+	then.AssertThat(t, RedactSecrets(`<script>var Gambit = "a1b2c3";</script>`), is.EqualTo(`<script>var Gambit = "<redacted>";</script>`))
+	then.AssertThat(t, RedactSecrets(`<input type="hidden" name="Gambit" value="xyz9">`),
+		is.EqualTo(`<input type="hidden" name="Gambit" value="<redacted>">`))
+	then.AssertThat(t, RedactSecrets("Cookie: SID=abc123; path=/"), is.EqualTo("Cookie: SID=<redacted>; path=/"))
+}
@@ -1,14 +1,39 @@
 package internal
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/transform"
 )
 
+// utf8BOM is the byte order mark some firmware versions prefix their pages with
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// minDocumentSize is the size above which a page is considered a real document rather than a stub
+const minDocumentSize = 512
+
+// i18nLabelPattern matches the i18n keys GS30x firmware uses as labels in status pages
+var i18nLabelPattern = regexp.MustCompile(`^ml\d+$`)
+
+// errorStatusLabel is the i18n key labelling the POE error status on GS30x status pages
+const errorStatusLabel = "ml581"
+
+// measurementLabels maps the i18n keys labelling the unitless POE measurements on GS30x status pages to their fields
+var measurementLabels = map[string]string{
+	"ml570": "voltage_v",
+	"ml572": "current_ma",
+	"ml574": "power_w",
+	"ml575": "temperature_c",
+}
+
 // ModelDetector contains logic for detecting Netgear switch models
 type ModelDetector struct{}
 
@@ -48,7 +73,7 @@ func NewPOEDataParser() *POEDataParser {
 func (p *POEDataParser) ParsePOEStatus(content string) ([]map[string]interface{}, error) {
 	var results []map[string]interface{}
 	
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	doc, err := newDocument(content)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
@@ -80,37 +105,108 @@ func (p *POEDataParser) ParsePOEStatus(content string) ([]map[string]interface{}
 		}
 		
 		// Extract voltage, current, and power from poe_port_status divs
+		// The firmware alternates i18n label spans (e.g. "ml581") with their value spans
+		var label string
 		s.Find("div.poe_port_status div div span").Each(func(j int, span *goquery.Selection) {
 			text := strings.TrimSpace(span.Text())
 			if text == "" {
 				return
 			}
+			if i18nLabelPattern.MatchString(text) {
+				label = text
+				return
+			}
+			if label == errorStatusLabel {
+				portData["error_status"] = text
+				label = ""
+				return
+			}
+			if field, ok := measurementLabels[label]; ok {
+				if val, unit, ok := extractMeasurement(text); ok && unit == "" {
+					portData[field] = val
+					label = ""
+					return
+				}
+			}
+			label = ""
 			
 			// Try to extract numeric values
-			if strings.Contains(text, "V") {
-				// Voltage
-				if val := extractNumericValue(text); val > 0 {
-					portData["voltage_v"] = val
-				}
-			} else if strings.Contains(text, "mA") {
-				// Current
-				if val := extractNumericValue(text); val > 0 {
-					portData["current_ma"] = val
+			if val, unit, ok := extractMeasurement(text); ok && unit != "" {
+				if val > 0 {
+					switch unit {
+					case "V":
+						portData["voltage_v"] = val
+					case "mA":
+						portData["current_ma"] = val
+					case "A":
+						portData["current_ma"] = val * 1000
+					case "W":
+						portData["power_w"] = val
+					}
 				}
-			} else if strings.Contains(text, "W") {
-				// Power
-				if val := extractNumericValue(text); val > 0 {
-					portData["power_w"] = val
+			} else if strings.Contains(text, "°C") || strings.Contains(text, "℃") {
+				// Temperature
+				cleaned := strings.NewReplacer("°C", "", "℃", "").Replace(text)
+				if val, err := strconv.ParseFloat(strings.TrimSpace(cleaned), 64); err == nil {
+					portData["temperature_c"] = val
 				}
+			} else if tempStatus := NormalizeTemperatureStatus(text); tempStatus != "" {
+				portData["temperature_status"] = tempStatus
 			}
 		})
 		
 		// Only add if we found at least a port ID
 		if _, hasPortID := portData["port_id"]; hasPortID {
+			deriveTemperatureStatus(portData)
 			results = append(results, portData)
 		}
 	})
 	
+	// Parse GS316 series format (div.port-wrap)
+	if len(results) == 0 {
+		doc.Find("div.port-wrap").Each(func(i int, s *goquery.Selection) {
+			portData := make(map[string]interface{})
+			
+			portID, portName := splitPortNumberAndName(s.Find("span.port-number").Text())
+			if portID == 0 {
+				return
+			}
+			portData["port_id"] = portID
+			if portName != "" {
+				portData["port_name"] = portName
+			}
+			
+			if status := strings.TrimSpace(s.Find("span.Status-text").Text()); status != "" {
+				portData["status"] = status
+			}
+			if powerClass := strings.TrimSpace(s.Find("span.Class-text").Text()); powerClass != "" {
+				portData["power_class"] = powerClass
+			}
+			
+			numericFields := map[string]string{
+				"p.OutputVoltage-text": "voltage_v",
+				"p.OutputCurrent-text": "current_ma",
+				"p.OutputPower-text":   "power_w",
+				"p.Temperature-text":   "temperature_c",
+			}
+			for selector, key := range numericFields {
+				if val, err := strconv.ParseFloat(strings.TrimSpace(s.Find(selector).Text()), 64); err == nil {
+					portData[key] = val
+				}
+			}
+			
+			if faultStatus := strings.TrimSpace(s.Find("p.Fault-Status-text").Text()); faultStatus != "" {
+				portData["error_status"] = faultStatus
+			}
+			if tempStatus := NormalizeTemperatureStatus(s.Find("p.Temperature-Status-text").Text()); tempStatus != "" {
+				portData["temperature_status"] = tempStatus
+			}
+			
+			deriveTemperatureStatus(portData)
+			results = append(results, portData)
+		})
+	}
+	
 	// If no GS30x format found, try generic table parsing as fallback
 	if len(results) == 0 {
 		doc.Find("table").Each(func(i int, table *goquery.Selection) {
@@ -163,11 +259,47 @@ func (p *POEDataParser) ParsePOESettings(content string) ([]map[string]interface
 	var results []map[string]interface{}
 	
 	// Similar to ParsePOEStatus but for settings data
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	doc, err := newDocument(content)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 	
+	// Parse GS30x series format (li.poePortSettingListItem), values are kept as firmware codes
+	doc.Find("li.poePortSettingListItem").Each(func(i int, s *goquery.Selection) {
+		settingsData := make(map[string]interface{})
+		
+		portIDStr, _ := s.Find("input[type=hidden].port").Attr("value")
+		portID, err := strconv.Atoi(portIDStr)
+		if err != nil {
+			return
+		}
+		settingsData["port_id"] = portID
+		settingsData["port_name"], _ = s.Find("input[type=hidden].portName").Attr("value")
+		
+		portPwr, _ := s.Find("input#hidPortPwr").Attr("value")
+		settingsData["enabled"] = portPwr == "1"
+		settingsData["mode"], _ = s.Find("input#hidPwrMode").Attr("value")
+		settingsData["priority"], _ = s.Find("input#hidPortPrio").Attr("value")
+		settingsData["power_limit_type"], _ = s.Find("input#hidLimitType").Attr("value")
+		if limit, exists := s.Find("input.pwrLimit").Attr("value"); exists {
+			if val, err := strconv.ParseFloat(limit, 64); err == nil {
+				settingsData["power_limit_w"] = val
+			}
+		}
+		settingsData["detection_type"], _ = s.Find("input#hidDetecType").Attr("value")
+		longerDetect, _ := s.Find("input.longerDetect").Attr("value")
+		settingsData["longer_detection_time"] = longerDetect == "3"
+		// the time schedule bound to the port, the name is empty for ports without one
+		schedEnable, _ := s.Find("input#hidSchedEnable").Attr("value")
+		settingsData["schedule_enabled"] = schedEnable == "1"
+		settingsData["schedule_name"] = strings.TrimSpace(s.Find("input[type=hidden].schedName").AttrOr("value", ""))
+		
+		results = append(results, settingsData)
+	})
+	if len(results) > 0 {
+		return results, nil
+	}
+	
 	// Parse POE settings from forms or tables
 	doc.Find("form, table").Each(func(i int, element *goquery.Selection) {
 		// Extract POE settings based on the specific HTML structure
@@ -192,6 +324,203 @@ func (p *POEDataParser) ParsePOESettings(content string) ([]map[string]interface
 	return results, nil
 }
 
+// ParsePOEPowerUpConfig parses the per port power-up mode and delay from the POE config page.
+// Ports without power-up fields are skipped, as older firmware doesn't support a power-up delay.
+func (p *POEDataParser) ParsePOEPowerUpConfig(content string) ([]map[string]interface{}, error) {
+	var results []map[string]interface{}
+	
+	doc, err := newDocument(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	
+	// Parse GS30x series format (li.poePortSettingListItem)
+	doc.Find("li.poePortSettingListItem").Each(func(i int, s *goquery.Selection) {
+		mode, exists := s.Find("input#hidPwrUpMode").Attr("value")
+		if !exists {
+			return
+		}
+		portID, err := strconv.Atoi(s.Find("input[type=hidden].port").AttrOr("value", ""))
+		if err != nil {
+			return
+		}
+		delay, _ := strconv.Atoi(s.Find("input.pwrUpDelay").AttrOr("value", "0"))
+		results = append(results, map[string]interface{}{
+			"port_id":       portID,
+			"mode":          normalizePowerUpMode(mode),
+			"delay_seconds": delay,
+		})
+	})
+	
+	// Parse GS316 series format (div.port-wrap)
+	doc.Find("div.port-wrap").Each(func(i int, s *goquery.Selection) {
+		modeSel := s.Find("p.Power-Up-Mode-text")
+		if modeSel.Length() == 0 {
+			return
+		}
+		portID, _ := splitPortNumberAndName(s.Find("span.port-number").Text())
+		if portID == 0 {
+			return
+		}
+		delay, _ := strconv.Atoi(strings.TrimSpace(s.Find("p.Power-Up-Delay-text").Text()))
+		results = append(results, map[string]interface{}{
+			"port_id":       portID,
+			"mode":          normalizePowerUpMode(modeSel.Text()),
+			"delay_seconds": delay,
+		})
+	})
+	
+	return results, nil
+}
+
+// normalizePowerUpMode maps GS30x codes and GS316 wording to "immediate" or "delayed"
+func normalizePowerUpMode(mode string) string {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "0", "immediate":
+		return "immediate"
+	case "1", "delayed", "delay":
+		return "delayed"
+	default:
+		return strings.TrimSpace(mode)
+	}
+}
+
+// ParseThermalStatus parses the system temperature and fan state from a system/dashboard page.
+// GS30x firmware exposes them as hidden inputs, GS316 firmware as text paragraphs.
+func (p *POEDataParser) ParseThermalStatus(content string) (map[string]interface{}, error) {
+	doc, err := newDocument(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	
+	thermalData := make(map[string]interface{})
+	
+	temperature, exists := doc.Find("input#sysTemperature").Attr("value")
+	if !exists {
+		temperature = doc.Find("p.System-Temperature-text").Text()
+	}
+	if val, err := strconv.ParseFloat(strings.TrimSpace(temperature), 64); err == nil {
+		thermalData["temperature_c"] = val
+	}
+	
+	tempStatus, exists := doc.Find("input#sysTemperatureStatus").Attr("value")
+	if !exists {
+		tempStatus = doc.Find("p.System-Temperature-Status-text").Text()
+	}
+	if status := NormalizeTemperatureStatus(tempStatus); status != "" {
+		thermalData["status"] = status
+	}
+	
+	fanStatus, exists := doc.Find("input#fanState").Attr("value")
+	if !exists {
+		fanStatus = doc.Find("p.Fan-Status-text").Text()
+	}
+	if fan := strings.TrimSpace(fanStatus); fan != "" {
+		thermalData["fan_status"] = fan
+	}
+	
+	return thermalData, nil
+}
+
+// ParsePOEPowerBudget parses the switch's total POE power budget and the power currently consumed,
+// from hidden inputs (GS30x) or the budget summary texts (GS316)
+func (p *POEDataParser) ParsePOEPowerBudget(content string) (map[string]interface{}, error) {
+	doc, err := newDocument(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	
+	budgetData := make(map[string]interface{})
+	
+	total, exists := doc.Find("input#poeMaxPower").Attr("value")
+	if !exists {
+		total = doc.Find("p.Power-Budget-text").Text()
+	}
+	if val, ok := parseWatts(total); ok {
+		budgetData["total_power_w"] = val
+	}
+	
+	consumed, exists := doc.Find("input#poeConsumedPower").Attr("value")
+	if !exists {
+		consumed = doc.Find("p.Power-Consumption-text").Text()
+	}
+	if val, ok := parseWatts(consumed); ok {
+		budgetData["used_power_w"] = val
+	}
+	
+	return budgetData, nil
+}
+
+// ParsePOEPowerManagementMode parses the global power management mode from the GS316 POE config page,
+// normalized to "static" or "dynamic". It returns an empty string, if the page doesn't report one.
+func (p *POEDataParser) ParsePOEPowerManagementMode(content string) (string, error) {
+	doc, err := newDocument(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	
+	mode := strings.ToLower(strings.Join(strings.Fields(doc.Find("p.Power-Management-Mode-text").Text()), " "))
+	switch mode {
+	case "static", "static power", "class", "class based", "class-based":
+		return "static", nil
+	case "dynamic", "dynamic power":
+		return "dynamic", nil
+	case "":
+		return "", nil
+	default:
+		return "", fmt.Errorf("unknown power management mode '%s'", mode)
+	}
+}
+
+// parseWatts parses a power value like "61.6" or "61.6 W"
+func parseWatts(text string) (float64, bool) {
+	val, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "W")), 64)
+	return val, err == nil
+}
+
+// NormalizeTemperatureStatus maps firmware temperature wording to Normal, Warning or Critical.
+// It returns an empty string if the text is not a temperature state.
+func NormalizeTemperatureStatus(text string) string {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "normal", "ok":
+		return "Normal"
+	case "warning", "high", "high temperature":
+		return "Warning"
+	case "critical", "over temperature", "over-temperature", "overheat", "thermal shutdown":
+		return "Critical"
+	default:
+		return ""
+	}
+}
+
+// deriveTemperatureStatus fills in the temperature status when the page doesn't report one explicitly:
+// a temperature related fault is critical, otherwise a reported temperature is considered normal
+func deriveTemperatureStatus(portData map[string]interface{}) {
+	if _, exists := portData["temperature_status"]; exists {
+		return
+	}
+	if errorStatus, ok := portData["error_status"].(string); ok {
+		lower := strings.ToLower(errorStatus)
+		if strings.Contains(lower, "temperature") || strings.Contains(lower, "thermal") {
+			portData["temperature_status"] = "Critical"
+			return
+		}
+	}
+	if _, exists := portData["temperature_c"]; exists {
+		portData["temperature_status"] = "Normal"
+	}
+}
+
+// splitPortNumberAndName splits GS316 port labels like "1 - uplink" into port number and name
+func splitPortNumberAndName(text string) (int, string) {
+	number, name, _ := strings.Cut(strings.ReplaceAll(text, "\u00a0", " "), "-")
+	portID, err := strconv.Atoi(strings.TrimSpace(number))
+	if err != nil {
+		return 0, ""
+	}
+	return portID, strings.TrimSpace(name)
+}
+
 // PortDataParser contains logic for parsing port-related data
 type PortDataParser struct{}
 
@@ -204,7 +533,7 @@ func NewPortDataParser() *PortDataParser {
 func (p *PortDataParser) ParsePortSettings(content string) ([]map[string]interface{}, error) {
 	var results []map[string]interface{}
 	
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	doc, err := newDocument(content)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
@@ -250,6 +579,135 @@ func (p *PortDataParser) ParsePortSettings(content string) ([]map[string]interfa
 	return results, nil
 }
 
+// ParsePortLinkStates parses the link state and the link speed of each port of the GS30x dashboard,
+// keyed by port; ports without a link state are left out
+func (p *PortDataParser) ParsePortLinkStates(content string) (map[int]map[string]string, error) {
+	doc, err := newDocument(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	results := make(map[int]map[string]string)
+	doc.Find("li.list_item").Each(func(i int, s *goquery.Selection) {
+		portID, err := strconv.Atoi(s.Find("input[type=hidden].port").AttrOr("value", ""))
+		if err != nil {
+			return
+		}
+		state := strings.TrimSpace(s.Find("div.li_header_content span.pull-right").First().Text())
+		if state == "" {
+			return
+		}
+		results[portID] = map[string]string{
+			"status":     state,
+			"link_speed": strings.TrimSpace(s.Find("input[type=hidden].LinkedSpeed").AttrOr("value", "")),
+		}
+	})
+	return results, nil
+}
+
+// ParsePOEStatusFromReader is ParsePOEStatus for a page read from r, e.g. for fuzzing with arbitrary input
+func (p *POEDataParser) ParsePOEStatusFromReader(r io.Reader) ([]map[string]interface{}, error) {
+	return parseFromReader(r, p.ParsePOEStatus)
+}
+
+// ParsePOESettingsFromReader is ParsePOESettings for a page read from r
+func (p *POEDataParser) ParsePOESettingsFromReader(r io.Reader) ([]map[string]interface{}, error) {
+	return parseFromReader(r, p.ParsePOESettings)
+}
+
+// ParsePortSettingsFromReader is ParsePortSettings for a page read from r
+func (p *PortDataParser) ParsePortSettingsFromReader(r io.Reader) ([]map[string]interface{}, error) {
+	return parseFromReader(r, p.ParsePortSettings)
+}
+
+// parseFromReader reads the page and parses it; a parser panicking on unexpected markup
+// is reported as an error instead of crashing the caller
+func parseFromReader(r io.Reader, parse func(string) ([]map[string]interface{}, error)) (results []map[string]interface{}, err error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			results, err = nil, fmt.Errorf("failed to parse content: %v", recovered)
+		}
+	}()
+	return parse(string(content))
+}
+
+// SystemDataParser handles parsing of switch-wide configuration pages
+type SystemDataParser struct{}
+
+// NewSystemDataParser creates a new system data parser
+func NewSystemDataParser() *SystemDataParser {
+	return &SystemDataParser{}
+}
+
+// ParseDashboard counts the ports of the dashboard page and the connected ones among them.
+// GS30x firmware lists the ports as li.list_item with a link state of UP, GS316 firmware with CONNECTED.
+func (p *SystemDataParser) ParseDashboard(content string) (map[string]interface{}, error) {
+	doc, err := newDocument(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	total, connected := 0, 0
+	doc.Find("li.list_item").Each(func(i int, s *goquery.Selection) {
+		if s.Find("input[type=hidden].port").Length() == 0 {
+			return
+		}
+		total++
+		state := strings.TrimSpace(s.Find("div.li_header_content span.pull-right").First().Text())
+		if strings.EqualFold(state, "UP") {
+			connected++
+		}
+	})
+	doc.Find("div.dashboard-port-status span.status-on-port").Each(func(i int, s *goquery.Selection) {
+		total++
+		if strings.EqualFold(strings.TrimSpace(s.Text()), "CONNECTED") {
+			connected++
+		}
+	})
+	if total == 0 {
+		return nil, fmt.Errorf("no ports found on the dashboard")
+	}
+
+	return map[string]interface{}{
+		"total_ports":     total,
+		"connected_ports": connected,
+	}, nil
+}
+
+// firmwareVersionPattern matches a firmware version like "V1.0.1.1" or "1.0.4.4"
+var firmwareVersionPattern = regexp.MustCompile(`^[vV]?\d+(\.\d+)+$`)
+
+// ParseFirmwareVersion parses the firmware version of the dashboard, empty if the page doesn't show it.
+// GS30x dashboards label the fields with translation keys, so the version is found by its format.
+func (p *SystemDataParser) ParseFirmwareVersion(content string) (string, error) {
+	doc, err := newDocument(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	version := ""
+	doc.Find("div#sysinfoContainer div.hid_info_cell span").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if text := strings.TrimSpace(s.Text()); firmwareVersionPattern.MatchString(text) {
+			version = text
+			return false
+		}
+		return true
+	})
+	doc.Find("p.light-title").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if !strings.EqualFold(strings.TrimSpace(s.Text()), "Firmware Version") {
+			return true
+		}
+		version = strings.TrimSpace(s.NextFiltered("p.bold-title").Text())
+		return false
+	})
+	return version, nil
+}
+
 // ExtractSessionToken extracts session token from response content
 func ExtractSessionToken(content string) string {
 	// Look for SID cookie or session token in various formats
@@ -310,6 +768,94 @@ func ExtractErrorMessage(content string) string {
 	return ""
 }
 
+// ExtractLoginErrorMessage extracts the error, which the login page of the firmware shows after a failed login:
+// the div.pwdErrStyle of GS30x, the #loginPageErrorMsg of GS316
+func ExtractLoginErrorMessage(content string) string {
+	doc, err := newDocument(content)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(doc.Find("div.pwdErrStyle, #loginPageErrorMsg").First().Text())
+}
+
+// IsLoginPage returns true if the content is (or links back to) a switch login page
+func IsLoginPage(content string) bool {
+	return strings.Contains(content, "/login.cgi") ||
+		strings.Contains(content, "/wmi/login") ||
+		strings.Contains(content, "/redirect.html")
+}
+
+// IsUnrecognizedDocument returns true if the content is a substantial HTML document
+// that is not a login page, i.e. a page that should have contained parseable data
+func IsUnrecognizedDocument(content string) bool {
+	return len(content) >= minDocumentSize &&
+		strings.Contains(strings.ToLower(content), "<html") &&
+		!IsLoginPage(content)
+}
+
+// IsJSONContent returns true if the content is a JSON document rather than an HTML page,
+// as returned by the AJAX data endpoints of newer GS316 firmware
+func IsJSONContent(content string) bool {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix([]byte(content), utf8BOM))
+	return len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{')
+}
+
+// Login schemes returned by DetectLoginAuthType
+const (
+	LoginAuthSession = "session"
+	LoginAuthGambit  = "gambit"
+)
+
+// DetectLoginAuthType inspects the password field of a login page's form: LoginPassword means Gambit
+// authentication, password means a SID session. It returns an empty string if the page has neither.
+func DetectLoginAuthType(content string) string {
+	doc, err := newDocument(content)
+	if err != nil {
+		return ""
+	}
+
+	if doc.Find("form input[name=LoginPassword]").Length() > 0 {
+		return LoginAuthGambit
+	}
+	if doc.Find("form input[name=password]").Length() > 0 {
+		return LoginAuthSession
+	}
+	return ""
+}
+
+// writableFormControls matches form controls a user with write privileges can change
+const writableFormControls = "form input:not([type=hidden]):not([disabled]), form select:not([disabled]), " +
+	"form textarea:not([disabled]), form button:not([disabled])"
+
+// IsReadOnlyForm returns true if the content is a page with a form, but without any writable controls,
+// as the firmware renders configuration pages for accounts without admin privileges
+func IsReadOnlyForm(content string) bool {
+	doc, err := newDocument(content)
+	if err != nil {
+		return false
+	}
+	return doc.Find("form").Length() > 0 && doc.Find(writableFormControls).Length() == 0
+}
+
+// formTokenNames are the hidden inputs, in which firmware embeds a security token that writes must echo back
+var formTokenNames = []string{"hash", "csrf_token", "csrfToken", "security_token", "securityToken"}
+
+// ExtractFormToken returns the name and value of the hidden security (CSRF) token of a config page,
+// or empty strings if the page has none
+func ExtractFormToken(content string) (string, string) {
+	doc, err := newDocument(content)
+	if err != nil {
+		return "", ""
+	}
+	for _, name := range formTokenNames {
+		input := doc.Find(fmt.Sprintf("input[type=hidden][name='%s'], input[type=hidden]#%s", name, name)).First()
+		if value := strings.TrimSpace(input.AttrOr("value", "")); value != "" {
+			return name, value
+		}
+	}
+	return "", ""
+}
+
 // ExtractSeedValue extracts the random seed value from login page HTML
 func ExtractSeedValue(content string) string {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
@@ -326,18 +872,36 @@ func ExtractSeedValue(content string) string {
 	return ""
 }
 
-// extractNumericValue extracts a numeric value from a string that may contain units
-func extractNumericValue(text string) float64 {
-	// Remove common units and non-numeric characters
-	cleaned := strings.ReplaceAll(text, "V", "")
-	cleaned = strings.ReplaceAll(cleaned, "mA", "")
-	cleaned = strings.ReplaceAll(cleaned, "W", "")
-	cleaned = strings.ReplaceAll(cleaned, "A", "")
-	cleaned = strings.TrimSpace(cleaned)
-	
-	if val, err := strconv.ParseFloat(cleaned, 64); err == nil {
-		return val
+// newDocument creates a goquery document from switch HTML after normalizing its encoding
+func newDocument(content string) (*goquery.Document, error) {
+	return goquery.NewDocumentFromReader(normalizeEncoding(content))
+}
+
+// normalizeEncoding strips a leading UTF-8 BOM and converts non-UTF-8 pages
+// (e.g. Windows-1252 or UTF-16) to UTF-8 so selections don't pick up garbage
+func normalizeEncoding(content string) io.Reader {
+	raw := bytes.TrimPrefix([]byte(content), utf8BOM)
+	if utf8.Valid(raw) {
+		return bytes.NewReader(raw)
 	}
-	
-	return 0
+
+	enc, _, _ := charset.DetermineEncoding(raw, "")
+	return transform.NewReader(bytes.NewReader(raw), enc.NewDecoder())
+}
+
+// measurementPattern matches a leading number with an optional electrical unit,
+// trailing annotations like "(PoE+)" are ignored, but a unit must not run into a word like "Volt"
+var measurementPattern = regexp.MustCompile(`^\s*([-+]?\d+(?:\.\d+)?)\s*(?:(mA|V|W|A)(?:[^A-Za-z]|$)|[^A-Za-z\s\d.]|$)`)
+
+// extractMeasurement extracts the leading numeric value and its unit (V, mA, A, W or empty) from a text
+func extractMeasurement(text string) (float64, string, bool) {
+	match := measurementPattern.FindStringSubmatch(text)
+	if match == nil {
+		return 0, "", false
+	}
+	val, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return val, match[2], true
 }
\ No newline at end of file
@@ -0,0 +1,353 @@
+package internal
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestParsePOEStatusWithBOM(t *testing.T) {
+	content := "\xEF\xBB\xBF" + `<html><body><ul>` +
+		`<li class="poePortStatusListItem"><input type="hidden" class="port" value="1"/>` +
+		`<span class="poe-port-index"><span>Camera</span></span></li></ul></body></html>`
+
+	results, err := NewPOEDataParser().ParsePOEStatus(content)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, results, has.Length[map[string]interface{}](1))
+	then.AssertThat(t, results[0]["port_id"], is.EqualTo[interface{}](1))
+	then.AssertThat(t, results[0]["port_name"], is.EqualTo[interface{}]("Camera"))
+}
+
+func TestParsePOEStatusWindows1252(t *testing.T) {
+	// "Café" with 0xE9 as the Windows-1252 encoding of 'é'
+	content := `<html><head><meta charset="windows-1252"></head><body><ul>` +
+		`<li class="poePortStatusListItem"><input type="hidden" class="port" value="2"/>` +
+		"<span class=\"poe-port-index\"><span>Caf\xe9</span></span></li></ul></body></html>"
+
+	results, err := NewPOEDataParser().ParsePOEStatus(content)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, results, has.Length[map[string]interface{}](1))
+	then.AssertThat(t, results[0]["port_name"], is.EqualTo[interface{}]("Café"))
+}
+
+func TestExtractMeasurement(t *testing.T) {
+	tests := []struct {
+		text          string
+		expectedValue float64
+		expectedUnit  string
+		expectedOk    bool
+	}{
+		{text: "7.20W (PoE+)", expectedValue: 7.2, expectedUnit: "W", expectedOk: true},
+		{text: "48 V", expectedValue: 48, expectedUnit: "V", expectedOk: true},
+		{text: "150 mA", expectedValue: 150, expectedUnit: "mA", expectedOk: true},
+		{text: "0.15A", expectedValue: 0.15, expectedUnit: "A", expectedOk: true},
+		{text: "30", expectedValue: 30, expectedUnit: "", expectedOk: true},
+		{text: "48 Volt", expectedOk: false},
+		{text: "Delivering Power", expectedOk: false},
+	}
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			value, unit, ok := extractMeasurement(test.text)
+
+			then.AssertThat(t, ok, is.EqualTo(test.expectedOk))
+			then.AssertThat(t, value, is.EqualTo(test.expectedValue))
+			then.AssertThat(t, unit, is.EqualTo(test.expectedUnit))
+		})
+	}
+}
+
+func TestParsePOEStatusWithAnnotatedUnits(t *testing.T) {
+	content := `<html><body><ul>` +
+		`<li class="poePortStatusListItem"><input type="hidden" class="port" value="3"/>` +
+		`<div class="poe_port_status"><div><div>` +
+		`<span>48 V</span><span>150 mA</span><span>7.20W (PoE+)</span>` +
+		`</div></div></div></li></ul></body></html>`
+
+	results, err := NewPOEDataParser().ParsePOEStatus(content)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, results, has.Length[map[string]interface{}](1))
+	then.AssertThat(t, results[0]["voltage_v"], is.EqualTo[interface{}](48.0))
+	then.AssertThat(t, results[0]["current_ma"], is.EqualTo[interface{}](150.0))
+	then.AssertThat(t, results[0]["power_w"], is.EqualTo[interface{}](7.2))
+}
+
+func TestParsePortSettingsWithBOM(t *testing.T) {
+	content := "\xEF\xBB\xBF" + `<table>` +
+		`<tr><th>Port</th><th>Name</th></tr>` +
+		`<tr><td>1</td><td>uplink</td><td>Auto</td></tr>` +
+		`</table>`
+
+	results, err := NewPortDataParser().ParsePortSettings(content)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, results, has.Length[map[string]interface{}](1))
+	then.AssertThat(t, results[0]["port_id"], is.EqualTo[interface{}](1))
+	then.AssertThat(t, results[0]["port_name"], is.EqualTo[interface{}]("uplink"))
+}
+
+func TestParsePOESettingsWindows1252(t *testing.T) {
+	content := `<html><head><meta charset="windows-1252"></head><body>` +
+		"<form><input name=\"portName\" value=\"B\xfcro\"/></form></body></html>"
+
+	results, err := NewPOEDataParser().ParsePOESettings(content)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, results, has.Length[map[string]interface{}](1))
+	then.AssertThat(t, results[0]["portName"], is.EqualTo[interface{}]("Büro"))
+}
+
+func TestParsePOESettingsSchedule(t *testing.T) {
+	content := `<html><body><ul>` +
+		`<li class="poePortSettingListItem"><input type="hidden" class="port" value="1"/>` +
+		`<input type="hidden" id="hidPortPwr" value="1"/>` +
+		`<input type="hidden" id="hidSchedEnable" value="1"/><input type="hidden" class="schedName" value="office hours"/></li>` +
+		`<li class="poePortSettingListItem"><input type="hidden" class="port" value="2"/>` +
+		`<input type="hidden" id="hidPortPwr" value="1"/>` +
+		`<input type="hidden" id="hidSchedEnable" value="0"/><input type="hidden" class="schedName" value=""/></li>` +
+		`</ul></body></html>`
+
+	results, err := NewPOEDataParser().ParsePOESettings(content)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, results, has.Length[map[string]interface{}](2))
+	then.AssertThat(t, results[0]["schedule_enabled"], is.EqualTo[interface{}](true))
+	then.AssertThat(t, results[0]["schedule_name"], is.EqualTo[interface{}]("office hours"))
+	then.AssertThat(t, results[1]["schedule_enabled"], is.EqualTo[interface{}](false))
+	then.AssertThat(t, results[1]["schedule_name"], is.EqualTo[interface{}](""))
+}
+
+func TestParsePOEPowerManagementMode(t *testing.T) {
+	tests := []struct {
+		html     string
+		expected string
+	}{
+		{html: `<p class="Power-Management-Mode-text">Dynamic Power</p>`, expected: "dynamic"},
+		{html: `<p class="Power-Management-Mode-text"> Static </p>`, expected: "static"},
+		{html: `<p class="Power-Management-Mode-text">Class based</p>`, expected: "static"},
+		{html: `<p class="Power-Budget-text">83.0 W</p>`, expected: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.html, func(t *testing.T) {
+			mode, err := NewPOEDataParser().ParsePOEPowerManagementMode(`<html><body>` + test.html + `</body></html>`)
+
+			then.AssertThat(t, err, is.Nil())
+			then.AssertThat(t, mode, is.EqualTo(test.expected))
+		})
+	}
+
+	_, err := NewPOEDataParser().ParsePOEPowerManagementMode(`<p class="Power-Management-Mode-text">LLDP</p>`)
+	then.AssertThat(t, err, is.Not(is.Nil()))
+}
+
+func TestParseFirmwareVersionFromFixtures(t *testing.T) {
+	fixtures := map[string]string{
+		"../../../test-data/GS308EPP/dashboard.cgi.html": "V1.0.1.1",
+		"../../../test-data/GS316EP/dashboard.html":      "1.0.4.4",
+	}
+
+	for file, expected := range fixtures {
+		t.Run(file, func(t *testing.T) {
+			content, err := os.ReadFile(file)
+			then.AssertThat(t, err, is.Nil())
+
+			version, err := NewSystemDataParser().ParseFirmwareVersion(string(content))
+
+			then.AssertThat(t, err, is.Nil())
+			then.AssertThat(t, version, is.EqualTo(expected))
+		})
+	}
+}
+
+func TestExtractFormToken(t *testing.T) {
+	content, err := os.ReadFile("../../../test-data/GS308EPP/PoEPortConfig.cgi.html")
+	then.AssertThat(t, err, is.Nil())
+
+	name, value := ExtractFormToken(string(content))
+	then.AssertThat(t, name, is.EqualTo("hash"))
+	then.AssertThat(t, value, is.EqualTo("5c183d939eee1c74c1bb9055ec82d2d6"))
+
+	name, value = ExtractFormToken(`<form><input type="hidden" name="csrf_token" value="abc123"></form>`)
+	then.AssertThat(t, name, is.EqualTo("csrf_token"))
+	then.AssertThat(t, value, is.EqualTo("abc123"))
+
+	name, _ = ExtractFormToken(`<form><input type="text" name="hash" value="visible"></form>`)
+	then.AssertThat(t, name, is.EqualTo(""))
+}
+
+func TestParsePOEStatusGS316Temperature(t *testing.T) {
+	content, err := os.ReadFile("../../../test-data/GS316EP/poePortStatus_GetData_true.html")
+	then.AssertThat(t, err, is.Nil())
+
+	results, err := NewPOEDataParser().ParsePOEStatus(string(content))
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, results, has.Length[map[string]interface{}](15))
+	then.AssertThat(t, results[0]["port_id"], is.EqualTo[interface{}](1))
+	then.AssertThat(t, results[0]["temperature_c"], is.EqualTo[interface{}](float64(23)))
+	then.AssertThat(t, results[0]["temperature_status"], is.EqualTo[interface{}]("Normal"))
+}
+
+func TestParsePOEStatusGS316OverTemperature(t *testing.T) {
+	content := `<html><body>` +
+		`<div class="port-wrap"><span class="port-number">3&nbsp;-&nbsp;ap</span>` +
+		`<span class="Status-text">Searching</span>` +
+		`<p class="Temperature-text">85</p>` +
+		`<p class="Fault-Status-text">Over Temperature</p></div>` +
+		`<div class="port-wrap"><span class="port-number">4&nbsp;-&nbsp;</span>` +
+		`<p class="Temperature-text">70</p>` +
+		`<p class="Temperature-Status-text">High</p></div>` +
+		`</body></html>`
+
+	results, err := NewPOEDataParser().ParsePOEStatus(content)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, results, has.Length[map[string]interface{}](2))
+	then.AssertThat(t, results[0]["port_name"], is.EqualTo[interface{}]("ap"))
+	then.AssertThat(t, results[0]["temperature_c"], is.EqualTo[interface{}](float64(85)))
+	then.AssertThat(t, results[0]["temperature_status"], is.EqualTo[interface{}]("Critical"))
+	then.AssertThat(t, results[1]["temperature_status"], is.EqualTo[interface{}]("Warning"))
+}
+
+func TestParseThermalStatus(t *testing.T) {
+	gs30x := `<html><body><input type="hidden" id="sysTemperature" value="41">` +
+		`<input type="hidden" id="fanState" value="Normal"></body></html>`
+	gs316 := `<html><body><p class="System-Temperature-text">78</p>` +
+		`<p class="System-Temperature-Status-text">Over Temperature</p>` +
+		`<p class="Fan-Status-text">Fail</p></body></html>`
+
+	normal, err := NewPOEDataParser().ParseThermalStatus(gs30x)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, normal["temperature_c"], is.EqualTo[interface{}](float64(41)))
+	then.AssertThat(t, normal["fan_status"], is.EqualTo[interface{}]("Normal"))
+
+	overTemp, err := NewPOEDataParser().ParseThermalStatus(gs316)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, overTemp["temperature_c"], is.EqualTo[interface{}](float64(78)))
+	then.AssertThat(t, overTemp["status"], is.EqualTo[interface{}]("Critical"))
+	then.AssertThat(t, overTemp["fan_status"], is.EqualTo[interface{}]("Fail"))
+}
+
+func TestParsePOEPowerUpConfig(t *testing.T) {
+	gs30x := `<ul><li class="poePortSettingListItem"><input type="hidden" class="port" value="1">` +
+		`<input type="hidden" id="hidPwrUpMode" value="1"><input type="hidden" class="pwrUpDelay" value="30"></li>` +
+		`<li class="poePortSettingListItem"><input type="hidden" class="port" value="2">` +
+		`<input type="hidden" id="hidPwrUpMode" value="0"><input type="hidden" class="pwrUpDelay" value="0"></li></ul>`
+	gs316 := `<div id="POE_SETTING"><div class="port-wrap"><span class="port-number">3&nbsp;-&nbsp;cam</span>` +
+		`<p class="Power-Up-Mode-text">Delayed</p><p class="Power-Up-Delay-text">45</p></div></div>`
+
+	results, err := NewPOEDataParser().ParsePOEPowerUpConfig(gs30x)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, results, has.Length[map[string]interface{}](2))
+	then.AssertThat(t, results[0]["mode"], is.EqualTo[interface{}]("delayed"))
+	then.AssertThat(t, results[0]["delay_seconds"], is.EqualTo[interface{}](30))
+	then.AssertThat(t, results[1]["mode"], is.EqualTo[interface{}]("immediate"))
+
+	results, err = NewPOEDataParser().ParsePOEPowerUpConfig(gs316)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, results, has.Length[map[string]interface{}](1))
+	then.AssertThat(t, results[0]["port_id"], is.EqualTo[interface{}](3))
+	then.AssertThat(t, results[0]["mode"], is.EqualTo[interface{}]("delayed"))
+	then.AssertThat(t, results[0]["delay_seconds"], is.EqualTo[interface{}](45))
+}
+
+func TestParsePOEPowerUpConfigUnsupportedFirmware(t *testing.T) {
+	for _, fixture := range []string{
+		"../../../test-data/GS308EPP/PoEPortConfig.cgi.html",
+		"../../../test-data/GS316EP/poePortConf.html",
+	} {
+		content, err := os.ReadFile(fixture)
+		then.AssertThat(t, err, is.Nil())
+
+		results, err := NewPOEDataParser().ParsePOEPowerUpConfig(string(content))
+
+		then.AssertThat(t, err, is.Nil())
+		then.AssertThat(t, results, has.Length[map[string]interface{}](0))
+	}
+}
+
+func TestIsJSONContent(t *testing.T) {
+	then.AssertThat(t, IsJSONContent(`[{"port_id": 1}]`), is.True())
+	then.AssertThat(t, IsJSONContent("\xEF\xBB\xBF\n  {\"ports\": []}"), is.True())
+	then.AssertThat(t, IsJSONContent(`<html><body></body></html>`), is.False())
+	then.AssertThat(t, IsJSONContent(""), is.False())
+}
+
+func TestIsReadOnlyForm(t *testing.T) {
+	then.AssertThat(t, IsReadOnlyForm(`<form><input type="hidden" name="hash"><select disabled></select></form>`), is.True())
+	then.AssertThat(t, IsReadOnlyForm(`<form><input type="hidden" name="hash"><select name="speed"></select></form>`), is.False())
+	then.AssertThat(t, IsReadOnlyForm(`<table><tr><td>1</td></tr></table>`), is.False())
+	then.AssertThat(t, IsReadOnlyForm(`SUCCESS`), is.False())
+}
+
+func FuzzParsePOEStatus(f *testing.F) {
+	for _, file := range []string{
+		"../../../test-data/GS308EPP/getPoePortStatus.cgi.html",
+		"../../../test-data/GS316EP/poePortStatus_GetData_true.html",
+	} {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatalf("failed to read %s: %v", file, err)
+		}
+		f.Add(content)
+	}
+	f.Add([]byte(`<li class="poePortStatusListItem"><input type="hidden" class="port" value="x"></li>`))
+	f.Add([]byte{0xEF, 0xBB, 0xBF, 0xFF, 0xFE})
+
+	parser := NewPOEDataParser()
+	f.Fuzz(func(t *testing.T, content []byte) {
+		// must not panic, whatever the firmware sends
+		_, _ = parser.ParsePOEStatusFromReader(bytes.NewReader(content))
+	})
+}
+
+func FuzzParsePortSettings(f *testing.F) {
+	f.Add([]byte(`<table><tr><th>Port</th></tr><tr><td>1</td><td>camera</td><td>Auto</td></tr></table>`))
+	f.Add([]byte(`<table><tr><td>`))
+
+	parser := NewPortDataParser()
+	f.Fuzz(func(t *testing.T, content []byte) {
+		_, _ = parser.ParsePortSettingsFromReader(bytes.NewReader(content))
+	})
+}
+
+func TestParseFromReaderRecoversPanic(t *testing.T) {
+	results, err := parseFromReader(strings.NewReader("<html>"), func(string) ([]map[string]interface{}, error) {
+		panic("index out of range")
+	})
+
+	then.AssertThat(t, results, has.Length[map[string]interface{}](0))
+	then.AssertThat(t, err, is.Not(is.Nil()))
+}
+
+func TestParseDashboard(t *testing.T) {
+	fixtures := []struct {
+		file       string
+		totalPorts int
+		connected  int
+	}{
+		{"../../../test-data/GS308EPP/dashboard.cgi.html", 8, 1},
+		{"../../../test-data/GS316EP/dashboard.html", 16, 2},
+	}
+
+	for _, fixture := range fixtures {
+		content, err := os.ReadFile(fixture.file)
+		then.AssertThat(t, err, is.Nil())
+
+		result, err := NewSystemDataParser().ParseDashboard(string(content))
+
+		then.AssertThat(t, err, is.Nil())
+		then.AssertThat(t, result["total_ports"], is.EqualTo[interface{}](fixture.totalPorts))
+		then.AssertThat(t, result["connected_ports"], is.EqualTo[interface{}](fixture.connected))
+	}
+
+	_, err := NewSystemDataParser().ParseDashboard("<html></html>")
+	then.AssertThat(t, err, is.Not(is.Nil()))
+}
@@ -0,0 +1,196 @@
+package netgear
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testToken = "test-token"
+
+// mockRequest records a single request received by the mock switch
+type mockRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Form   url.Values
+	Header http.Header
+}
+
+// mockSwitch is a minimal HTTP server standing in for a Netgear switch
+type mockSwitch struct {
+	server   *httptest.Server
+	mu       sync.Mutex
+	requests []mockRequest
+	handlers map[string]http.HandlerFunc
+}
+
+// newMockSwitch starts a mock switch; unregistered paths answer 200 with an empty body
+func newMockSwitch(t testing.TB) *mockSwitch {
+	m := &mockSwitch{handlers: make(map[string]http.HandlerFunc)}
+	m.server = httptest.NewServer(http.HandlerFunc(m.serve))
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockSwitch) serve(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	m.mu.Lock()
+	m.requests = append(m.requests, mockRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Form:   r.PostForm,
+		Header: r.Header.Clone(),
+	})
+	handler, ok := m.handlers[r.Method+" "+r.URL.Path]
+	if !ok {
+		handler, ok = m.handlers[r.URL.Path]
+	}
+	m.mu.Unlock()
+
+	if ok {
+		handler(w, r)
+	}
+}
+
+// handle registers a handler for a path, optionally prefixed with a method ("POST /x")
+func (m *mockSwitch) handle(pattern string, handler http.HandlerFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[pattern] = handler
+}
+
+// respond registers a handler that always writes the given body
+func (m *mockSwitch) respond(pattern string, body string) {
+	m.handle(pattern, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+// requestsTo returns all recorded requests matching method and path
+func (m *mockSwitch) requestsTo(method, path string) []mockRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []mockRequest
+	for _, req := range m.requests {
+		if req.Method == method && req.Path == path {
+			result = append(result, req)
+		}
+	}
+	return result
+}
+
+// URL returns the base URL of the mock switch
+func (m *mockSwitch) URL() string {
+	return m.server.URL
+}
+
+// newTestClient creates a client for the mock switch that is already authenticated
+func newTestClient(t *testing.T, m *mockSwitch, model Model, opts ...ClientOption) *Client {
+	tokenMgr := NewMemoryTokenManager()
+	_ = tokenMgr.StoreToken(context.Background(), m.URL(), testToken, model)
+
+	allOpts := append([]ClientOption{WithTokenManager(tokenMgr)}, opts...)
+	client, err := NewClient(m.URL(), allOpts...)
+	if err != nil {
+		t.Fatalf("failed to create test client: %v", err)
+	}
+	return client
+}
+
+// fakeGS30xPort holds the configuration of one port of a stateful GS30x mock
+type fakeGS30xPort struct {
+	name, speed, ingress, egress, flowControl        string
+	poeEnabled, mode, priority, limitType, detection string
+	limitW                                           string
+	powerUpMode, powerUpDelay                        string // not rendered if empty, like firmware without power-up config
+}
+
+// serveGS30xConfig makes the mock behave like GS30x firmware for the port and POE config pages:
+// a POST replaces all fields of a port, so fields missing from the form are reset to their defaults
+func (m *mockSwitch) serveGS30xConfig(ports map[int]*fakeGS30xPort) {
+	formValue := func(r *http.Request, key, fallback string) string {
+		if v := r.PostForm.Get(key); v != "" {
+			return v
+		}
+		return fallback
+	}
+	portFromForm := func(r *http.Request) *fakeGS30xPort {
+		id, _ := strconv.Atoi(r.PostForm.Get("port"))
+		return ports[id]
+	}
+
+	m.handle("GET /PortStatistics.cgi", func(w http.ResponseWriter, r *http.Request) {
+		var b strings.Builder
+		b.WriteString("<table><tr><th>Port</th></tr>")
+		for id := 1; id <= len(ports); id++ {
+			p := ports[id]
+			fmt.Fprintf(&b, "<tr><td>%d</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>",
+				id, p.name, p.speed, p.ingress, p.egress, p.flowControl)
+		}
+		b.WriteString("</table>")
+		_, _ = w.Write([]byte(b.String()))
+	})
+	m.handle("POST /port_status.cgi", func(w http.ResponseWriter, r *http.Request) {
+		var p *fakeGS30xPort
+		for key := range r.PostForm {
+			if id, err := strconv.Atoi(strings.TrimPrefix(key, "port")); err == nil && r.PostForm.Get(key) == "checked" {
+				p = ports[id]
+			}
+		}
+		rateLimit := func(key string) string {
+			if i, err := strconv.Atoi(r.PostForm.Get(key)); err == nil && i >= 1 && i <= len(portRateLimits) {
+				return portRateLimits[i-1]
+			}
+			return "No Limit"
+		}
+		p.name = r.PostForm.Get("DESCRIPTION")
+		p.speed = formValue(r, "SPEED", "auto")
+		p.ingress = rateLimit("IngressRate")
+		p.egress = rateLimit("EgressRate")
+		p.flowControl = map[string]string{"1": "On"}[r.PostForm.Get("FLOW_CONTROL")]
+		if p.flowControl == "" {
+			p.flowControl = "Off"
+		}
+	})
+	m.handle("GET /PoEPortConfig.cgi", func(w http.ResponseWriter, r *http.Request) {
+		var b strings.Builder
+		b.WriteString("<ul>")
+		for id := 1; id <= len(ports); id++ {
+			p := ports[id]
+			fmt.Fprintf(&b, `<li class="poePortSettingListItem"><input type="hidden" class="port" value="%d">`+
+				`<input type="hidden" class="portName" value="%s"><input id="hidPortPwr" value="%s">`+
+				`<input id="hidPwrMode" value="%s"><input id="hidPortPrio" value="%s">`+
+				`<input id="hidLimitType" value="%s"><input class="pwrLimit" value="%s">`+
+				`<input id="hidDetecType" value="%s">`,
+				id, p.name, p.poeEnabled, p.mode, p.priority, p.limitType, p.limitW, p.detection)
+			if p.powerUpMode != "" {
+				fmt.Fprintf(&b, `<input id="hidPwrUpMode" value="%s"><input class="pwrUpDelay" value="%s">`, p.powerUpMode, p.powerUpDelay)
+			}
+			b.WriteString("</li>")
+		}
+		b.WriteString("</ul>")
+		_, _ = w.Write([]byte(b.String()))
+	})
+	m.handle("POST /PoEPortConfig.cgi", func(w http.ResponseWriter, r *http.Request) {
+		p := portFromForm(r)
+		p.name = r.PostForm.Get("port_name")
+		p.poeEnabled = formValue(r, "enabled", "0")
+		p.mode = formValue(r, "mode", "0")
+		p.priority = formValue(r, "priority", "0")
+		p.limitType = formValue(r, "power_limit_type", "0")
+		p.limitW = formValue(r, "power_limit_w", "0")
+		p.detection = formValue(r, "detection_type", "1")
+		if p.powerUpMode != "" {
+			p.powerUpMode = formValue(r, "power_up_mode", "immediate")
+			p.powerUpDelay = formValue(r, "power_up_delay", "0")
+		}
+	})
+}
@@ -1,5 +1,14 @@
 package netgear
 
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // Model represents a Netgear switch model
 type Model string
 
@@ -13,24 +22,35 @@ const (
 	ModelGS30xEPx Model = "GS30xEPx"
 )
 
-// IsModel30x returns true if the model is part of the 30x series
-func (m Model) IsModel30x() bool {
+// ModelSeries groups the models sharing the same firmware web interface
+type ModelSeries string
+
+const (
+	Series30x     ModelSeries = "GS30x"
+	Series316     ModelSeries = "GS316"
+	SeriesUnknown ModelSeries = "unknown"
+)
+
+// Series returns the series of the model, for switching on the web interface to use
+func (m Model) Series() ModelSeries {
 	switch m {
 	case ModelGS305EP, ModelGS305EPP, ModelGS308EP, ModelGS308EPP, ModelGS30xEPx:
-		return true
+		return Series30x
+	case ModelGS316EP, ModelGS316EPP:
+		return Series316
 	default:
-		return false
+		return SeriesUnknown
 	}
 }
 
+// IsModel30x returns true if the model is part of the 30x series
+func (m Model) IsModel30x() bool {
+	return m.Series() == Series30x
+}
+
 // IsModel316 returns true if the model is part of the 316 series
 func (m Model) IsModel316() bool {
-	switch m {
-	case ModelGS316EP, ModelGS316EPP:
-		return true
-	default:
-		return false
-	}
+	return m.Series() == Series316
 }
 
 // IsSupported returns true if the model is supported
@@ -44,17 +64,343 @@ func (m Model) IsSupported() bool {
 	}
 }
 
+// Capabilities describes which features the firmware of a model offers, e.g. to decide
+// which actions to offer in a UI
+type Capabilities struct {
+	POE          bool `json:"poe"`
+	POESchedule  bool `json:"poe_schedule"`
+	PowerUpDelay bool `json:"power_up_delay"`
+	VLAN         bool `json:"vlan"`
+	LAG          bool `json:"lag"`
+	LLDP         bool `json:"lldp"`
+	CableTest    bool `json:"cable_test"`
+	PortSecurity bool `json:"port_security"`
+}
+
+// gs30xCapabilities are shared by all models of the 30x series
+var gs30xCapabilities = Capabilities{
+	POE:          true,
+	PowerUpDelay: true,
+	VLAN:         true,
+	CableTest:    true,
+	PortSecurity: true,
+}
+
+// gs316Capabilities are shared by all models of the 316 series
+var gs316Capabilities = Capabilities{
+	POE:          true,
+	POESchedule:  true,
+	PowerUpDelay: true,
+	VLAN:         true,
+	LAG:          true,
+	LLDP:         true,
+	CableTest:    true,
+	PortSecurity: true,
+}
+
+// modelCapabilities is the registry of the features per supported model
+var modelCapabilities = map[Model]Capabilities{
+	ModelGS305EP:  gs30xCapabilities,
+	ModelGS305EPP: gs30xCapabilities,
+	ModelGS308EP:  gs30xCapabilities,
+	ModelGS308EPP: gs30xCapabilities,
+	ModelGS30xEPx: gs30xCapabilities,
+	ModelGS316EP:  gs316Capabilities,
+	ModelGS316EPP: gs316Capabilities,
+}
+
+// Capabilities returns the features of the model; unsupported models have none
+func (m Model) Capabilities() Capabilities {
+	return modelCapabilities[m]
+}
+
+// PortCount returns the number of switch ports of the model, 0 for unsupported models.
+// The generic GS30xEPx is assumed to be the larger 8 port variant.
+func (m Model) PortCount() int {
+	switch m {
+	case ModelGS305EP, ModelGS305EPP:
+		return 5
+	case ModelGS308EP, ModelGS308EPP, ModelGS30xEPx:
+		return 8
+	case ModelGS316EP, ModelGS316EPP:
+		return 16
+	default:
+		return 0
+	}
+}
+
+// POEPortCount returns the number of ports of the model which can power devices
+func (m Model) POEPortCount() int {
+	switch m {
+	case ModelGS305EP, ModelGS305EPP:
+		return 4
+	case ModelGS316EP, ModelGS316EPP:
+		return 15
+	default:
+		return m.PortCount()
+	}
+}
+
+// ValidatePortID checks that the port exists on the model, before any request is sent to the switch
+func (m Model) ValidatePortID(portID int) error {
+	return validatePortID(m, portID, m.PortCount())
+}
+
+// ValidatePOEPortID checks that the port exists and can power devices on the model
+func (m Model) ValidatePOEPortID(portID int) error {
+	return validatePortID(m, portID, m.POEPortCount())
+}
+
+// validatePortID checks that the port exists on the model, models with unknown port count accept any positive port
+func validatePortID(model Model, portID int, count int) error {
+	if portID < 1 || (count > 0 && portID > count) {
+		return NewOperationError(fmt.Sprintf("port %d out of range (%s has %d ports)", portID, model, count), nil)
+	}
+	return nil
+}
+
+// checkUniquePortIDs rejects parsed pages listing a port more than once, which only malformed firmware HTML does
+func checkUniquePortIDs[T any](items []T, portID func(T) int) error {
+	seen := make(map[int]bool, len(items))
+	for _, item := range items {
+		id := portID(item)
+		if seen[id] {
+			return NewParsingError(fmt.Sprintf("port %d is listed more than once", id), ErrInvalidResponse)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
 // POEPortStatus represents the status of a POE port
 type POEPortStatus struct {
-	PortID       int     `json:"port_id"`
-	PortName     string  `json:"port_name"`
-	Status       string  `json:"status"`
-	PowerClass   string  `json:"power_class"`
-	VoltageV     float64 `json:"voltage_v"`
-	CurrentMA    float64 `json:"current_ma"`
-	PowerW       float64 `json:"power_w"`
+	PortID            int      `json:"port_id"`
+	PortName          string   `json:"port_name"`
+	Status            string   `json:"status"`
+	PowerClass        string   `json:"power_class"`
+	VoltageV          float64  `json:"voltage_v"`
+	CurrentMA         float64  `json:"current_ma"`
+	PowerW            float64  `json:"power_w"`
+	TemperatureC      float64  `json:"temperature_c"`
+	TemperatureStatus string   `json:"temperature_status"`
+	ErrorStatus       string   `json:"error_status"`
+	Fault             POEFault `json:"fault"`
+	FaultReason       string   `json:"fault_reason"`
+	// UnparsedReadings names the readings, e.g. "power_w", which the switch reported as text like "N/A";
+	// their values are 0, but not measured
+	UnparsedReadings []string `json:"unparsed_readings,omitempty"`
+}
+
+// IsReadingValid reports whether the reading, named like its JSON field, was parsed from a number
+func (s POEPortStatus) IsReadingValid(field string) bool {
+	return !slices.Contains(s.UnparsedReadings, field)
+}
+
+// DefaultPowerTolerance is the share by which the reported power may deviate from voltage × current,
+// the firmware rounds voltage and current before reporting them
+const DefaultPowerTolerance = 0.2
+
+// minPowerDeviationW is the deviation always tolerated, as rounding weighs heavily on small readings
+const minPowerDeviationW = 0.5
+
+// PowerFromVI computes the power in watts from the reported voltage and current
+func (s POEPortStatus) PowerFromVI() float64 {
+	return s.VoltageV * s.CurrentMA / 1000
+}
+
+// IsDelivering reports whether the port delivers power, by its status or a power reading above zero
+func (s POEPortStatus) IsDelivering() bool {
+	return s.PowerW > 0 || strings.EqualFold(s.Status, "Delivering Power")
+}
+
+// IsPowerConsistent reports whether the reported power matches voltage × current within the
+// tolerance, a share of the larger value. Large discrepancies usually indicate a parse error.
+func (s POEPortStatus) IsPowerConsistent(tolerance float64) bool {
+	computed := s.PowerFromVI()
+	allowed := math.Max(tolerance*math.Max(s.PowerW, computed), minPowerDeviationW)
+	return math.Abs(s.PowerW-computed) <= allowed
+}
+
+// POEFault represents a normalized POE port error state
+type POEFault string
+
+const (
+	POEFaultNone            POEFault = "none"
+	POEFaultOverload        POEFault = "overload"
+	POEFaultShortCircuit    POEFault = "short_circuit"
+	POEFaultOverTemperature POEFault = "over_temperature"
+	POEFaultPowerDenied     POEFault = "power_denied"
+	POEFaultPortDisabled    POEFault = "port_disabled"
+	POEFaultMPSAbsent       POEFault = "mps_absent"
+	POEFaultInvalidPD       POEFault = "invalid_pd"
+	POEFaultVoltage         POEFault = "voltage_out_of_range"
+	POEFaultUnknown         POEFault = "unknown"
+)
+
+// poeFaultPhrases maps lowercased firmware error wording of GS30x and GS316 to a POEFault
+var poeFaultPhrases = map[string]POEFault{
+	"no error":              POEFaultNone,
+	"none":                  POEFaultNone,
+	"overload":              POEFaultOverload,
+	"over load":             POEFaultOverload,
+	"over-load":             POEFaultOverload,
+	"overcurrent":           POEFaultOverload,
+	"over current":          POEFaultOverload,
+	"short":                 POEFaultShortCircuit,
+	"short circuit":         POEFaultShortCircuit,
+	"over temperature":      POEFaultOverTemperature,
+	"over-temperature":      POEFaultOverTemperature,
+	"overtemperature":       POEFaultOverTemperature,
+	"thermal shutdown":      POEFaultOverTemperature,
+	"power denied":          POEFaultPowerDenied,
+	"power budget exceeded": POEFaultPowerDenied,
+	"port disabled":         POEFaultPortDisabled,
+	"disabled":              POEFaultPortDisabled,
+	"mps absent":            POEFaultMPSAbsent,
+	"invalid pd":            POEFaultInvalidPD,
+	"invalid signature":     POEFaultInvalidPD,
+	"voltage out of range":  POEFaultVoltage,
+	"under voltage":         POEFaultVoltage,
+	"over voltage":          POEFaultVoltage,
+}
+
+// poeFaultReasons describes the cause of each POEFault in plain words
+var poeFaultReasons = map[POEFault]string{
+	POEFaultOverload:        "the powered device draws more than the port's power limit",
+	POEFaultShortCircuit:    "short circuit in the cable or the powered device",
+	POEFaultOverTemperature: "the port was shut off as the POE controller overheated",
+	POEFaultPowerDenied:     "the switch denied power to the port",
+	POEFaultPortDisabled:    "POE is disabled on the port",
+	POEFaultMPSAbsent:       "the powered device stopped drawing the current to keep its power (MPS)",
+	POEFaultInvalidPD:       "the connected device has no valid POE signature",
+	POEFaultVoltage:         "the port voltage is out of range",
+}
+
+// poeFaultDetailReasons describes the details, which firmware appends in parentheses, like in
+// "Power Denied (budget exceeded)"
+var poeFaultDetailReasons = map[string]string{
+	"budget exceeded":       "the switch's POE power budget is exceeded",
+	"power budget exceeded": "the switch's POE power budget is exceeded",
+}
+
+// splitPOEFaultDetail splits a raw error status into its normalized, lowercased phrase
+// and the detail in parentheses, if any
+func splitPOEFaultDetail(errorStatus string) (string, string) {
+	phrase := strings.ToLower(strings.Join(strings.Fields(errorStatus), " "))
+	open := strings.Index(phrase, "(")
+	if open < 0 || !strings.HasSuffix(phrase, ")") {
+		return phrase, ""
+	}
+	return strings.TrimSpace(phrase[:open]), strings.TrimSpace(phrase[open+1 : len(phrase)-1])
+}
+
+// ParsePOEFault maps a raw firmware error status to a POEFault, unknown phrases map to POEFaultUnknown.
+// A detail in parentheses, like in "Power Denied (budget exceeded)", doesn't change the fault.
+func ParsePOEFault(errorStatus string) POEFault {
+	phrase, _ := splitPOEFaultDetail(errorStatus)
+	if fault, ok := poeFaultPhrases[phrase]; ok {
+		return fault
+	}
+	return POEFaultUnknown
+}
+
+// ParsePOEFaultReason describes the cause of a raw firmware error status, more detailed than
+// ParsePOEFault. It is empty without a fault, and the trimmed status for unknown phrases.
+func ParsePOEFaultReason(errorStatus string) string {
+	fault := ParsePOEFault(errorStatus)
+	if fault == POEFaultNone || strings.TrimSpace(errorStatus) == "" {
+		return ""
+	}
+	_, detail := splitPOEFaultDetail(errorStatus)
+	if reason, ok := poeFaultDetailReasons[detail]; ok {
+		return reason
+	}
+	reason, ok := poeFaultReasons[fault]
+	if !ok {
+		return strings.Join(strings.Fields(errorStatus), " ")
+	}
+	if detail != "" {
+		reason += " (" + detail + ")"
+	}
+	return reason
+}
+
+// Temperature states reported in POEPortStatus.TemperatureStatus and ThermalStatus.Status
+const (
+	TemperatureStatusNormal   = "Normal"
+	TemperatureStatusWarning  = "Warning"
+	TemperatureStatusCritical = "Critical"
+)
+
+// ThermalStatus represents the system-level temperature and fan state of a switch
+type ThermalStatus struct {
+	Available    bool    `json:"available"`
 	TemperatureC float64 `json:"temperature_c"`
-	ErrorStatus  string  `json:"error_status"`
+	Status       string  `json:"status"`
+	FanStatus    string  `json:"fan_status"`
+}
+
+// Dashboard summarizes the state of a switch in one call, e.g. for the home screen of a UI
+type Dashboard struct {
+	TotalPorts     int      `json:"total_ports"`
+	ConnectedPorts int      `json:"connected_ports"`
+	POEPortsActive int      `json:"poe_ports_active"`
+	TotalPOEPowerW float64  `json:"total_poe_power_w"`
+	Alarms         []string `json:"alarms"` // e.g. POE faults or a critical temperature, empty if all is well
+}
+
+// SystemInfo describes the switch and the firmware it runs
+type SystemInfo struct {
+	Model           Model  `json:"model"`
+	FirmwareVersion string `json:"firmware_version"` // e.g. "V1.0.1.1", empty if the dashboard doesn't show it
+}
+
+// Snapshot holds the POE and port settings of a switch, e.g. exported as JSON to apply it again later
+type Snapshot struct {
+	Model     Model             `json:"model"`
+	Timestamp time.Time         `json:"timestamp"`
+	POE       []POEPortSettings `json:"poe"`
+	Ports     []PortSettings    `json:"ports"`
+}
+
+// SnapshotChanges are the updates, which bring a switch to the settings of a snapshot
+type SnapshotChanges struct {
+	POE   []POEPortUpdate `json:"poe"`
+	Ports []PortUpdate    `json:"ports"`
+}
+
+// POEPowerBudget represents the switch's total POE power budget and its current usage
+type POEPowerBudget struct {
+	TotalPowerW     float64 `json:"total_power_w"`
+	UsedPowerW      float64 `json:"used_power_w"`
+	RemainingPowerW float64 `json:"remaining_power_w"`
+}
+
+// UsagePercent returns the consumed power as percentage of the total budget
+func (b POEPowerBudget) UsagePercent() float64 {
+	if b.TotalPowerW <= 0 {
+		return 0
+	}
+	return b.UsedPowerW / b.TotalPowerW * 100
+}
+
+// POE power management modes of GS316 switches, which decide how the power budget is allocated to the ports
+const (
+	// POEPowerManagementStatic reserves the power limit (or class maximum) of every powered port
+	POEPowerManagementStatic = "static"
+	// POEPowerManagementDynamic allocates only the power the ports actually consume
+	POEPowerManagementDynamic = "dynamic"
+)
+
+// validatePOEPowerManagementMode accepts the power management modes the firmware offers
+func validatePOEPowerManagementMode(mode string) error {
+	switch mode {
+	case POEPowerManagementStatic, POEPowerManagementDynamic:
+		return nil
+	default:
+		return NewOperationError(fmt.Sprintf("invalid POE power management mode '%s', allowed are %s and %s",
+			mode, POEPowerManagementStatic, POEPowerManagementDynamic), nil)
+	}
 }
 
 // POEPortSettings represents POE port configuration
@@ -68,18 +414,24 @@ type POEPortSettings struct {
 	PowerLimitW         float64      `json:"power_limit_w"`
 	DetectionType       string       `json:"detection_type"`
 	LongerDetectionTime bool         `json:"longer_detection_time"`
+	ScheduleEnabled     bool         `json:"schedule_enabled"`
+	ScheduleName        string       `json:"schedule_name"`
 }
 
-// PortSettings represents switch port configuration
+// PortSettings represents switch port configuration.
+// The Kbps limits are parsed from the raw limits, NoRateLimitKbps without a limit and -1 if unknown.
 type PortSettings struct {
-	PortID       int        `json:"port_id"`
-	PortName     string     `json:"port_name"`
-	Speed        PortSpeed  `json:"speed"`
-	IngressLimit string     `json:"ingress_limit"`
-	EgressLimit  string     `json:"egress_limit"`
-	FlowControl  bool       `json:"flow_control"`
-	Status       PortStatus `json:"status"`
-	LinkSpeed    string     `json:"link_speed"`
+	PortID           int        `json:"port_id"`
+	PortName         string     `json:"port_name"`
+	Description      string     `json:"description"` // the PortName, the firmware has no separate description
+	Speed            PortSpeed  `json:"speed"`
+	IngressLimit     string     `json:"ingress_limit"`
+	EgressLimit      string     `json:"egress_limit"`
+	IngressLimitKbps int        `json:"ingress_limit_kbps"`
+	EgressLimitKbps  int        `json:"egress_limit_kbps"`
+	FlowControl      bool       `json:"flow_control"`
+	Status           PortStatus `json:"status"`
+	LinkSpeed        string     `json:"link_speed"`
 }
 
 // POEMode represents POE power mode
@@ -110,7 +462,8 @@ const (
 	POELimitTypeUser  POELimitType = "user"
 )
 
-// PortSpeed represents port speed configuration
+// PortSpeed represents port speed configuration. PortSpeedAuto negotiates speed and duplex with the link
+// partner, the fixed speeds force them with auto-negotiation off, e.g. for legacy devices.
 type PortSpeed string
 
 const (
@@ -122,6 +475,62 @@ const (
 	PortSpeedDisable  PortSpeed = "disable"
 )
 
+// portSpeedFormValues maps the speeds to the option values of the firmware's port configuration form
+var portSpeedFormValues = map[PortSpeed]string{
+	PortSpeedAuto:     "1",
+	PortSpeedDisable:  "2",
+	PortSpeed10MHalf:  "3",
+	PortSpeed10MFull:  "4",
+	PortSpeed100MHalf: "5",
+	PortSpeed100MFull: "6",
+}
+
+// portSpeedVariants maps speeds, lower-cased and stripped of units and separators, to the defined constants
+var portSpeedVariants = map[string]PortSpeed{
+	"auto":     PortSpeedAuto,
+	"disable":  PortSpeedDisable,
+	"disabled": PortSpeedDisable,
+	"10half":   PortSpeed10MHalf,
+	"10full":   PortSpeed10MFull,
+	"100half":  PortSpeed100MHalf,
+	"100full":  PortSpeed100MFull,
+}
+
+// FormValue returns the value the firmware's port configuration form expects for the speed
+func (s PortSpeed) FormValue() string {
+	if value, ok := portSpeedFormValues[normalizePortSpeed(string(s))]; ok {
+		return value
+	}
+	return string(s)
+}
+
+// AutoNegotiation reports whether the port negotiates speed and duplex, false for fixed speeds and disabled ports
+func (s PortSpeed) AutoNegotiation() bool {
+	return normalizePortSpeed(string(s)) == PortSpeedAuto
+}
+
+// normalizePortSpeed canonicalizes the firmware's renderings of a speed, like "100M Full",
+// "100 Mbps Full", "100M/Full" or the form value "6", to the defined constants.
+// Unknown speeds are returned unchanged.
+func normalizePortSpeed(speed string) PortSpeed {
+	speed = strings.TrimSpace(speed)
+	for constant, value := range portSpeedFormValues {
+		if speed == value {
+			return constant
+		}
+	}
+
+	key := strings.ToLower(speed)
+	for _, unit := range []string{"mbit/s", "mb/s", "mbps", "m"} {
+		key = strings.Replace(key, unit, "", 1)
+	}
+	key = strings.NewReplacer(" ", "", "/", "", "-", "", "_", "").Replace(key)
+	if constant, ok := portSpeedVariants[key]; ok {
+		return constant
+	}
+	return PortSpeed(speed)
+}
+
 // PortStatus represents port status
 type PortStatus string
 
@@ -131,23 +540,213 @@ const (
 	PortStatusDisabled  PortStatus = "disabled"
 )
 
+// POEPowerUpMode represents when a POE port powers up after the switch boots
+type POEPowerUpMode string
+
+const (
+	POEPowerUpModeImmediate POEPowerUpMode = "immediate"
+	POEPowerUpModeDelayed   POEPowerUpMode = "delayed"
+)
+
+// MaxPOEPowerUpDelaySeconds is the longest power-up delay the firmware accepts
+const MaxPOEPowerUpDelaySeconds = 300
+
+// POEPowerUpConfig represents the power-up behaviour of a POE port, used to stagger inrush current on boot
+type POEPowerUpConfig struct {
+	PortID       int            `json:"port_id"`
+	Mode         POEPowerUpMode `json:"mode"`
+	DelaySeconds int            `json:"delay_seconds"`
+}
+
+// Validate checks the power-up mode and that the delay is within the supported bounds
+func (c POEPowerUpConfig) Validate() error {
+	switch c.Mode {
+	case POEPowerUpModeImmediate:
+		if c.DelaySeconds != 0 {
+			return NewOperationError("a power-up delay requires the delayed power-up mode", nil)
+		}
+	case POEPowerUpModeDelayed:
+		if c.DelaySeconds < 1 || c.DelaySeconds > MaxPOEPowerUpDelaySeconds {
+			return NewOperationError(fmt.Sprintf("power-up delay must be between 1 and %d seconds", MaxPOEPowerUpDelaySeconds), nil)
+		}
+	default:
+		return NewOperationError(fmt.Sprintf("invalid power-up mode '%s', allowed are %s and %s",
+			c.Mode, POEPowerUpModeImmediate, POEPowerUpModeDelayed), nil)
+	}
+	return nil
+}
+
 // POEPortUpdate represents changes to apply to a POE port
 type POEPortUpdate struct {
-	PortID         int           `json:"port_id"`
-	Enabled        *bool         `json:"enabled,omitempty"`
-	Mode           *POEMode      `json:"mode,omitempty"`
-	Priority       *POEPriority  `json:"priority,omitempty"`
-	PowerLimitType *POELimitType `json:"power_limit_type,omitempty"`
-	PowerLimitW    *float64      `json:"power_limit_w,omitempty"`
-	DetectionType  *string       `json:"detection_type,omitempty"`
+	PortID         int               `json:"port_id"`
+	Enabled        *bool             `json:"enabled,omitempty"`
+	Mode           *POEMode          `json:"mode,omitempty"`
+	Priority       *POEPriority      `json:"priority,omitempty"`
+	PowerLimitType *POELimitType     `json:"power_limit_type,omitempty"`
+	PowerLimitW    *float64          `json:"power_limit_w,omitempty"`
+	DetectionType  *string           `json:"detection_type,omitempty"`
+	PowerUp        *POEPowerUpConfig `json:"power_up,omitempty"`
+}
+
+// MaxPOEPowerLimitW is the highest power limit per port, as defined by 802.3at
+const MaxPOEPowerLimitW = 30.0
+
+// POEPowerLimitStepW is the granularity of the power limit, the firmware accepts tenths of a watt only
+const POEPowerLimitStepW = 0.1
+
+// FormatPOEPowerLimit formats a power limit the way the model's POE form expects it: GS30x firmware
+// takes watts with one decimal, e.g. "15.4", GS316 firmware an integer of tenths of a watt, e.g. "154"
+func FormatPOEPowerLimit(model Model, watts float64) string {
+	tenths := int(math.Round(watts / POEPowerLimitStepW))
+	if model.IsModel316() {
+		return strconv.Itoa(tenths)
+	}
+	return fmt.Sprintf("%d.%d", tenths/10, tenths%10)
+}
+
+// isPOEPowerLimitStep reports whether the power limit is a multiple of POEPowerLimitStepW
+func isPOEPowerLimitStep(watts float64) bool {
+	tenths := watts / POEPowerLimitStepW
+	return math.Abs(tenths-math.Round(tenths)) < 1e-6
+}
+
+// isNamedOrFormValue accepts one of the named values, case-insensitively, or a firmware form value like "2"
+func isNamedOrFormValue(value string, names ...string) bool {
+	if _, err := strconv.Atoi(value); err == nil {
+		return true
+	}
+	for _, name := range names {
+		if strings.EqualFold(value, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimitFormValue returns the form value of a rate limit given by name, e.g. "3" for "1 Mbit/s"
+func rateLimitFormValue(limit string) string {
+	limit = strings.Join(strings.Fields(limit), " ")
+	for i, name := range portRateLimits {
+		if strings.EqualFold(limit, name) {
+			return strconv.Itoa(i + 1)
+		}
+	}
+	return limit
+}
+
+// Validate checks the update locally for the model, without contacting the switch.
+// All problems found are returned together as a MultiError.
+func (u POEPortUpdate) Validate(model Model) error {
+	var problems MultiError
+	if err := validatePortID(model, u.PortID, model.POEPortCount()); err != nil {
+		problems.add(err)
+	}
+	if u.Mode != nil && !isNamedOrFormValue(string(*u.Mode),
+		string(POEMode8023af), string(POEMode8023at), string(POEModeLegacy), string(POEModePre8023at)) {
+		problems.add(NewOperationError(fmt.Sprintf("invalid POE mode '%s'", *u.Mode), nil))
+	}
+	if u.Priority != nil && !isNamedOrFormValue(string(*u.Priority),
+		string(POEPriorityLow), string(POEPriorityHigh), string(POEPriorityCritical)) {
+		problems.add(NewOperationError(fmt.Sprintf("invalid POE priority '%s'", *u.Priority), nil))
+	}
+	if u.PowerLimitType != nil && !isNamedOrFormValue(string(*u.PowerLimitType),
+		string(POELimitTypeNone), string(POELimitTypeClass), string(POELimitTypeUser)) {
+		problems.add(NewOperationError(fmt.Sprintf("invalid power limit type '%s'", *u.PowerLimitType), nil))
+	}
+	if u.PowerLimitW != nil && (*u.PowerLimitW < 0 || *u.PowerLimitW > MaxPOEPowerLimitW) {
+		problems.add(NewOperationError(fmt.Sprintf("power limit %.1f W must be between 0 and %.1f W", *u.PowerLimitW, MaxPOEPowerLimitW), nil))
+	} else if u.PowerLimitW != nil && !isPOEPowerLimitStep(*u.PowerLimitW) {
+		problems.add(NewOperationError(fmt.Sprintf("power limit %g W is finer than the firmware's %.1f W steps", *u.PowerLimitW, POEPowerLimitStepW), nil))
+	}
+	if u.PowerUp != nil {
+		if err := u.PowerUp.Validate(); err != nil {
+			problems.add(err)
+		}
+	}
+	return problems.errorOrNil()
 }
 
 // PortUpdate represents changes to apply to a port
 type PortUpdate struct {
 	PortID       int        `json:"port_id"`
 	Name         *string    `json:"name,omitempty"`
+	Description  *string    `json:"description,omitempty"` // sets the name, the firmware has no separate description
 	Speed        *PortSpeed `json:"speed,omitempty"`
 	IngressLimit *string    `json:"ingress_limit,omitempty"`
 	EgressLimit  *string    `json:"egress_limit,omitempty"`
 	FlowControl  *bool      `json:"flow_control,omitempty"`
-}
\ No newline at end of file
+}
+
+// MaxPortNameLength is the longest port name the firmware accepts
+const MaxPortNameLength = 16
+
+// portRateLimits are the ingress and egress rate limits the firmware offers, in the order of their form values
+var portRateLimits = []string{"No Limit", "512 Kbit/s", "1 Mbit/s", "2 Mbit/s", "4 Mbit/s", "8 Mbit/s",
+	"16 Mbit/s", "32 Mbit/s", "64 Mbit/s", "128 Mbit/s", "256 Mbit/s", "512 Mbit/s"}
+
+// NoRateLimitKbps is the Kbps value of a port without ingress or egress rate limit
+const NoRateLimitKbps = 0
+
+// ParseRateLimitKbps converts a rate limit to Kbit/s, 1 Mbit/s being 1000 Kbit/s. It accepts the
+// firmware's names, e.g. "No Limit" or "32 Mbit/s", the short units "Kbps" and "Mbps", and form values, e.g. "5".
+func ParseRateLimitKbps(limit string) (int, error) {
+	limit = strings.Join(strings.Fields(limit), " ")
+	for i, name := range portRateLimits {
+		if strings.EqualFold(limit, name) || limit == strconv.Itoa(i+1) {
+			limit = name
+			break
+		}
+	}
+	if strings.EqualFold(limit, portRateLimits[0]) {
+		return NoRateLimitKbps, nil
+	}
+
+	value, unit, found := strings.Cut(strings.ToLower(limit), " ")
+	rate, err := strconv.Atoi(value)
+	if !found || err != nil || rate <= 0 {
+		return 0, NewParsingError(fmt.Sprintf("invalid rate limit '%s'", limit), nil)
+	}
+	switch unit {
+	case "kbit/s", "kbps":
+		return rate, nil
+	case "mbit/s", "mbps":
+		return rate * 1000, nil
+	default:
+		return 0, NewParsingError(fmt.Sprintf("invalid rate limit unit in '%s'", limit), nil)
+	}
+}
+
+// isValidRateLimit accepts a rate limit by name, e.g. "4 Mbit/s", or by its form value, e.g. "5"
+func isValidRateLimit(limit string) bool {
+	limit = strings.Join(strings.Fields(limit), " ")
+	for i, name := range portRateLimits {
+		if strings.EqualFold(limit, name) || limit == strconv.Itoa(i+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the update locally for the model, without contacting the switch.
+// All problems found are returned together as a MultiError.
+func (u PortUpdate) Validate(model Model) error {
+	var problems MultiError
+	if err := validatePortID(model, u.PortID, model.PortCount()); err != nil {
+		problems.add(err)
+	}
+	if u.Name != nil && len(*u.Name) > MaxPortNameLength {
+		problems.add(NewOperationError(fmt.Sprintf("port name '%s' is longer than %d characters", *u.Name, MaxPortNameLength), nil))
+	}
+	if u.Speed != nil {
+		if _, ok := portSpeedFormValues[normalizePortSpeed(string(*u.Speed))]; !ok {
+			problems.add(NewOperationError(fmt.Sprintf("invalid port speed '%s'", *u.Speed), nil))
+		}
+	}
+	if u.IngressLimit != nil && !isValidRateLimit(*u.IngressLimit) {
+		problems.add(NewOperationError(fmt.Sprintf("invalid ingress rate limit '%s'", *u.IngressLimit), nil))
+	}
+	if u.EgressLimit != nil && !isValidRateLimit(*u.EgressLimit) {
+		problems.add(NewOperationError(fmt.Sprintf("invalid egress rate limit '%s'", *u.EgressLimit), nil))
+	}
+	return problems.errorOrNil()
+}
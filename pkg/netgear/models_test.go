@@ -0,0 +1,257 @@
+package netgear
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestModelSeries(t *testing.T) {
+	tests := []struct {
+		model    Model
+		expected ModelSeries
+	}{
+		{ModelGS305EP, Series30x},
+		{ModelGS305EPP, Series30x},
+		{ModelGS308EP, Series30x},
+		{ModelGS308EPP, Series30x},
+		{ModelGS30xEPx, Series30x},
+		{ModelGS316EP, Series316},
+		{ModelGS316EPP, Series316},
+		{Model("GS724TP"), SeriesUnknown},
+		{Model(""), SeriesUnknown},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.model), func(t *testing.T) {
+			then.AssertThat(t, test.model.Series(), is.EqualTo(test.expected))
+			then.AssertThat(t, test.model.IsModel30x(), is.EqualTo(test.expected == Series30x))
+			then.AssertThat(t, test.model.IsModel316(), is.EqualTo(test.expected == Series316))
+		})
+	}
+}
+
+func TestParsePOEFault(t *testing.T) {
+	tests := []struct {
+		phrase   string
+		expected POEFault
+	}{
+		{"No Error", POEFaultNone},
+		{"Overload", POEFaultOverload},
+		{"Over Load", POEFaultOverload},
+		{"Short Circuit", POEFaultShortCircuit},
+		{"Over Temperature", POEFaultOverTemperature},
+		{"Power Denied", POEFaultPowerDenied},
+		{"Port Disabled", POEFaultPortDisabled},
+		{"MPS Absent", POEFaultMPSAbsent},
+		{"  power   denied ", POEFaultPowerDenied},
+		{"Power Denied (budget exceeded)", POEFaultPowerDenied},
+		{"Something Else", POEFaultUnknown},
+	}
+
+	for _, test := range tests {
+		t.Run(test.phrase, func(t *testing.T) {
+			then.AssertThat(t, ParsePOEFault(test.phrase), is.EqualTo(test.expected))
+		})
+	}
+}
+
+func TestParsePOEFaultReason(t *testing.T) {
+	tests := []struct {
+		phrase   string
+		expected string
+	}{
+		{"No Error", ""},
+		{"", ""},
+		{"Power Denied (budget exceeded)", "the switch's POE power budget is exceeded"},
+		{"Power Denied", "the switch denied power to the port"},
+		{"Power Denied (low priority)", "the switch denied power to the port (low priority)"},
+		{"Overload", "the powered device draws more than the port's power limit"},
+		{"Something  Else", "Something Else"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.phrase, func(t *testing.T) {
+			then.AssertThat(t, ParsePOEFaultReason(test.phrase), is.EqualTo(test.expected))
+		})
+	}
+}
+
+func TestPOEPowerUpConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config POEPowerUpConfig
+		valid  bool
+	}{
+		{"immediate", POEPowerUpConfig{Mode: POEPowerUpModeImmediate}, true},
+		{"delayed", POEPowerUpConfig{Mode: POEPowerUpModeDelayed, DelaySeconds: 30}, true},
+		{"max delay", POEPowerUpConfig{Mode: POEPowerUpModeDelayed, DelaySeconds: MaxPOEPowerUpDelaySeconds}, true},
+		{"delay too long", POEPowerUpConfig{Mode: POEPowerUpModeDelayed, DelaySeconds: MaxPOEPowerUpDelaySeconds + 1}, false},
+		{"delayed without delay", POEPowerUpConfig{Mode: POEPowerUpModeDelayed}, false},
+		{"immediate with delay", POEPowerUpConfig{Mode: POEPowerUpModeImmediate, DelaySeconds: 5}, false},
+		{"unknown mode", POEPowerUpConfig{Mode: "later"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			then.AssertThat(t, test.config.Validate() == nil, is.EqualTo(test.valid))
+		})
+	}
+}
+
+func TestNormalizePortSpeed(t *testing.T) {
+	tests := []struct {
+		speed    string
+		expected PortSpeed
+	}{
+		{"Auto", PortSpeedAuto},
+		{"auto", PortSpeedAuto},
+		{"Disable", PortSpeedDisable},
+		{"Disabled", PortSpeedDisable},
+		{"10M Half", PortSpeed10MHalf},
+		{"10M Full", PortSpeed10MFull},
+		{"100M Half", PortSpeed100MHalf},
+		{"100M Full", PortSpeed100MFull},
+		{"100M full", PortSpeed100MFull},
+		{"100 Mbps Full", PortSpeed100MFull},
+		{"100M/Full", PortSpeed100MFull},
+		{" 10 Mbps-Half ", PortSpeed10MHalf},
+		{"6", PortSpeed100MFull},
+		{"1000M Full", PortSpeed("1000M Full")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.speed, func(t *testing.T) {
+			then.AssertThat(t, normalizePortSpeed(test.speed), is.EqualTo(test.expected))
+		})
+	}
+}
+
+func TestPortSpeedFormValue(t *testing.T) {
+	tests := []struct {
+		speed    PortSpeed
+		expected string
+	}{
+		{PortSpeedAuto, "1"},
+		{PortSpeedDisable, "2"},
+		{PortSpeed10MHalf, "3"},
+		{PortSpeed10MFull, "4"},
+		{PortSpeed100MHalf, "5"},
+		{PortSpeed100MFull, "6"},
+		{PortSpeed("100M Full"), "6"},
+		{PortSpeed("1000M Full"), "1000M Full"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.speed), func(t *testing.T) {
+			then.AssertThat(t, test.speed.FormValue(), is.EqualTo(test.expected))
+		})
+	}
+}
+
+func TestPortUpdateValidateReportsAllProblems(t *testing.T) {
+	name := "a port name longer than sixteen characters"
+	speed := PortSpeed("10G full")
+	ingress := "3 Mbit/s"
+	egress := "4 mbit/s"
+	update := PortUpdate{PortID: 6, Name: &name, Speed: &speed, IngressLimit: &ingress, EgressLimit: &egress}
+
+	err := update.Validate(ModelGS305EP)
+
+	var multiErr *MultiError
+	then.AssertThat(t, errors.As(err, &multiErr), is.True())
+	then.AssertThat(t, multiErr.Errors, has.Length[error](4))
+	then.AssertThat(t, multiErr.Errors[0].Error(), is.EqualTo("operation error: port 6 out of range (GS305EP has 5 ports)"))
+	then.AssertThat(t, strings.Contains(multiErr.Errors[1].Error(), "longer than 16 characters"), is.True())
+	then.AssertThat(t, strings.Contains(multiErr.Errors[2].Error(), "'10G full'"), is.True())
+	then.AssertThat(t, strings.Contains(multiErr.Errors[3].Error(), "'3 Mbit/s'"), is.True())
+
+	then.AssertThat(t, PortUpdate{PortID: 6, EgressLimit: &egress}.Validate(ModelGS316EPP), is.Nil())
+}
+
+func TestPOEPortUpdateValidateReportsAllProblems(t *testing.T) {
+	mode := POEMode("802.3bt")
+	priority := POEPriority("urgent")
+	limitW := 60.0
+	update := POEPortUpdate{PortID: 5, Mode: &mode, Priority: &priority, PowerLimitW: &limitW,
+		PowerUp: &POEPowerUpConfig{Mode: POEPowerUpModeDelayed}}
+
+	err := update.Validate(ModelGS305EP)
+
+	var multiErr *MultiError
+	then.AssertThat(t, errors.As(err, &multiErr), is.True())
+	then.AssertThat(t, multiErr.Errors, has.Length[error](5))
+	then.AssertThat(t, multiErr.Errors[0].Error(), is.EqualTo("operation error: port 5 out of range (GS305EP has 4 ports)"))
+
+	priority = POEPriority("2")
+	limitW = 15.4
+	then.AssertThat(t, POEPortUpdate{PortID: 15, Priority: &priority, PowerLimitW: &limitW}.Validate(ModelGS316EP), is.Nil())
+}
+
+func TestPOEPortStatusPowerConsistency(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     POEPortStatus
+		consistent bool
+	}{
+		{name: "matching reading", status: POEPortStatus{VoltageV: 53, CurrentMA: 82, PowerW: 4.4}, consistent: true},
+		{name: "idle port", status: POEPortStatus{VoltageV: 0, CurrentMA: 0, PowerW: 0}, consistent: true},
+		{name: "rounding on small reading", status: POEPortStatus{VoltageV: 54, CurrentMA: 2, PowerW: 0.5}, consistent: true},
+		{name: "power parsed from wrong field", status: POEPortStatus{VoltageV: 53, CurrentMA: 82, PowerW: 53}, consistent: false},
+		{name: "missing current", status: POEPortStatus{VoltageV: 53, CurrentMA: 0, PowerW: 7.2}, consistent: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			then.AssertThat(t, test.status.IsPowerConsistent(DefaultPowerTolerance), is.EqualTo(test.consistent))
+		})
+	}
+	then.AssertThat(t, POEPortStatus{VoltageV: 53, CurrentMA: 82}.PowerFromVI(), is.EqualTo(4.346))
+}
+
+func TestParseRateLimitKbps(t *testing.T) {
+	tests := []struct {
+		limit    string
+		expected int
+	}{
+		{limit: "No Limit", expected: NoRateLimitKbps},
+		{limit: "512 Kbps", expected: 512},
+		{limit: "512 Kbit/s", expected: 512},
+		{limit: "32 Mbps", expected: 32000},
+		{limit: "32  Mbit/s", expected: 32000},
+		{limit: "1", expected: NoRateLimitKbps},
+		{limit: "3", expected: 1000},
+	}
+	for _, test := range tests {
+		t.Run(test.limit, func(t *testing.T) {
+			kbps, err := ParseRateLimitKbps(test.limit)
+
+			then.AssertThat(t, err, is.Nil())
+			then.AssertThat(t, kbps, is.EqualTo(test.expected))
+		})
+	}
+
+	for _, invalid := range []string{"", "fast", "32 Gbps", "-1 Mbps"} {
+		_, err := ParseRateLimitKbps(invalid)
+		then.AssertThat(t, err, is.Not(is.Nil()))
+	}
+}
+
+func TestFormatPOEPowerLimit(t *testing.T) {
+	then.AssertThat(t, FormatPOEPowerLimit(ModelGS305EP, 15.4), is.EqualTo("15.4"))
+	then.AssertThat(t, FormatPOEPowerLimit(ModelGS305EP, 30), is.EqualTo("30.0"))
+	then.AssertThat(t, FormatPOEPowerLimit(ModelGS316EPP, 15.4), is.EqualTo("154"))
+	then.AssertThat(t, FormatPOEPowerLimit(ModelGS316EPP, 0.5), is.EqualTo("5"))
+}
+
+func TestValidatePortID(t *testing.T) {
+	then.AssertThat(t, ModelGS305EP.ValidatePortID(5), is.Nil())
+	then.AssertThat(t, ModelGS305EP.ValidatePortID(9).Error(), is.EqualTo("operation error: port 9 out of range (GS305EP has 5 ports)"))
+	then.AssertThat(t, ModelGS305EP.ValidatePOEPortID(5), is.Not(is.Nil()))
+	then.AssertThat(t, ModelGS316EPP.ValidatePortID(16), is.Nil())
+	then.AssertThat(t, ModelGS316EPP.ValidatePortID(17).Error(), is.EqualTo("operation error: port 17 out of range (GS316EPP has 16 ports)"))
+	then.AssertThat(t, ModelGS316EPP.ValidatePOEPortID(15), is.Nil())
+}
@@ -1,6 +1,7 @@
 package netgear
 
 import (
+	"fmt"
 	"os"
 	"strings"
 )
@@ -51,9 +52,9 @@ func (e *EnvironmentPasswordManager) GetSwitchConfig(address string) (*SwitchCon
 	// Priority 1: Host-specific environment variable (highest priority)
 	normalizedHost := e.normalizeHost(address)
 	envVar := "NETGEAR_PASSWORD_" + normalizedHost
-	if password := os.Getenv(envVar); password != "" {
+	if password, source := e.lookupSecret(envVar); password != "" {
 		if e.verbose {
-			println("Found host-specific password for", address, "via", envVar)
+			println("Found host-specific password for", address, "via", source)
 		}
 		
 		// Check for model specification
@@ -84,52 +85,77 @@ func (e *EnvironmentPasswordManager) GetSwitchConfig(address string) (*SwitchCon
 
 // parseMultiSwitchConfig parses NETGEAR_SWITCHES environment variable for a specific host
 func (e *EnvironmentPasswordManager) parseMultiSwitchConfig(targetHost string) (*SwitchConfig, bool) {
-	switchesVar := os.Getenv("NETGEAR_SWITCHES")
-	if switchesVar == "" {
-		return nil, false
+	configs, _ := e.SwitchesConfig()
+	for _, config := range configs {
+		if config.Host == targetHost {
+			return &config, true
+		}
 	}
+	return nil, false
+}
+
+// SwitchesConfig parses the NETGEAR_SWITCHES environment variable, or the file of NETGEAR_SWITCHES_FILE,
+// see ParseSwitchesConfig
+func (e *EnvironmentPasswordManager) SwitchesConfig() ([]SwitchConfig, []error) {
+	switchesVar, _ := e.lookupSecret("NETGEAR_SWITCHES")
+	return ParseSwitchesConfig(switchesVar)
+}
 
-	// Parse format: host1=password1[,model1];host2=password2[,model2];...
-	entries := strings.Split(switchesVar, ";")
-	for _, entry := range entries {
+// ParseSwitchesConfig parses the format of NETGEAR_SWITCHES: host1=password1[,model1];host2=password2[,model2];...
+// Secret files may also put one entry per line. Malformed entries are skipped, and returned as errors
+// naming their position, starting with 1, but never the password.
+func ParseSwitchesConfig(value string) ([]SwitchConfig, []error) {
+	var configs []SwitchConfig
+	var problems []error
+	entries := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ';' || r == '\n'
+	})
+	for i, entry := range entries {
 		entry = strings.TrimSpace(entry)
 		if entry == "" {
 			continue
 		}
 
 		// Split host=password[,model]
-		parts := strings.SplitN(entry, "=", 2)
-		if len(parts) != 2 {
+		host, passwordAndModel, found := strings.Cut(entry, "=")
+		if !found {
+			problems = append(problems, NewParsingError(fmt.Sprintf("NETGEAR_SWITCHES entry %d has no '=' between host and password", i+1), nil))
 			continue
 		}
-
-		host := strings.TrimSpace(parts[0])
-		passwordAndModel := strings.TrimSpace(parts[1])
-
-		// Check if this entry matches our target host
-		if host != targetHost {
+		config := SwitchConfig{Host: strings.TrimSpace(host)}
+		if config.Host == "" {
+			problems = append(problems, NewParsingError(fmt.Sprintf("NETGEAR_SWITCHES entry %d has an empty host", i+1), nil))
 			continue
 		}
 
 		// Parse password[,model]
-		config := &SwitchConfig{
-			Host: host,
+		password, model, _ := strings.Cut(passwordAndModel, ",")
+		config.Password = strings.TrimSpace(password)
+		config.Model = strings.TrimSpace(model)
+		if config.Password == "" {
+			problems = append(problems, NewParsingError(fmt.Sprintf("NETGEAR_SWITCHES entry %d for %s has an empty password", i+1, config.Host), nil))
+			continue
 		}
+		configs = append(configs, config)
+	}
+	return configs, problems
+}
 
-		if strings.Contains(passwordAndModel, ",") {
-			// Has model specification
-			modelParts := strings.SplitN(passwordAndModel, ",", 2)
-			config.Password = strings.TrimSpace(modelParts[0])
-			config.Model = strings.TrimSpace(modelParts[1])
-		} else {
-			// Password only
-			config.Password = passwordAndModel
+// lookupSecret resolves an environment variable, preferring the file named by
+// its _FILE variant (Docker secrets convention) over the inline value. The file
+// is read on every call, so rotated secrets are picked up without a restart.
+func (e *EnvironmentPasswordManager) lookupSecret(envVar string) (value string, source string) {
+	fileVar := envVar + "_FILE"
+	if path := os.Getenv(fileVar); path != "" {
+		content, err := os.ReadFile(path)
+		if err == nil {
+			return strings.TrimRight(string(content), "\r\n"), fileVar
+		}
+		if e.verbose {
+			println("Could not read", fileVar, "file", path+":", err.Error())
 		}
-
-		return config, true
 	}
-
-	return nil, false
+	return os.Getenv(envVar), envVar
 }
 
 // normalizeHost converts host to environment variable format
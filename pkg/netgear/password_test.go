@@ -0,0 +1,85 @@
+package netgear
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func writeSecretFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "secret")
+	err := os.WriteFile(path, []byte(content), 0600)
+	then.AssertThat(t, err, is.Nil())
+	return path
+}
+
+func TestPasswordFileTakesPrecedenceOverInlineVariable(t *testing.T) {
+	t.Setenv("NETGEAR_PASSWORD_192_168_0_1", "inline")
+	t.Setenv("NETGEAR_PASSWORD_192_168_0_1_FILE", writeSecretFile(t, "from-file\n"))
+
+	password, found := NewEnvironmentPasswordManager().GetPassword("192.168.0.1")
+
+	then.AssertThat(t, found, is.True())
+	then.AssertThat(t, password, is.EqualTo("from-file"))
+}
+
+func TestPasswordFileIsReadOnDemand(t *testing.T) {
+	path := writeSecretFile(t, "first")
+	t.Setenv("NETGEAR_PASSWORD_SWITCH1_FILE", path)
+	pm := NewEnvironmentPasswordManager()
+
+	password, _ := pm.GetPassword("switch1")
+	then.AssertThat(t, password, is.EqualTo("first"))
+
+	then.AssertThat(t, os.WriteFile(path, []byte("rotated\n"), 0600), is.Nil())
+	password, _ = pm.GetPassword("switch1")
+	then.AssertThat(t, password, is.EqualTo("rotated"))
+}
+
+func TestUnreadablePasswordFileFallsBackToInlineVariable(t *testing.T) {
+	t.Setenv("NETGEAR_PASSWORD_SWITCH1", "inline")
+	t.Setenv("NETGEAR_PASSWORD_SWITCH1_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	password, found := NewEnvironmentPasswordManager().GetPassword("switch1")
+
+	then.AssertThat(t, found, is.True())
+	then.AssertThat(t, password, is.EqualTo("inline"))
+}
+
+func TestSwitchesFileResolvesPasswordAndModel(t *testing.T) {
+	t.Setenv("NETGEAR_SWITCHES", "switch2=inline")
+	t.Setenv("NETGEAR_SWITCHES_FILE", writeSecretFile(t, "switch1=one;\nswitch2=two,GS316EP\n"))
+
+	config, found := NewEnvironmentPasswordManager().GetSwitchConfig("switch2")
+
+	then.AssertThat(t, found, is.True())
+	then.AssertThat(t, config.Password, is.EqualTo("two"))
+	then.AssertThat(t, config.Model, is.EqualTo("GS316EP"))
+}
+
+func TestParseSwitchesConfig(t *testing.T) {
+	configs, problems := ParseSwitchesConfig("switch1 secret; =nohost ;switch3=three,GS316EP;switch4=")
+
+	then.AssertThat(t, configs, is.EqualTo([]SwitchConfig{{Host: "switch3", Password: "three", Model: "GS316EP"}}))
+	then.AssertThat(t, problems, has.Length[error](3))
+	then.AssertThat(t, problems[0].Error(), is.StringContaining("entry 1 has no '=' between host and password"))
+	then.AssertThat(t, problems[1].Error(), is.StringContaining("entry 2 has an empty host"))
+	then.AssertThat(t, problems[2].Error(), is.StringContaining("entry 4 for switch4 has an empty password"))
+	for _, problem := range problems {
+		then.AssertThat(t, strings.Contains(problem.Error(), "secret"), is.False())
+	}
+}
+
+func TestMalformedSwitchesEntryDoesNotHideOthers(t *testing.T) {
+	t.Setenv("NETGEAR_SWITCHES", "switch1;switch2=two")
+
+	config, found := NewEnvironmentPasswordManager().GetSwitchConfig("switch2")
+
+	then.AssertThat(t, found, is.True())
+	then.AssertThat(t, config.Password, is.EqualTo("two"))
+}
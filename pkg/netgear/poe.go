@@ -2,9 +2,13 @@ package netgear
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math/rand/v2"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"ntgrrc/pkg/netgear/internal"
 )
@@ -31,26 +35,44 @@ func (m *POEManager) GetStatus(ctx context.Context) ([]POEPortStatus, error) {
 
 	// Determine the appropriate endpoint based on model
 	var endpoint string
-	if m.client.model.IsModel30x() {
+	var query url.Values
+	switch m.client.GetModel().Series() {
+	case Series30x:
 		endpoint = "/getPoePortStatus.cgi"
-	} else if m.client.model.IsModel316() {
+	case Series316:
 		endpoint = "/iss/specific/poePortStatus.html"
-	} else {
+		query = url.Values{"GetData": {"TRUE"}}
+	default:
 		return nil, NewOperationError("POE status not supported for this model", nil)
 	}
 
 	// Make authenticated request
-	response, err := m.client.makeAuthenticatedRequest(ctx, "GET", endpoint, nil)
+	response, err := m.client.makeAuthenticatedRequest(ctx, "GET", endpoint, query)
 	if err != nil {
 		return nil, NewOperationError("failed to get POE status", err)
 	}
 
+	// Newer GS316 firmware answers the data request with JSON instead of HTML
+	if internal.IsJSONContent(response) {
+		statuses, err := parsePOEStatusJSON(response)
+		if err != nil {
+			return nil, err
+		}
+		return statuses, checkUniquePortIDs(statuses, func(s POEPortStatus) int { return s.PortID })
+	}
+
 	// Parse the response
 	rawData, err := m.parser.ParsePOEStatus(response)
 	if err != nil {
 		return nil, NewParsingError("failed to parse POE status", err)
 	}
 
+	// A real page without any recognizable ports most likely means the firmware changed its markup
+	if len(rawData) == 0 && internal.IsUnrecognizedDocument(response) {
+		return nil, NewParsingError("no POE ports found in status page, the firmware may use an unknown layout; "+
+			"please file an issue including the output of 'ntgrrc debug-report'", ErrInvalidResponse)
+	}
+
 	// Convert to strongly typed structures
 	var statuses []POEPortStatus
 	for _, raw := range rawData {
@@ -80,42 +102,84 @@ func (m *POEManager) GetStatus(ctx context.Context) ([]POEPortStatus, error) {
 		if temp, ok := raw["temperature_c"].(float64); ok {
 			status.TemperatureC = temp
 		}
+		if tempStatus, ok := raw["temperature_status"].(string); ok {
+			status.TemperatureStatus = tempStatus
+		}
 		if errorStatus, ok := raw["error_status"].(string); ok {
 			status.ErrorStatus = errorStatus
+			status.Fault = ParsePOEFault(errorStatus)
+			status.FaultReason = ParsePOEFaultReason(errorStatus)
+		}
+		for _, field := range []string{"voltage_v", "current_ma", "power_w", "temperature_c"} {
+			if _, ok := raw[field].(float64); !ok {
+				status.UnparsedReadings = append(status.UnparsedReadings, field)
+			}
 		}
 
 		statuses = append(statuses, status)
 	}
 
+	if err := checkUniquePortIDs(statuses, func(s POEPortStatus) int { return s.PortID }); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// parsePOEStatusJSON unmarshals a JSON POE status response, a list of port statuses
+func parsePOEStatusJSON(response string) ([]POEPortStatus, error) {
+	var statuses []POEPortStatus
+	if err := json.Unmarshal([]byte(response), &statuses); err != nil {
+		return nil, NewParsingError("failed to parse POE status JSON", err)
+	}
+
+	for i := range statuses {
+		if statuses[i].Fault == "" && statuses[i].ErrorStatus != "" {
+			statuses[i].Fault = ParsePOEFault(statuses[i].ErrorStatus)
+		}
+		if statuses[i].FaultReason == "" && statuses[i].ErrorStatus != "" {
+			statuses[i].FaultReason = ParsePOEFaultReason(statuses[i].ErrorStatus)
+		}
+		if statuses[i].TemperatureStatus != "" {
+			statuses[i].TemperatureStatus = internal.NormalizeTemperatureStatus(statuses[i].TemperatureStatus)
+		}
+	}
+
 	return statuses, nil
 }
 
 // GetSettings retrieves POE settings for all ports
 func (m *POEManager) GetSettings(ctx context.Context) ([]POEPortSettings, error) {
+	settings, _, err := m.readSettings(ctx)
+	return settings, err
+}
+
+// readSettings retrieves the POE settings and the config page itself, e.g. for its form token
+func (m *POEManager) readSettings(ctx context.Context) ([]POEPortSettings, string, error) {
 	if !m.client.IsAuthenticated() {
-		return nil, ErrNotAuthenticated
+		return nil, "", ErrNotAuthenticated
 	}
 
 	// Determine the appropriate endpoint based on model
 	var endpoint string
-	if m.client.model.IsModel30x() {
+	switch m.client.GetModel().Series() {
+	case Series30x:
 		endpoint = "/PoEPortConfig.cgi"
-	} else if m.client.model.IsModel316() {
+	case Series316:
 		endpoint = "/iss/specific/poePortConf.html"
-	} else {
-		return nil, NewOperationError("POE settings not supported for this model", nil)
+	default:
+		return nil, "", NewOperationError("POE settings not supported for this model", nil)
 	}
 
 	// Make authenticated request
 	response, err := m.client.makeAuthenticatedRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
-		return nil, NewOperationError("failed to get POE settings", err)
+		return nil, "", NewOperationError("failed to get POE settings", err)
 	}
 
 	// Parse the response
 	rawData, err := m.parser.ParsePOESettings(response)
 	if err != nil {
-		return nil, NewParsingError("failed to parse POE settings", err)
+		return nil, "", NewParsingError("failed to parse POE settings", err)
 	}
 
 	// Convert to strongly typed structures
@@ -150,11 +214,20 @@ func (m *POEManager) GetSettings(ctx context.Context) ([]POEPortSettings, error)
 		if longerDetection, ok := raw["longer_detection_time"].(bool); ok {
 			setting.LongerDetectionTime = longerDetection
 		}
+		if scheduleEnabled, ok := raw["schedule_enabled"].(bool); ok {
+			setting.ScheduleEnabled = scheduleEnabled
+		}
+		if scheduleName, ok := raw["schedule_name"].(string); ok {
+			setting.ScheduleName = scheduleName
+		}
 
 		settings = append(settings, setting)
 	}
 
-	return settings, nil
+	if err := checkUniquePortIDs(settings, func(s POEPortSettings) int { return s.PortID }); err != nil {
+		return nil, "", err
+	}
+	return settings, response, nil
 }
 
 // UpdatePort updates settings for specific ports
@@ -167,22 +240,72 @@ func (m *POEManager) UpdatePort(ctx context.Context, updates ...POEPortUpdate) e
 		return NewOperationError("no updates provided", nil)
 	}
 
+	// Fail fast on invalid updates, before any of them is sent
+	for _, update := range updates {
+		if err := update.Validate(m.client.GetModel()); err != nil {
+			return err
+		}
+	}
+
 	// Determine the appropriate endpoint based on model
 	var endpoint string
-	if m.client.model.IsModel30x() {
+	switch m.client.GetModel().Series() {
+	case Series30x:
 		endpoint = "/PoEPortConfig.cgi"
-	} else if m.client.model.IsModel316() {
+	case Series316:
 		endpoint = "/iss/specific/poePortConf.html"
-	} else {
+	default:
 		return NewOperationError("POE updates not supported for this model", nil)
 	}
 
+	// GS30x firmware resets every field missing from the form, so merge the current settings in
+	var current map[int]POEPortSettings
+	var powerUps map[int]POEPowerUpConfig
+	var page string
+	if m.client.GetModel().IsModel30x() {
+		settings, settingsPage, err := m.readSettings(ctx)
+		if err != nil {
+			return NewOperationError("failed to read current POE settings", err)
+		}
+		page = settingsPage
+		current = make(map[int]POEPortSettings, len(settings))
+		for _, setting := range settings {
+			current[setting.PortID] = setting
+		}
+		// the power-up config is on the same page, firmware without it has none
+		if rawData, err := m.parser.ParsePOEPowerUpConfig(page); err == nil {
+			powerUps = make(map[int]POEPowerUpConfig, len(rawData))
+			for _, config := range powerUpConfigsOf(rawData) {
+				powerUps[config.PortID] = config
+			}
+		}
+	}
+
+	tokenName, tokenValue := m.client.formToken(ctx, endpoint, page)
+
 	// Prepare form data for each update
-	for _, update := range updates {
+	for i, update := range updates {
 		data := url.Values{}
 		
 		// Add port identification
 		data.Set("port", strconv.Itoa(update.PortID))
+		if tokenName != "" {
+			data.Set(tokenName, tokenValue)
+		}
+		
+		if setting, ok := current[update.PortID]; ok {
+			var powerUp *POEPowerUpConfig
+			if config, ok := powerUps[update.PortID]; ok {
+				powerUp = &config
+			}
+			update = mergePOEUpdate(update, setting, powerUp)
+			data.Set("port_name", setting.PortName)
+			if setting.LongerDetectionTime {
+				data.Set("longer_detection_time", "on")
+			} else {
+				data.Set("longer_detection_time", "off")
+			}
+		}
 		
 		// Add updates based on what's provided
 		if update.Enabled != nil {
@@ -206,28 +329,68 @@ func (m *POEManager) UpdatePort(ctx context.Context, updates ...POEPortUpdate) e
 		}
 		
 		if update.PowerLimitW != nil {
-			data.Set("power_limit_w", fmt.Sprintf("%.2f", *update.PowerLimitW))
+			data.Set("power_limit_w", FormatPOEPowerLimit(m.client.GetModel(), *update.PowerLimitW))
 		}
 		
 		if update.DetectionType != nil {
 			data.Set("detection_type", *update.DetectionType)
 		}
 
-		// Make the update request
-		response, err := m.client.makeAuthenticatedRequest(ctx, "POST", endpoint, data)
+		if update.PowerUp != nil {
+			data.Set("power_up_mode", string(update.PowerUp.Mode))
+			data.Set("power_up_delay", strconv.Itoa(update.PowerUp.DelaySeconds))
+		}
+
+		// Make the update request, some GS316 firmware only accepts JSON
+		var response string
+		var err error
+		if m.client.GetModel().IsModel316() {
+			response, err = m.client.makeGS316WriteRequest(ctx, endpoint, data)
+		} else {
+			response, err = m.client.makeWriteRequest(ctx, endpoint, data)
+		}
 		if err != nil {
-			return NewOperationError(fmt.Sprintf("failed to update port %d", update.PortID), err)
+			return newWriteError(fmt.Sprintf("failed to update port %d", update.PortID), err)
 		}
 
 		// Check for errors in response
 		if errorMsg := internal.ExtractErrorMessage(response); errorMsg != "" {
 			return NewOperationError(fmt.Sprintf("update failed for port %d: %s", update.PortID, errorMsg), nil)
 		}
+
+		m.client.reportProgress(i+1, len(updates), fmt.Sprintf("port %d", update.PortID))
 	}
 
 	return nil
 }
 
+// mergePOEUpdate fills all fields not set in the update with the port's current settings,
+// and the power-up config with the current one, if the firmware has it
+func mergePOEUpdate(update POEPortUpdate, current POEPortSettings, powerUp *POEPowerUpConfig) POEPortUpdate {
+	if update.Enabled == nil {
+		update.Enabled = &current.Enabled
+	}
+	if update.Mode == nil {
+		update.Mode = &current.Mode
+	}
+	if update.Priority == nil {
+		update.Priority = &current.Priority
+	}
+	if update.PowerLimitType == nil {
+		update.PowerLimitType = &current.PowerLimitType
+	}
+	if update.PowerLimitW == nil {
+		update.PowerLimitW = &current.PowerLimitW
+	}
+	if update.DetectionType == nil {
+		update.DetectionType = &current.DetectionType
+	}
+	if update.PowerUp == nil {
+		update.PowerUp = powerUp
+	}
+	return update
+}
+
 // CyclePower performs a power cycle on specified ports
 func (m *POEManager) CyclePower(ctx context.Context, portIDs ...int) error {
 	if !m.client.IsAuthenticated() {
@@ -237,59 +400,269 @@ func (m *POEManager) CyclePower(ctx context.Context, portIDs ...int) error {
 	if len(portIDs) == 0 {
 		return NewOperationError("no ports specified for power cycle", nil)
 	}
+	for _, portID := range portIDs {
+		if err := m.client.GetModel().ValidatePOEPortID(portID); err != nil {
+			return err
+		}
+	}
 
-	// Determine the appropriate endpoint based on model
-	var endpoint string
-	if m.client.model.IsModel30x() {
-		endpoint = "/PoEPortConfig.cgi"
-	} else if m.client.model.IsModel316() {
-		endpoint = "/iss/specific/poePortConf.html"
-	} else {
-		return NewOperationError("POE power cycle not supported for this model", nil)
+	endpoint, err := m.cycleEndpoint()
+	if err != nil {
+		return err
 	}
 
 	// Cycle power for each port
+	for i, portID := range portIDs {
+		if err := m.cyclePort(ctx, endpoint, portID); err != nil {
+			return err
+		}
+		m.client.reportProgress(i+1, len(portIDs), fmt.Sprintf("port %d", portID))
+	}
+
+	return nil
+}
+
+// staggerJitterFraction is the maximum random share added to the stagger delay,
+// so switches cycled by several scripts at once don't stay in lockstep
+const staggerJitterFraction = 0.1
+
+// CyclePowerStaggered power cycles the ports one after the other, waiting at least the stagger delay
+// (plus a small random jitter) between two ports, so the powered devices don't all draw their inrush
+// current at the same time. A cancelled context stops the sequence before the next port.
+func (m *POEManager) CyclePowerStaggered(ctx context.Context, stagger time.Duration, portIDs ...int) error {
+	if !m.client.IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+
+	if len(portIDs) == 0 {
+		return NewOperationError("no ports specified for power cycle", nil)
+	}
 	for _, portID := range portIDs {
-		data := url.Values{}
-		data.Set("port", strconv.Itoa(portID))
-		data.Set("action", "cycle")
-		
-		response, err := m.client.makeAuthenticatedRequest(ctx, "POST", endpoint, data)
-		if err != nil {
-			return NewOperationError(fmt.Sprintf("failed to cycle power for port %d", portID), err)
+		if err := m.client.GetModel().ValidatePOEPortID(portID); err != nil {
+			return err
 		}
+	}
 
-		// Check for errors in response
-		if errorMsg := internal.ExtractErrorMessage(response); errorMsg != "" {
-			return NewOperationError(fmt.Sprintf("power cycle failed for port %d: %s", portID, errorMsg), nil)
+	endpoint, err := m.cycleEndpoint()
+	if err != nil {
+		return err
+	}
+
+	for i, portID := range portIDs {
+		if i > 0 {
+			if err := ctx.Err(); err != nil {
+				return NewOperationError(fmt.Sprintf("power cycle stopped before port %d", portID), err)
+			}
+			if err := m.client.sleep(ctx, withJitter(stagger)); err != nil {
+				return NewOperationError(fmt.Sprintf("power cycle stopped before port %d", portID), err)
+			}
 		}
+		if err := m.cyclePort(ctx, endpoint, portID); err != nil {
+			return err
+		}
+		m.client.reportProgress(i+1, len(portIDs), fmt.Sprintf("port %d", portID))
+	}
+
+	return nil
+}
 
-		if m.client.verbose {
-			fmt.Printf("Successfully cycled power for port %d\n", portID)
+// cycleAllStagger is the delay between two ports of CycleAll, so the devices don't all boot at once
+const cycleAllStagger = 2 * time.Second
+
+// CycleAll power cycles every port delivering power, e.g. to reboot all cameras, one after the other
+// like CyclePowerStaggered. Disabled ports and ports without a powered device are skipped.
+func (m *POEManager) CycleAll(ctx context.Context) error {
+	statuses, err := m.GetStatus(ctx)
+	if err != nil {
+		return NewOperationError("failed to read the POE status", err)
+	}
+
+	var portIDs []int
+	for _, status := range statuses {
+		if status.IsDelivering() {
+			portIDs = append(portIDs, status.PortID)
 		}
 	}
+	if len(portIDs) == 0 {
+		return nil
+	}
+	return m.CyclePowerStaggered(ctx, cycleAllStagger, portIDs...)
+}
+
+// withJitter adds a random delay of up to staggerJitterFraction to d
+func withJitter(d time.Duration) time.Duration {
+	maxJitter := int64(float64(d) * staggerJitterFraction)
+	if maxJitter <= 0 {
+		return d
+	}
+	return d + time.Duration(rand.Int64N(maxJitter+1))
+}
+
+func (m *POEManager) cycleEndpoint() (string, error) {
+	switch m.client.GetModel().Series() {
+	case Series30x:
+		return "/PoEPortConfig.cgi", nil
+	case Series316:
+		return "/iss/specific/poePortConf.html", nil
+	}
+	return "", NewOperationError("POE power cycle not supported for this model", nil)
+}
+
+func (m *POEManager) cyclePort(ctx context.Context, endpoint string, portID int) error {
+	data := url.Values{}
+	data.Set("port", strconv.Itoa(portID))
+	data.Set("action", "cycle")
+
+	response, err := m.client.makeActionRequest(ctx, endpoint, data)
+	if err != nil {
+		return newWriteError(fmt.Sprintf("failed to cycle power for port %d", portID), err)
+	}
+
+	// Check for errors in response
+	if errorMsg := internal.ExtractErrorMessage(response); errorMsg != "" {
+		return NewOperationError(fmt.Sprintf("power cycle failed for port %d: %s", portID, errorMsg), nil)
+	}
 
+	if m.client.verbose {
+		fmt.Printf("Successfully cycled power for port %d\n", portID)
+	}
 	return nil
 }
 
-// EnablePort enables POE on the specified port
+// The firmware needs a moment to redetect the powered device after a fault was reset
+const (
+	faultClearAttempts     = 5
+	faultClearPollInterval = 2 * time.Second
+)
+
+// ClearFault resets a port which latched off after a POE fault, e.g. an overload, and waits
+// until the status no longer reports the fault. The reset is the same as a power cycle.
+func (m *POEManager) ClearFault(ctx context.Context, portID int) error {
+	if err := m.CyclePower(ctx, portID); err != nil {
+		return err
+	}
+
+	// WaitForStatus polls until the context is done, so the predicate also gives up after the last attempt
+	attempts := 0
+	status, err := m.WaitForStatus(ctx, portID, func(status POEPortStatus) bool {
+		attempts++
+		return !hasPOEFault(status) || attempts == faultClearAttempts
+	}, faultClearPollInterval)
+	if err != nil {
+		return err
+	}
+	if !hasPOEFault(*status) {
+		return nil
+	}
+
+	return NewOperationError(fmt.Sprintf("fault on port %d did not clear: %s", portID, status.ErrorStatus), nil)
+}
+
+func hasPOEFault(status POEPortStatus) bool {
+	return status.Fault != "" && status.Fault != POEFaultNone
+}
+
+// EnablePort enables POE on the specified port.
+// With WithBudgetGuard, it fails with ErrInsufficientBudget if the port could exceed the remaining power budget.
 func (m *POEManager) EnablePort(ctx context.Context, portID int) error {
-	enabled := true
-	return m.UpdatePort(ctx, POEPortUpdate{
-		PortID:  portID,
-		Enabled: &enabled,
-	})
+	if m.client.budgetGuard {
+		if err := m.checkBudgetForPort(ctx, portID); err != nil {
+			return err
+		}
+	}
+	return m.setPortEnabled(ctx, portID, true)
 }
 
 // DisablePort disables POE on the specified port
 func (m *POEManager) DisablePort(ctx context.Context, portID int) error {
-	enabled := false
+	return m.setPortEnabled(ctx, portID, false)
+}
+
+// setPortEnabled enables or disables POE on the port. With WithCheckBeforeWrite, the current
+// state is read first and nothing is written if the port already is enabled or disabled.
+func (m *POEManager) setPortEnabled(ctx context.Context, portID int, enabled bool) error {
+	if err := m.client.GetModel().ValidatePOEPortID(portID); err != nil {
+		return err
+	}
+	if m.client.checkState {
+		setting, err := m.GetPortSettings(ctx, portID)
+		if err != nil {
+			return NewOperationError(fmt.Sprintf("failed to check the POE state of port %d", portID), err)
+		}
+		if setting.Enabled == enabled {
+			if m.client.verbose {
+				fmt.Printf("POE on port %d already is in the desired state (enabled=%t), skipping the write\n", portID, enabled)
+			}
+			return nil
+		}
+	}
 	return m.UpdatePort(ctx, POEPortUpdate{
 		PortID:  portID,
 		Enabled: &enabled,
 	})
 }
 
+// poeClassMaxPowerW is the power a PSE provides per IEEE 802.3 class, class 0 is unclassified
+var poeClassMaxPowerW = map[string]float64{
+	"0": 15.4, "1": 4.0, "2": 7.0, "3": 15.4, "4": 30.0, "5": 45.0, "6": 60.0, "7": 75.0, "8": 90.0,
+}
+
+// defaultPOEPortPowerW is assumed for a port without a class or limit, the maximum of 802.3at
+const defaultPOEPortPowerW = 30.0
+
+// checkBudgetForPort fails with ErrInsufficientBudget, if enabling the port could draw more power than remains.
+// An already enabled port is fine, its consumption is part of the used budget.
+func (m *POEManager) checkBudgetForPort(ctx context.Context, portID int) error {
+	if err := m.client.GetModel().ValidatePOEPortID(portID); err != nil {
+		return err
+	}
+	setting, err := m.GetPortSettings(ctx, portID)
+	if err != nil {
+		return NewOperationError(fmt.Sprintf("failed to check the POE state of port %d", portID), err)
+	}
+	if setting.Enabled {
+		return nil
+	}
+	budget, err := m.GetPowerBudget(ctx)
+	if err != nil {
+		return NewOperationError(fmt.Sprintf("failed to check the POE budget for port %d", portID), err)
+	}
+	statuses, err := m.GetStatus(ctx)
+	if err != nil {
+		return NewOperationError(fmt.Sprintf("failed to check the POE class of port %d", portID), err)
+	}
+	powerClass := ""
+	for _, status := range statuses {
+		if status.PortID == portID {
+			powerClass = status.PowerClass
+		}
+	}
+
+	requiredW := requiredPOEPowerW(*setting, powerClass)
+	if requiredW > budget.RemainingPowerW {
+		return NewOperationError(fmt.Sprintf("enabling POE on port %d could draw %.1f W, but only %.1f W of the budget remain",
+			portID, requiredW, budget.RemainingPowerW), ErrInsufficientBudget)
+	}
+	return nil
+}
+
+// requiredPOEPowerW estimates the most power a port may draw: the maximum of its detected class,
+// capped by the port's power limit. Without either, the 802.3at maximum is assumed.
+func requiredPOEPowerW(setting POEPortSettings, powerClass string) float64 {
+	requiredW := defaultPOEPortPowerW
+	// the class is reported as e.g. "4" or "Class 4"
+	powerClass = strings.TrimSpace(powerClass)
+	if powerClass != "" {
+		if classW, ok := poeClassMaxPowerW[powerClass[len(powerClass)-1:]]; ok {
+			requiredW = classW
+		}
+	}
+	if setting.PowerLimitW > 0 && setting.PowerLimitW < requiredW {
+		requiredW = setting.PowerLimitW
+	}
+	return requiredW
+}
+
 // SetPortMode sets the POE mode for a specific port
 func (m *POEManager) SetPortMode(ctx context.Context, portID int, mode POEMode) error {
 	return m.UpdatePort(ctx, POEPortUpdate{
@@ -331,6 +704,56 @@ func (m *POEManager) GetPortStatus(ctx context.Context, portID int) (*POEPortSta
 	return nil, NewOperationError(fmt.Sprintf("port %d not found", portID), nil)
 }
 
+// WaitForStatus polls the status of a port until the predicate holds or the context is done.
+// On timeout the last observed status is returned together with the error.
+func (m *POEManager) WaitForStatus(ctx context.Context, portID int, predicate func(POEPortStatus) bool, pollInterval time.Duration) (*POEPortStatus, error) {
+	for {
+		status, err := m.GetPortStatus(ctx, portID)
+		if err != nil {
+			return nil, err
+		}
+		if predicate(*status) {
+			return status, nil
+		}
+
+		if err := m.client.sleep(ctx, pollInterval); err != nil {
+			return status, NewOperationError(fmt.Sprintf("port %d did not reach the expected POE status", portID), err)
+		}
+	}
+}
+
+// SampleAveragePower reads the power of a port samples times, interval apart, and returns the mean in watts.
+// A single reading of devices like cameras is noisy, the mean is closer to what they typically draw.
+// Samples whose power the switch didn't report as a number are skipped, as their 0 wasn't measured.
+func (m *POEManager) SampleAveragePower(ctx context.Context, portID int, samples int, interval time.Duration) (float64, error) {
+	if samples < 1 {
+		return 0, NewOperationError(fmt.Sprintf("invalid number of samples %d, must be positive", samples), nil)
+	}
+
+	var totalW float64
+	var validSamples int
+	for sample := 0; sample < samples; sample++ {
+		if sample > 0 {
+			if err := m.client.sleep(ctx, interval); err != nil {
+				return 0, NewOperationError(fmt.Sprintf("sampling the power of port %d was interrupted", portID), err)
+			}
+		}
+		status, err := m.GetPortStatus(ctx, portID)
+		if err != nil {
+			return 0, err
+		}
+		if !status.IsReadingValid("power_w") {
+			continue
+		}
+		totalW += status.PowerW
+		validSamples++
+	}
+	if validSamples == 0 {
+		return 0, NewParsingError(fmt.Sprintf("none of the %d samples of port %d reported its power as a number", samples, portID), ErrInvalidResponse)
+	}
+	return totalW / float64(validSamples), nil
+}
+
 // GetPortSettings gets the POE settings for a specific port
 func (m *POEManager) GetPortSettings(ctx context.Context, portID int) (*POEPortSettings, error) {
 	settings, err := m.GetSettings(ctx)
@@ -345,4 +768,256 @@ func (m *POEManager) GetPortSettings(ctx context.Context, portID int) (*POEPortS
 	}
 
 	return nil, NewOperationError(fmt.Sprintf("port %d not found", portID), nil)
-}
\ No newline at end of file
+}
+
+// GetPowerUpConfig retrieves the power-up mode and delay of all POE ports
+func (m *POEManager) GetPowerUpConfig(ctx context.Context) ([]POEPowerUpConfig, error) {
+	if !m.client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	// Determine the appropriate endpoint based on model
+	var endpoint string
+	switch m.client.GetModel().Series() {
+	case Series30x:
+		endpoint = "/PoEPortConfig.cgi"
+	case Series316:
+		endpoint = "/iss/specific/poePortConf.html"
+	default:
+		return nil, NewOperationError("POE power-up configuration not supported for this model", nil)
+	}
+
+	response, err := m.client.makeAuthenticatedRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, NewOperationError("failed to get POE power-up configuration", err)
+	}
+
+	rawData, err := m.parser.ParsePOEPowerUpConfig(response)
+	if err != nil {
+		return nil, NewParsingError("failed to parse POE power-up configuration", err)
+	}
+	if len(rawData) == 0 {
+		return nil, NewOperationError("POE power-up configuration is not supported by this firmware", nil)
+	}
+
+	return powerUpConfigsOf(rawData), nil
+}
+
+// powerUpConfigsOf converts the parsed power-up config of the ports
+func powerUpConfigsOf(rawData []map[string]interface{}) []POEPowerUpConfig {
+	var configs []POEPowerUpConfig
+	for _, raw := range rawData {
+		config := POEPowerUpConfig{}
+
+		if portID, ok := raw["port_id"].(int); ok {
+			config.PortID = portID
+		}
+		if mode, ok := raw["mode"].(string); ok {
+			config.Mode = POEPowerUpMode(mode)
+		}
+		if delay, ok := raw["delay_seconds"].(int); ok {
+			config.DelaySeconds = delay
+		}
+
+		configs = append(configs, config)
+	}
+
+	return configs
+}
+
+// SetPowerUpConfig sets the power-up mode and delay of a POE port
+func (m *POEManager) SetPowerUpConfig(ctx context.Context, portID int, config POEPowerUpConfig) error {
+	if err := m.client.GetModel().ValidatePOEPortID(portID); err != nil {
+		return err
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	config.PortID = portID
+	return m.UpdatePort(ctx, POEPortUpdate{
+		PortID:  portID,
+		PowerUp: &config,
+	})
+}
+
+// GetThermalStatus retrieves the system temperature and fan state, if the switch reports them
+func (m *POEManager) GetThermalStatus(ctx context.Context) (*ThermalStatus, error) {
+	if !m.client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	// Determine the appropriate endpoint based on model
+	var endpoint string
+	switch m.client.GetModel().Series() {
+	case Series30x:
+		endpoint = "/dashboard.cgi"
+	case Series316:
+		endpoint = "/iss/specific/dashboard.html"
+	default:
+		return nil, NewOperationError("thermal status not supported for this model", nil)
+	}
+
+	response, err := m.client.makeAuthenticatedRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, NewOperationError("failed to get thermal status", err)
+	}
+
+	raw, err := m.parser.ParseThermalStatus(response)
+	if err != nil {
+		return nil, NewParsingError("failed to parse thermal status", err)
+	}
+
+	thermal := &ThermalStatus{}
+	if temp, ok := raw["temperature_c"].(float64); ok {
+		thermal.TemperatureC = temp
+		thermal.Available = true
+	}
+	if status, ok := raw["status"].(string); ok {
+		thermal.Status = status
+		thermal.Available = true
+	}
+	if fan, ok := raw["fan_status"].(string); ok {
+		thermal.FanStatus = fan
+		thermal.Available = true
+	}
+
+	return thermal, nil
+}
+
+// GetPowerBudget retrieves the switch's total POE power budget and the power currently consumed.
+// If the firmware doesn't report the consumption, it is summed up from the port statuses.
+func (m *POEManager) GetPowerBudget(ctx context.Context) (*POEPowerBudget, error) {
+	if !m.client.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	// Determine the appropriate endpoint based on model
+	var endpoint string
+	switch m.client.GetModel().Series() {
+	case Series30x:
+		endpoint = "/PoEPortConfig.cgi"
+	case Series316:
+		endpoint = "/iss/specific/poePortConf.html"
+	default:
+		return nil, NewOperationError("POE power budget not supported for this model", nil)
+	}
+
+	response, err := m.client.makeAuthenticatedRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, NewOperationError("failed to get POE power budget", err)
+	}
+
+	raw, err := m.parser.ParsePOEPowerBudget(response)
+	if err != nil {
+		return nil, NewParsingError("failed to parse POE power budget", err)
+	}
+
+	budget := &POEPowerBudget{}
+	total, ok := raw["total_power_w"].(float64)
+	if !ok || total <= 0 {
+		return nil, NewOperationError("POE power budget not reported by this firmware", nil)
+	}
+	budget.TotalPowerW = total
+
+	if used, ok := raw["used_power_w"].(float64); ok {
+		budget.UsedPowerW = used
+	} else {
+		statuses, err := m.GetStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, status := range statuses {
+			budget.UsedPowerW += status.PowerW
+		}
+	}
+	budget.RemainingPowerW = budget.TotalPowerW - budget.UsedPowerW
+
+	return budget, nil
+}
+
+// GetPowerManagementMode returns the global POE power management mode of GS316 switches,
+// POEPowerManagementStatic or POEPowerManagementDynamic
+func (m *POEManager) GetPowerManagementMode(ctx context.Context) (string, error) {
+	if !m.client.IsAuthenticated() {
+		return "", ErrNotAuthenticated
+	}
+
+	endpoint, err := m.powerManagementEndpoint()
+	if err != nil {
+		return "", err
+	}
+
+	response, err := m.client.makeAuthenticatedRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return "", NewOperationError("failed to get POE power management mode", err)
+	}
+
+	mode, err := m.parser.ParsePOEPowerManagementMode(response)
+	if err != nil {
+		return "", NewParsingError("failed to parse POE power management mode", err)
+	}
+	if mode == "" {
+		return "", NewOperationError("POE power management mode not reported by this firmware", nil)
+	}
+	return mode, nil
+}
+
+// SetPowerManagementMode changes the global POE power management mode of GS316 switches,
+// which decides how the power budget is allocated across the ports
+func (m *POEManager) SetPowerManagementMode(ctx context.Context, mode string) error {
+	if !m.client.IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+
+	if err := validatePOEPowerManagementMode(mode); err != nil {
+		return err
+	}
+
+	endpoint, err := m.powerManagementEndpoint()
+	if err != nil {
+		return err
+	}
+
+	// the fields of the CLI's poe power-management, the Gambit token is added with the request
+	data := url.Values{
+		"TYPE":                  {"submitPoeMgmt"},
+		"POWER_MANAGEMENT_MODE": {strings.ToUpper(mode)},
+	}
+
+	response, err := m.client.makeWriteRequest(ctx, endpoint, data)
+	if err != nil {
+		return newWriteError("failed to set POE power management mode", err)
+	}
+
+	// Check for errors in response
+	if errorMsg := internal.ExtractErrorMessage(response); errorMsg != "" {
+		return NewOperationError("POE power management mode update failed: "+errorMsg, nil)
+	}
+
+	return nil
+}
+
+// powerManagementEndpoint returns the page with the power management mode, which only GS316 switches have
+func (m *POEManager) powerManagementEndpoint() (string, error) {
+	switch m.client.GetModel().Series() {
+	case Series316:
+		return "/iss/specific/poePortConf.html", nil
+	default:
+		return "", NewModelError("POE power management mode is only supported by GS316 switches", nil)
+	}
+}
+
+// CheckBudgetThreshold fetches the POE power budget and reports whether the consumption
+// reached the given percentage of the total budget, e.g. to warn before the PSU is saturated
+func (m *POEManager) CheckBudgetThreshold(ctx context.Context, thresholdPercent float64) (bool, *POEPowerBudget, error) {
+	if thresholdPercent <= 0 || thresholdPercent > 100 {
+		return false, nil, NewOperationError(fmt.Sprintf("invalid threshold %.1f%%, must be within 0..100", thresholdPercent), nil)
+	}
+
+	budget, err := m.GetPowerBudget(ctx)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return budget.UsagePercent() >= thresholdPercent, budget, nil
+}
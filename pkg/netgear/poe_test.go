@@ -0,0 +1,712 @@
+package netgear
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestCyclePowerReportsProgress(t *testing.T) {
+	mock := newMockSwitch(t)
+	var doneValues []int
+	var totals []int
+	client := newTestClient(t, mock, ModelGS308EPP, WithProgress(func(done, total int, current string) {
+		doneValues = append(doneValues, done)
+		totals = append(totals, total)
+	}))
+
+	err := client.POE().CyclePower(context.Background(), 1, 2, 3, 4)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, doneValues, is.EqualTo([]int{1, 2, 3, 4}))
+	then.AssertThat(t, totals, is.EqualTo([]int{4, 4, 4, 4}))
+	then.AssertThat(t, mock.requestsTo("POST", "/PoEPortConfig.cgi"), has.Length[mockRequest](4))
+}
+
+func TestCyclePowerStaggeredSpacesCycles(t *testing.T) {
+	mock := newMockSwitch(t)
+	var mu sync.Mutex
+	var cycledAt []time.Time
+	mock.handle("POST /PoEPortConfig.cgi", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		cycledAt = append(cycledAt, time.Now())
+		mu.Unlock()
+	})
+	client := newTestClient(t, mock, ModelGS308EPP)
+	stagger := 20 * time.Millisecond
+
+	err := client.POE().CyclePowerStaggered(context.Background(), stagger, 1, 2, 3)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, cycledAt, has.Length[time.Time](3))
+	for i := 1; i < len(cycledAt); i++ {
+		then.AssertThat(t, cycledAt[i].Sub(cycledAt[i-1]) >= stagger, is.True())
+	}
+}
+
+func TestCyclePowerStaggeredStopsOnCancel(t *testing.T) {
+	mock := newMockSwitch(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mock.handle("POST /PoEPortConfig.cgi", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostForm.Get("port") == "2" {
+			cancel()
+		}
+	})
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	client := newTestClient(t, mock, ModelGS308EPP, WithClock(clock))
+
+	err := client.POE().CyclePowerStaggered(ctx, time.Second, 1, 2, 3, 4)
+
+	then.AssertThat(t, errors.Is(err, context.Canceled), is.True())
+	then.AssertThat(t, mock.requestsTo("POST", "/PoEPortConfig.cgi"), has.Length[mockRequest](2))
+	then.AssertThat(t, clock.delays, has.Length[time.Duration](1))
+	then.AssertThat(t, clock.delays[0] >= time.Second && clock.delays[0] <= 1100*time.Millisecond, is.True())
+}
+
+func TestCycleAllCyclesOnlyDeliveringPorts(t *testing.T) {
+	mock := newMockSwitch(t)
+	port := func(id int, status string) string {
+		return fmt.Sprintf(`<li class="poePortStatusListItem"><input type="hidden" class="port" value="%d">`+
+			`<span class="poe-power-mode"><span>%s</span></span></li>`, id, status)
+	}
+	mock.respond("GET /getPoePortStatus.cgi", "<ul>"+port(1, "Delivering Power")+port(2, "Disabled")+
+		port(3, "Delivering Power")+"</ul>")
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	client := newTestClient(t, mock, ModelGS308EPP, WithClock(clock))
+
+	err := client.POE().CycleAll(context.Background())
+
+	then.AssertThat(t, err, is.Nil())
+	requests := mock.requestsTo("POST", "/PoEPortConfig.cgi")
+	then.AssertThat(t, requests, has.Length[mockRequest](2))
+	then.AssertThat(t, requests[0].Form.Get("port"), is.EqualTo("1"))
+	then.AssertThat(t, requests[1].Form.Get("port"), is.EqualTo("3"))
+	then.AssertThat(t, clock.delays, has.Length[time.Duration](1))
+}
+
+func TestGetStatusUnrecognizedLayout(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("/getPoePortStatus.cgi", `<html><body><div class="poe-v2-list">`+
+		strings.Repeat(`<div class="poe-v2-item"><span class="idx">1</span><span class="st">Delivering Power</span></div>`, 8)+
+		`</div></body></html>`)
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	statuses, err := client.POE().GetStatus(context.Background())
+
+	then.AssertThat(t, statuses, has.Length[POEPortStatus](0))
+	then.AssertThat(t, errors.Is(err, ErrInvalidResponse), is.True())
+}
+
+func TestGetStatusEmptyLoginPage(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("/getPoePortStatus.cgi", `<html><a href="/login.cgi">Login</a></html>`)
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	statuses, err := client.POE().GetStatus(context.Background())
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, statuses, has.Length[POEPortStatus](0))
+}
+
+func TestGetStatusRejectsDuplicatePorts(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("/getPoePortStatus.cgi", `<ul>`+
+		strings.Repeat(`<li class="poePortStatusListItem"><input type="hidden" class="port" value="1"></li>`, 2)+
+		`</ul>`)
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	statuses, err := client.POE().GetStatus(context.Background())
+
+	then.AssertThat(t, statuses, has.Length[POEPortStatus](0))
+	then.AssertThat(t, errors.Is(err, ErrInvalidResponse), is.True())
+	then.AssertThat(t, err.Error(), is.StringContaining("port 1 is listed more than once"))
+
+	_, err = client.POE().GetPortStatus(context.Background(), 1)
+
+	then.AssertThat(t, errors.Is(err, ErrInvalidResponse), is.True())
+}
+
+func TestGetThermalStatus(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("/iss/specific/dashboard.html", `<html><body><p class="System-Temperature-text">52</p>`+
+		`<p class="Fan-Status-text">Normal</p></body></html>`)
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	thermal, err := client.POE().GetThermalStatus(context.Background())
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, thermal.Available, is.True())
+	then.AssertThat(t, thermal.TemperatureC, is.EqualTo(52.0))
+	then.AssertThat(t, thermal.FanStatus, is.EqualTo("Normal"))
+}
+
+func TestSetPortPriorityKeepsNameAndMode(t *testing.T) {
+	mock := newMockSwitch(t)
+	ports := newFakeGS30xPorts()
+	ports[2].powerUpMode, ports[2].powerUpDelay = "delayed", "20"
+	mock.serveGS30xConfig(ports)
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	err := client.POE().SetPortPriority(context.Background(), 2, POEPriority("2"))
+	then.AssertThat(t, err, is.Nil())
+
+	then.AssertThat(t, ports[2].priority, is.EqualTo("2"))
+	then.AssertThat(t, ports[2].name, is.EqualTo("ap"))
+	then.AssertThat(t, ports[2].mode, is.EqualTo("0"))
+	then.AssertThat(t, ports[2].limitType, is.EqualTo("1"))
+	then.AssertThat(t, ports[2].limitW, is.EqualTo("30.0"))
+	then.AssertThat(t, ports[2].powerUpMode, is.EqualTo("delayed"))
+	then.AssertThat(t, ports[2].powerUpDelay, is.EqualTo("20"))
+}
+
+func TestWaitForStatusPollsUntilDelivering(t *testing.T) {
+	mock := newMockSwitch(t)
+	polls := 0
+	mock.handle("/getPoePortStatus.cgi", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		status := "Searching"
+		if polls > 2 {
+			status = "Delivering Power"
+		}
+		_, _ = w.Write([]byte(`<ul><li class="poePortStatusListItem"><input type="hidden" class="port" value="1">` +
+			`<span class="poe-power-mode"><span>` + status + `</span></span></li></ul>`))
+	})
+	clock := &fakeClock{}
+	client := newTestClient(t, mock, ModelGS308EPP, WithClock(clock))
+
+	status, err := client.POE().WaitForStatus(context.Background(), 1, func(s POEPortStatus) bool {
+		return s.Status == "Delivering Power"
+	}, time.Second)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, status.Status, is.EqualTo("Delivering Power"))
+	then.AssertThat(t, polls, is.EqualTo(3))
+	then.AssertThat(t, clock.delays, is.EqualTo([]time.Duration{time.Second, time.Second}))
+}
+
+func TestSampleAveragePower(t *testing.T) {
+	mock := newMockSwitch(t)
+	readings := []string{"3.2", "5.0", "4.1", "6.0", "3.7"}
+	polls := 0
+	mock.handle("/iss/specific/poePortStatus.html", func(w http.ResponseWriter, r *http.Request) {
+		reading := readings[polls%len(readings)]
+		polls++
+		_, _ = w.Write([]byte(`<div class="port-wrap"><span class="port-number">2</span><p class="OutputPower-text">` + reading + `</p></div>`))
+	})
+	clock := &fakeClock{}
+	client := newTestClient(t, mock, ModelGS316EP, WithClock(clock))
+
+	average, err := client.POE().SampleAveragePower(context.Background(), 2, 5, time.Second)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, math.Abs(average-4.4) < 1e-9, is.True())
+	then.AssertThat(t, polls, is.EqualTo(5))
+	then.AssertThat(t, clock.delays, has.Length[time.Duration](4))
+}
+
+func TestSampleAveragePowerSkipsUnparsedReadings(t *testing.T) {
+	mock := newMockSwitch(t)
+	readings := []string{"3.0", "N/A", "5.0"}
+	polls := 0
+	mock.handle("/iss/specific/poePortStatus.html", func(w http.ResponseWriter, r *http.Request) {
+		reading := readings[polls%len(readings)]
+		polls++
+		_, _ = w.Write([]byte(`<div class="port-wrap"><span class="port-number">2</span><p class="OutputPower-text">` + reading + `</p></div>`))
+	})
+	client := newTestClient(t, mock, ModelGS316EP, WithClock(&fakeClock{}))
+
+	average, err := client.POE().SampleAveragePower(context.Background(), 2, 3, time.Second)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, average, is.EqualTo(4.0))
+	then.AssertThat(t, polls, is.EqualTo(3))
+}
+
+func TestSampleAveragePowerFailsWithoutValidReadings(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("/iss/specific/poePortStatus.html", `<div class="port-wrap"><span class="port-number">2</span><p class="OutputPower-text">N/A</p></div>`)
+	client := newTestClient(t, mock, ModelGS316EP, WithClock(&fakeClock{}))
+
+	_, err := client.POE().SampleAveragePower(context.Background(), 2, 2, time.Second)
+
+	then.AssertThat(t, errors.Is(err, ErrInvalidResponse), is.True())
+}
+
+func TestGetStatusMarksUnparsedReadings(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("/getPoePortStatus.cgi", `<li class="poePortStatusListItem"><input type="hidden" class="port" value="1">`+
+		`<div class="poe_port_status"><div><div><span>ml570</span><span>53</span></div></div>`+
+		`<div><div><span>ml572</span><span>0</span></div></div>`+
+		`<div><div><span>ml574</span><span>N/A</span></div></div>`+
+		`<div><div><span>ml575</span><span>33</span></div></div></div></li>`)
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	statuses, err := client.POE().GetStatus(context.Background())
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, statuses, has.Length[POEPortStatus](1))
+	then.AssertThat(t, statuses[0].VoltageV, is.EqualTo(53.0))
+	then.AssertThat(t, statuses[0].IsReadingValid("current_ma"), is.True())
+	then.AssertThat(t, statuses[0].UnparsedReadings, is.EqualTo([]string{"power_w"}))
+}
+
+func TestSampleAveragePowerRespectsCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mock := newMockSwitch(t)
+	mock.handle("/iss/specific/poePortStatus.html", func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		_, _ = w.Write([]byte(`<div class="port-wrap"><span class="port-number">2</span><p class="OutputPower-text">4.0</p></div>`))
+	})
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	_, err := client.POE().SampleAveragePower(ctx, 2, 3, time.Hour)
+
+	then.AssertThat(t, errors.Is(err, context.Canceled), is.True())
+	then.AssertThat(t, mock.requestsTo("GET", "/iss/specific/poePortStatus.html"), has.Length[mockRequest](1))
+}
+
+func TestGetStatusFaultFromFixtures(t *testing.T) {
+	fixtures := map[Model]struct{ path, file string }{
+		ModelGS308EPP: {"/getPoePortStatus.cgi", "../../test-data/GS308EPP/getPoePortStatus.cgi.html"},
+		ModelGS316EP:  {"/iss/specific/poePortStatus.html", "../../test-data/GS316EP/poePortStatus_GetData_true.html"},
+	}
+
+	for model, fixture := range fixtures {
+		t.Run(string(model), func(t *testing.T) {
+			content, err := os.ReadFile(fixture.file)
+			then.AssertThat(t, err, is.Nil())
+			mock := newMockSwitch(t)
+			mock.respond(fixture.path, string(content))
+			client := newTestClient(t, mock, model)
+
+			statuses, err := client.POE().GetStatus(context.Background())
+
+			then.AssertThat(t, err, is.Nil())
+			then.AssertThat(t, len(statuses) > 0, is.True())
+			for _, status := range statuses {
+				then.AssertThat(t, status.ErrorStatus, is.EqualTo("No Error"))
+				then.AssertThat(t, status.Fault, is.EqualTo(POEFaultNone))
+			}
+		})
+	}
+}
+
+func TestGetStatusFromJSON(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("/iss/specific/poePortStatus.html", `[
+		{"port_id": 1, "port_name": "camera", "status": "Delivering Power", "power_class": "4",
+		 "voltage_v": 53.2, "current_ma": 84, "power_w": 4.5, "temperature_c": 31, "error_status": "No Error"},
+		{"port_id": 2, "port_name": "", "status": "Searching", "power_class": "",
+		 "voltage_v": 0, "current_ma": 0, "power_w": 0, "temperature_c": 30, "error_status": "Overload"}
+	]`)
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	statuses, err := client.POE().GetStatus(context.Background())
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, statuses, has.Length[POEPortStatus](2))
+	then.AssertThat(t, statuses[0].PortID, is.EqualTo(1))
+	then.AssertThat(t, statuses[0].PortName, is.EqualTo("camera"))
+	then.AssertThat(t, statuses[0].Status, is.EqualTo("Delivering Power"))
+	then.AssertThat(t, statuses[0].VoltageV, is.EqualTo(53.2))
+	then.AssertThat(t, statuses[0].PowerW, is.EqualTo(4.5))
+	then.AssertThat(t, statuses[0].Fault, is.EqualTo(POEFaultNone))
+	then.AssertThat(t, statuses[1].Fault, is.EqualTo(POEFaultOverload))
+	then.AssertThat(t, statuses[0].FaultReason, is.EqualTo(""))
+	then.AssertThat(t, statuses[1].FaultReason, is.EqualTo("the powered device draws more than the port's power limit"))
+
+	requests := mock.requestsTo("GET", "/iss/specific/poePortStatus.html")
+	then.AssertThat(t, requests, has.Length[mockRequest](1))
+	then.AssertThat(t, requests[0].Query.Get("GetData"), is.EqualTo("TRUE"))
+	then.AssertThat(t, requests[0].Query.Get("Gambit"), is.EqualTo(testToken))
+}
+
+func TestGetStatusFromMalformedJSON(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("/iss/specific/poePortStatus.html", `{"port_id": `)
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	_, err := client.POE().GetStatus(context.Background())
+
+	var netgearErr *Error
+	then.AssertThat(t, errors.As(err, &netgearErr), is.True())
+	then.AssertThat(t, netgearErr.Type, is.EqualTo(ErrorTypeParsing))
+}
+
+func TestSetPowerUpConfig(t *testing.T) {
+	mock := newMockSwitch(t)
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	err := client.POE().SetPowerUpConfig(context.Background(), 4, POEPowerUpConfig{
+		Mode:         POEPowerUpModeDelayed,
+		DelaySeconds: 20,
+	})
+
+	then.AssertThat(t, err, is.Nil())
+	requests := mock.requestsTo("POST", "/iss/specific/poePortConf.html")
+	then.AssertThat(t, requests, has.Length[mockRequest](1))
+	then.AssertThat(t, requests[0].Form.Get("port"), is.EqualTo("4"))
+	then.AssertThat(t, requests[0].Form.Get("power_up_mode"), is.EqualTo("delayed"))
+	then.AssertThat(t, requests[0].Form.Get("power_up_delay"), is.EqualTo("20"))
+}
+
+func TestSetPowerUpConfigRejectsInvalidDelay(t *testing.T) {
+	mock := newMockSwitch(t)
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	err := client.POE().SetPowerUpConfig(context.Background(), 4, POEPowerUpConfig{
+		Mode:         POEPowerUpModeDelayed,
+		DelaySeconds: 3600,
+	})
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+	then.AssertThat(t, mock.requestsTo("POST", "/iss/specific/poePortConf.html"), has.Length[mockRequest](0))
+}
+
+func TestCheckBudgetThreshold(t *testing.T) {
+	tests := []struct {
+		name       string
+		usedPowerW string
+		expected   bool
+	}{
+		{name: "85 percent", usedPowerW: "51.0", expected: false},
+		{name: "95 percent", usedPowerW: "57.0", expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mock := newMockSwitch(t)
+			mock.respond("/PoEPortConfig.cgi", `<html><body><input type="hidden" id="poeMaxPower" value="60.0">`+
+				`<input type="hidden" id="poeConsumedPower" value="`+test.usedPowerW+`"></body></html>`)
+			client := newTestClient(t, mock, ModelGS305EP)
+
+			over, budget, err := client.POE().CheckBudgetThreshold(context.Background(), 90)
+
+			then.AssertThat(t, err, is.Nil())
+			then.AssertThat(t, over, is.EqualTo(test.expected))
+			then.AssertThat(t, budget.TotalPowerW, is.EqualTo(60.0))
+		})
+	}
+}
+
+func TestGetPowerBudgetSumsPortPower(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("/iss/specific/poePortConf.html", `<html><body><p class="Power-Budget-text">120 W</p></body></html>`)
+	mock.respond("/iss/specific/poePortStatus.html", `[{"port_id": 1, "power_w": 12.5}, {"port_id": 2, "power_w": 7.5}]`)
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	budget, err := client.POE().GetPowerBudget(context.Background())
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, budget.TotalPowerW, is.EqualTo(120.0))
+	then.AssertThat(t, budget.UsedPowerW, is.EqualTo(20.0))
+	then.AssertThat(t, budget.RemainingPowerW, is.EqualTo(100.0))
+}
+
+func TestCheckBudgetThresholdRejectsInvalidThreshold(t *testing.T) {
+	mock := newMockSwitch(t)
+	client := newTestClient(t, mock, ModelGS305EP)
+
+	_, _, err := client.POE().CheckBudgetThreshold(context.Background(), 150)
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+	then.AssertThat(t, mock.requestsTo("GET", "/PoEPortConfig.cgi"), has.Length[mockRequest](0))
+}
+
+func TestClearFault(t *testing.T) {
+	mock := newMockSwitch(t)
+	errorStatus := "Overload"
+	mock.handle("/getPoePortStatus.cgi", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<ul><li class="poePortStatusListItem"><input type="hidden" class="port" value="1">` +
+			`<div class="poe_port_status"><div><div><span>ml581</span><span>` + errorStatus + `</span></div></div></div></li></ul>`))
+	})
+	mock.handle("POST /PoEPortConfig.cgi", func(w http.ResponseWriter, r *http.Request) {
+		errorStatus = "No Error"
+	})
+	client := newTestClient(t, mock, ModelGS308EPP, WithClock(&fakeClock{}))
+
+	status, err := client.POE().GetPortStatus(context.Background(), 1)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, status.Fault, is.EqualTo(POEFaultOverload))
+
+	err = client.POE().ClearFault(context.Background(), 1)
+
+	then.AssertThat(t, err, is.Nil())
+	requests := mock.requestsTo("POST", "/PoEPortConfig.cgi")
+	then.AssertThat(t, requests, has.Length[mockRequest](1))
+	then.AssertThat(t, requests[0].Form.Get("port"), is.EqualTo("1"))
+	status, err = client.POE().GetPortStatus(context.Background(), 1)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, status.ErrorStatus, is.EqualTo("No Error"))
+	then.AssertThat(t, status.Fault, is.EqualTo(POEFaultNone))
+}
+
+func TestClearFaultReportsLatchedFault(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("/getPoePortStatus.cgi", `<ul><li class="poePortStatusListItem"><input type="hidden" class="port" value="1">`+
+		`<div class="poe_port_status"><div><div><span>ml581</span><span>Short Circuit</span></div></div></div></li></ul>`)
+	clock := &fakeClock{}
+	client := newTestClient(t, mock, ModelGS308EPP, WithClock(clock))
+
+	err := client.POE().ClearFault(context.Background(), 1)
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+	then.AssertThat(t, strings.Contains(err.Error(), "Short Circuit"), is.True())
+	then.AssertThat(t, mock.requestsTo("GET", "/getPoePortStatus.cgi"), has.Length[mockRequest](faultClearAttempts))
+	then.AssertThat(t, clock.delays, has.Length[time.Duration](faultClearAttempts-1))
+}
+
+func TestDisablePortSkipsWriteWhenAlreadyDisabled(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.serveGS30xConfig(newFakeGS30xPorts())
+	client := newTestClient(t, mock, ModelGS308EPP, WithCheckBeforeWrite(true))
+
+	err := client.POE().DisablePort(context.Background(), 1)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, mock.requestsTo("POST", "/PoEPortConfig.cgi"), has.Length[mockRequest](1))
+
+	err = client.POE().DisablePort(context.Background(), 1)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, mock.requestsTo("POST", "/PoEPortConfig.cgi"), has.Length[mockRequest](1))
+}
+
+func TestDisablePortWritesWithoutCheck(t *testing.T) {
+	mock := newMockSwitch(t)
+	ports := newFakeGS30xPorts()
+	ports[1].poeEnabled = "0"
+	mock.serveGS30xConfig(ports)
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	err := client.POE().DisablePort(context.Background(), 1)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, mock.requestsTo("POST", "/PoEPortConfig.cgi"), has.Length[mockRequest](1))
+}
+
+func TestGetAndSetPowerManagementMode(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("GET /iss/specific/poePortConf.html", `<p class="Power-Management-Mode-text">Static Power</p>`)
+	mock.respond("POST /iss/specific/poePortConf.html", "")
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	mode, err := client.POE().GetPowerManagementMode(context.Background())
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, mode, is.EqualTo(POEPowerManagementStatic))
+
+	err = client.POE().SetPowerManagementMode(context.Background(), POEPowerManagementDynamic)
+	then.AssertThat(t, err, is.Nil())
+	requests := mock.requestsTo("POST", "/iss/specific/poePortConf.html")
+	then.AssertThat(t, requests, has.Length[mockRequest](1))
+	then.AssertThat(t, requests[0].Form.Get("POWER_MANAGEMENT_MODE"), is.EqualTo("DYNAMIC"))
+	then.AssertThat(t, requests[0].Form.Get("TYPE"), is.EqualTo("submitPoeMgmt"))
+	then.AssertThat(t, requests[0].Form.Get("Gambit"), is.EqualTo(testToken))
+}
+
+func TestSetPowerManagementModeRejectsInvalidMode(t *testing.T) {
+	mock := newMockSwitch(t)
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	err := client.POE().SetPowerManagementMode(context.Background(), "class")
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+	then.AssertThat(t, mock.requestsTo("POST", "/iss/specific/poePortConf.html"), has.Length[mockRequest](0))
+}
+
+func TestPowerManagementModeNotSupportedByGS30x(t *testing.T) {
+	mock := newMockSwitch(t)
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	_, err := client.POE().GetPowerManagementMode(context.Background())
+
+	var netgearErr *Error
+	then.AssertThat(t, errors.As(err, &netgearErr), is.True())
+	then.AssertThat(t, netgearErr.Type, is.EqualTo(ErrorTypeModel))
+}
+
+func TestUpdatePortEchoesGS316FormToken(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.handle("/iss/specific/poePortConf.html", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`<form><input type="hidden" name="csrf_token" value="c5rf"><select name="mode"></select></form>`))
+			return
+		}
+		if r.PostForm.Get("csrf_token") != "c5rf" {
+			_, _ = w.Write([]byte(`alert("invalid security token")`))
+		}
+	})
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	err := client.POE().DisablePort(context.Background(), 2)
+
+	then.AssertThat(t, err, is.Nil())
+	requests := mock.requestsTo("POST", "/iss/specific/poePortConf.html")
+	then.AssertThat(t, requests, has.Length[mockRequest](1))
+	then.AssertThat(t, requests[0].Form.Get("csrf_token"), is.EqualTo("c5rf"))
+}
+
+func TestUpdatePortTakesFormTokenOfSettingsPage(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.serveGS30xConfig(newFakeGS30xPorts())
+	settingsPage := mock.handlers["GET /PoEPortConfig.cgi"]
+	mock.handle("GET /PoEPortConfig.cgi", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<input type="hidden" name="hash" value="f00d">`))
+		settingsPage(w, r)
+	})
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	err := client.POE().DisablePort(context.Background(), 2)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, mock.requestsTo("GET", "/PoEPortConfig.cgi"), has.Length[mockRequest](1))
+	requests := mock.requestsTo("POST", "/PoEPortConfig.cgi")
+	then.AssertThat(t, requests, has.Length[mockRequest](1))
+	then.AssertThat(t, requests[0].Form.Get("hash"), is.EqualTo("f00d"))
+}
+
+func TestUpdatePortWithoutReadableFormToken(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.handle("GET /iss/specific/poePortConf.html", func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			_ = conn.Close()
+		}
+	})
+	mock.respond("POST /iss/specific/poePortConf.html", "")
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	err := client.POE().DisablePort(context.Background(), 2)
+
+	then.AssertThat(t, err, is.Nil())
+	requests := mock.requestsTo("POST", "/iss/specific/poePortConf.html")
+	then.AssertThat(t, requests, has.Length[mockRequest](1))
+	then.AssertThat(t, requests[0].Form.Has("csrf_token"), is.False())
+}
+
+func TestUpdatePortFallsBackToJSONForGS316(t *testing.T) {
+	mock := newMockSwitch(t)
+	var submitted []map[string]string
+	mock.handle("POST /iss/specific/poePortConf.html", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		fields := map[string]string{}
+		if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		submitted = append(submitted, fields)
+	})
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	err := client.POE().DisablePort(context.Background(), 2)
+	then.AssertThat(t, err, is.Nil())
+	err = client.POE().EnablePort(context.Background(), 3)
+	then.AssertThat(t, err, is.Nil())
+
+	then.AssertThat(t, submitted, has.Length[map[string]string](2))
+	then.AssertThat(t, submitted[0]["port"], is.EqualTo("2"))
+	then.AssertThat(t, submitted[0]["enabled"], is.EqualTo("0"))
+	then.AssertThat(t, submitted[1]["port"], is.EqualTo("3"))
+	then.AssertThat(t, submitted[1]["enabled"], is.EqualTo("1"))
+	// only the first form-encoded write is rejected, the client sticks to JSON afterwards
+	then.AssertThat(t, mock.requestsTo("POST", "/iss/specific/poePortConf.html"), has.Length[mockRequest](3))
+}
+
+func TestUpdatePortFormatsPowerLimitForModel(t *testing.T) {
+	tests := []struct {
+		model    Model
+		endpoint string
+		expected string
+	}{
+		{ModelGS308EPP, "/PoEPortConfig.cgi", "15.4"},
+		{ModelGS316EP, "/iss/specific/poePortConf.html", "154"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.model), func(t *testing.T) {
+			mock := newMockSwitch(t)
+			mock.serveGS30xConfig(newFakeGS30xPorts())
+			client := newTestClient(t, mock, test.model)
+
+			err := client.POE().SetPortPowerLimit(context.Background(), 1, POELimitTypeUser, 15.4)
+
+			then.AssertThat(t, err, is.Nil())
+			requests := mock.requestsTo("POST", test.endpoint)
+			then.AssertThat(t, requests, has.Length[mockRequest](1))
+			then.AssertThat(t, requests[0].Form.Get("power_limit_w"), is.EqualTo(test.expected))
+		})
+	}
+}
+
+func TestUpdatePortRejectsPowerLimitFinerThanTenths(t *testing.T) {
+	mock := newMockSwitch(t)
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	err := client.POE().SetPortPowerLimit(context.Background(), 1, POELimitTypeUser, 15.45)
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+	then.AssertThat(t, strings.Contains(err.Error(), "finer than the firmware's 0.1 W steps"), is.True())
+	then.AssertThat(t, mock.requestsTo("POST", "/PoEPortConfig.cgi"), has.Length[mockRequest](0))
+}
+
+func TestDisablePortOutOfRangeSendsNoRequest(t *testing.T) {
+	mock := newMockSwitch(t)
+	client := newTestClient(t, mock, ModelGS305EP, WithCheckBeforeWrite(true))
+
+	err := client.POE().DisablePort(context.Background(), 9)
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+	then.AssertThat(t, err.Error(), is.EqualTo("operation error: port 9 out of range (GS305EP has 4 ports)"))
+	then.AssertThat(t, mock.requestsTo("GET", "/PoEPortConfig.cgi"), has.Length[mockRequest](0))
+	then.AssertThat(t, mock.requestsTo("POST", "/PoEPortConfig.cgi"), has.Length[mockRequest](0))
+}
+
+func TestCyclePowerOutOfRangeSendsNoRequest(t *testing.T) {
+	mock := newMockSwitch(t)
+	client := newTestClient(t, mock, ModelGS316EPP)
+
+	err := client.POE().CyclePower(context.Background(), 1, 16)
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+	then.AssertThat(t, mock.requestsTo("POST", "/iss/specific/poePortConf.html"), has.Length[mockRequest](0))
+}
+
+func TestEnablePortWithBudgetGuardRefusesOverSubscription(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("/PoEPortConfig.cgi", `<html><body><input type="hidden" id="poeMaxPower" value="60.0">`+
+		`<input type="hidden" id="poeConsumedPower" value="52.0"><ul>`+
+		`<li class="poePortSettingListItem"><input type="hidden" class="port" value="1"><input id="hidPortPwr" value="0">`+
+		`<input id="hidLimitType" value="1"><input class="pwrLimit" value="30.00"></li>`+
+		`<li class="poePortSettingListItem"><input type="hidden" class="port" value="2"><input id="hidPortPwr" value="0">`+
+		`<input id="hidLimitType" value="2"><input class="pwrLimit" value="7.00"></li></ul></body></html>`)
+	mock.respond("/getPoePortStatus.cgi", `<ul><li class="poePortStatusListItem"><input type="hidden" class="port" value="1">`+
+		`<span class="poe-portPwr-width"><span>Class 4</span></span></li></ul>`)
+	client := newTestClient(t, mock, ModelGS305EP, WithBudgetGuard(true))
+
+	err := client.POE().EnablePort(context.Background(), 1)
+
+	then.AssertThat(t, errors.Is(err, ErrInsufficientBudget), is.True())
+	then.AssertThat(t, err.Error(), is.StringContaining("30.0 W"))
+	then.AssertThat(t, mock.requestsTo("POST", "/PoEPortConfig.cgi"), has.Length[mockRequest](0))
+
+	// the user limit of port 2 fits into the remaining 8 W
+	err = client.POE().EnablePort(context.Background(), 2)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, mock.requestsTo("POST", "/PoEPortConfig.cgi"), has.Length[mockRequest](1))
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 
 	"ntgrrc/pkg/netgear/internal"
 )
@@ -23,32 +25,99 @@ func newPortManager(client *Client) *PortManager {
 	}
 }
 
-// GetSettings retrieves port settings
+// GetSettings retrieves port settings. On GS30x the link state and speed are merged in from the dashboard,
+// which degrades gracefully: without a link state the fields stay empty.
+//
+// Unlike the other getters, GetSettings can return settings AND an error: if the GS30x config page
+// fails, the ports are returned with their link state only, their editable fields empty, along with
+// the error. Callers, which need the editable fields, must discard the settings on any error.
 func (m *PortManager) GetSettings(ctx context.Context) ([]PortSettings, error) {
+	settings, _, err := m.readSettings(ctx)
+	if err != nil {
+		if linkOnly := m.linkStateSettings(ctx); len(linkOnly) > 0 {
+			return linkOnly, err
+		}
+		return nil, err
+	}
+	m.mergeLinkStates(ctx, settings)
+	return settings, nil
+}
+
+// readLinkStates reads the link state of each port from the GS30x dashboard, nil if it's unavailable
+func (m *PortManager) readLinkStates(ctx context.Context) map[int]map[string]string {
+	if !m.client.GetModel().IsModel30x() {
+		return nil
+	}
+	response, err := m.client.makeAuthenticatedRequest(ctx, "GET", "/dashboard.cgi", nil)
+	if err != nil {
+		return nil
+	}
+	states, err := m.parser.ParsePortLinkStates(response)
+	if err != nil {
+		return nil
+	}
+	return states
+}
+
+// mergeLinkStates sets the status and the link speed of the settings from the dashboard
+func (m *PortManager) mergeLinkStates(ctx context.Context, settings []PortSettings) {
+	states := m.readLinkStates(ctx)
+	for i := range settings {
+		if state, ok := states[settings[i].PortID]; ok {
+			settings[i].Status = linkPortStatus(state["status"])
+			settings[i].LinkSpeed = state["link_speed"]
+		}
+	}
+}
+
+// linkStateSettings returns the ports of the dashboard with only their link state set
+func (m *PortManager) linkStateSettings(ctx context.Context) []PortSettings {
+	var settings []PortSettings
+	for portID, state := range m.readLinkStates(ctx) {
+		settings = append(settings, PortSettings{PortID: portID, Status: linkPortStatus(state["status"]), LinkSpeed: state["link_speed"]})
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].PortID < settings[j].PortID })
+	return settings
+}
+
+// linkPortStatus maps the link state of the GS30x dashboard to a PortStatus, "UP" is connected
+func linkPortStatus(state string) PortStatus {
+	switch strings.ToUpper(state) {
+	case "UP":
+		return PortStatusConnected
+	case "DOWN":
+		return PortStatusAvailable
+	}
+	return PortStatus(strings.ToLower(state))
+}
+
+// readSettings retrieves the port settings of the port configuration page and the page itself, e.g. for its form token
+func (m *PortManager) readSettings(ctx context.Context) ([]PortSettings, string, error) {
 	if !m.client.IsAuthenticated() {
-		return nil, ErrNotAuthenticated
+		return nil, "", ErrNotAuthenticated
 	}
 
 	// Determine the appropriate endpoint based on model
 	var endpoint string
-	if m.client.model.IsModel30x() {
+	switch m.client.GetModel().Series() {
+	case Series30x:
 		endpoint = "/PortStatistics.cgi"
-	} else if m.client.model.IsModel316() {
+	case Series316:
 		endpoint = "/iss/specific/interface.html"
-	} else {
-		return nil, NewOperationError("port settings not supported for this model", nil)
+	default:
+		return nil, "", NewOperationError("port settings not supported for this model", nil)
 	}
 
 	// Make authenticated request
 	response, err := m.client.makeAuthenticatedRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
-		return nil, NewOperationError("failed to get port settings", err)
+		return nil, "", NewOperationError("failed to get port settings", err)
 	}
 
 	// Parse the response
 	rawData, err := m.parser.ParsePortSettings(response)
 	if err != nil {
-		return nil, NewParsingError("failed to parse port settings", err)
+		return nil, "", NewParsingError("failed to parse port settings", err)
 	}
 
 	// Convert to strongly typed structures
@@ -62,8 +131,10 @@ func (m *PortManager) GetSettings(ctx context.Context) ([]PortSettings, error) {
 		if portName, ok := raw["port_name"].(string); ok {
 			setting.PortName = portName
 		}
+		// the firmware has no separate description, the description is the name
+		setting.Description = setting.PortName
 		if speed, ok := raw["speed"].(string); ok {
-			setting.Speed = PortSpeed(speed)
+			setting.Speed = normalizePortSpeed(speed)
 		}
 		if ingressLimit, ok := raw["ingress_limit"].(string); ok {
 			setting.IngressLimit = ingressLimit
@@ -71,6 +142,8 @@ func (m *PortManager) GetSettings(ctx context.Context) ([]PortSettings, error) {
 		if egressLimit, ok := raw["egress_limit"].(string); ok {
 			setting.EgressLimit = egressLimit
 		}
+		setting.IngressLimitKbps = rateLimitKbpsOrUnknown(setting.IngressLimit)
+		setting.EgressLimitKbps = rateLimitKbpsOrUnknown(setting.EgressLimit)
 		if flowControl, ok := raw["flow_control"].(bool); ok {
 			setting.FlowControl = flowControl
 		}
@@ -84,11 +157,20 @@ func (m *PortManager) GetSettings(ctx context.Context) ([]PortSettings, error) {
 		settings = append(settings, setting)
 	}
 
-	return settings, nil
+	if err := checkUniquePortIDs(settings, func(s PortSettings) int { return s.PortID }); err != nil {
+		return nil, "", err
+	}
+	return settings, response, nil
 }
 
 // UpdatePort updates settings for specific ports
 func (m *PortManager) UpdatePort(ctx context.Context, updates ...PortUpdate) error {
+	return m.updatePorts(ctx, updates, false)
+}
+
+// updatePorts applies the updates, reading the current settings only once. With collectErrors,
+// it carries on after a failed port and returns a MultiError of all failures.
+func (m *PortManager) updatePorts(ctx context.Context, updates []PortUpdate, collectErrors bool) error {
 	if !m.client.IsAuthenticated() {
 		return ErrNotAuthenticated
 	}
@@ -97,63 +179,246 @@ func (m *PortManager) UpdatePort(ctx context.Context, updates ...PortUpdate) err
 		return NewOperationError("no updates provided", nil)
 	}
 
+	// Fail fast on invalid updates, before any of them is sent
+	for _, update := range updates {
+		if err := update.Validate(m.client.GetModel()); err != nil {
+			return err
+		}
+	}
+
 	// Determine the appropriate endpoint based on model
 	var endpoint string
-	if m.client.model.IsModel30x() {
-		endpoint = "/PortConfig.cgi"
-	} else if m.client.model.IsModel316() {
+	switch m.client.GetModel().Series() {
+	case Series30x:
+		endpoint = "/port_status.cgi"
+	case Series316:
 		endpoint = "/iss/specific/interface.html"
-	} else {
+	default:
 		return NewOperationError("port updates not supported for this model", nil)
 	}
 
-	// Apply each update
+	aliased := make([]PortUpdate, 0, len(updates))
 	for _, update := range updates {
-		data := url.Values{}
+		update, err := descriptionAsName(update, m.client.GetModel())
+		if err != nil {
+			return err
+		}
+		aliased = append(aliased, update)
+	}
+	updates = aliased
+
+	// GS30x firmware resets every field missing from the form, so merge the current settings in
+	var current map[int]PortSettings
+	var page string
+	if m.client.GetModel().IsModel30x() {
+		settings, settingsPage, err := m.readSettings(ctx)
+		if err != nil {
+			return NewOperationError("failed to read current port settings", err)
+		}
+		page = settingsPage
+		current = make(map[int]PortSettings, len(settings))
+		for _, setting := range settings {
+			current[setting.PortID] = setting
+		}
+	}
+
+	tokenName, tokenValue := m.client.formToken(ctx, endpoint, page)
 
-		// Add port identification
-		data.Set("port", strconv.Itoa(update.PortID))
+	newForm := portUpdateForms[m.client.GetModel().Series()]
 
-		// Add updates based on what's provided
-		if update.Name != nil {
-			data.Set("name", *update.Name)
+	// Apply each update
+	var failures MultiError
+	for i, update := range updates {
+		if setting, ok := current[update.PortID]; ok && m.client.GetModel().IsModel30x() {
+			update = mergePortUpdate(update, setting)
 		}
 
-		if update.Speed != nil {
-			data.Set("speed", string(*update.Speed))
+		data := newForm(update)
+		if tokenName != "" {
+			data.Set(tokenName, tokenValue)
 		}
 
-		if update.IngressLimit != nil {
-			data.Set("ingress_limit", *update.IngressLimit)
+		if err := m.postPortUpdate(ctx, endpoint, update.PortID, data); err != nil {
+			if !collectErrors {
+				return err
+			}
+			failures.add(err)
 		}
 
-		if update.EgressLimit != nil {
-			data.Set("egress_limit", *update.EgressLimit)
+		m.client.reportProgress(i+1, len(updates), fmt.Sprintf("port %d", update.PortID))
+	}
+
+	return failures.errorOrNil()
+}
+
+// portUpdateForms build the update form of a port with the field names the firmware of each series expects
+var portUpdateForms = map[ModelSeries]func(update PortUpdate) url.Values{
+	Series30x: gs30xPortUpdateForm,
+	Series316: gs316PortUpdateForm,
+}
+
+// gs30xPortUpdateForm builds the port_status.cgi form of the CLI. The firmware resets every field
+// missing from it, so the update must have been merged with the current settings.
+func gs30xPortUpdateForm(update PortUpdate) url.Values {
+	data := url.Values{
+		fmt.Sprintf("port%d", update.PortID): {"checked"},
+		"priority":                          {"0"},
+	}
+	if update.Name != nil {
+		data.Set("DESCRIPTION", *update.Name)
+	}
+	if update.Speed != nil {
+		data.Set("SPEED", update.Speed.FormValue())
+	}
+	if update.IngressLimit != nil {
+		data.Set("IngressRate", rateLimitFormValue(*update.IngressLimit))
+	}
+	if update.EgressLimit != nil {
+		data.Set("EgressRate", rateLimitFormValue(*update.EgressLimit))
+	}
+	if update.FlowControl != nil {
+		if *update.FlowControl {
+			data.Set("FLOW_CONTROL", "1")
+		} else {
+			data.Set("FLOW_CONTROL", "2")
 		}
+	}
+	return data
+}
+
+// PORT_CTRL_MODE values of the GS316 interface form
+const (
+	gs316PortCtrlAuto    = "1" // auto-negotiation, speed and duplex aren't sent
+	gs316PortCtrlFixed   = "2" // auto-negotiation off, PORT_CTRL_SPEED and PORT_CTRL_DUPLEX are forced
+	gs316PortCtrlDisable = "3"
+)
+
+// gs316PortSpeed holds the PORT_CTRL_MODE, PORT_CTRL_SPEED and PORT_CTRL_DUPLEX fields of a speed,
+// an empty value isn't sent
+type gs316PortSpeed struct {
+	mode   string
+	speed  string // "1" 10M, "2" 100M
+	duplex string // "1" full, "2" half
+}
+
+// gs316PortSpeedFields maps the speeds to the GS316 form, every speed but auto disables auto-negotiation
+var gs316PortSpeedFields = map[PortSpeed]gs316PortSpeed{
+	PortSpeedAuto:     {mode: gs316PortCtrlAuto},
+	PortSpeedDisable:  {mode: gs316PortCtrlDisable},
+	PortSpeed10MHalf:  {mode: gs316PortCtrlFixed, speed: "1", duplex: "2"},
+	PortSpeed10MFull:  {mode: gs316PortCtrlFixed, speed: "1", duplex: "1"},
+	PortSpeed100MHalf: {mode: gs316PortCtrlFixed, speed: "2", duplex: "2"},
+	PortSpeed100MFull: {mode: gs316PortCtrlFixed, speed: "2", duplex: "1"},
+}
 
-		if update.FlowControl != nil {
-			if *update.FlowControl {
-				data.Set("flow_control", "on")
+// gs316PortUpdateForm builds the GS316 interface form, which marks the fields to keep with NOTSET
+func gs316PortUpdateForm(update PortUpdate) url.Values {
+	data := url.Values{
+		"TYPE":             {"portInfo"},
+		"PORT_NO":          {strconv.Itoa(update.PortID)},
+		"INGRESS":          {"NOTSET"},
+		"EGRESS":           {"NOTSET"},
+		"FLOW_CONTROL":     {"NOTSET"},
+		"PORT_CTRL_MODE":   {"NOTSET"},
+		"PORT_CTRL_SPEED":  {"NOTSET"},
+		"PORT_CTRL_DUPLEX": {"NOTSET"},
+		// fixed values the firmware expects in every portInfo form, like the CLI sends them
+		"COLOR1G":    {"NOTSET"},
+		"COLOR100M":  {"NOTSET"},
+		"FREQUENCY":  {"-1"},
+		"BRIGHTNESS": {"undefined"},
+		"STATUS":     {"0"},
+	}
+	if update.Name != nil {
+		data.Set("PORT_NAME", *update.Name)
+	}
+	if update.Speed != nil {
+		fields := gs316PortSpeedFields[normalizePortSpeed(string(*update.Speed))]
+		for name, value := range map[string]string{
+			"PORT_CTRL_MODE":   fields.mode,
+			"PORT_CTRL_SPEED":  fields.speed,
+			"PORT_CTRL_DUPLEX": fields.duplex,
+		} {
+			if value == "" {
+				data.Del(name)
 			} else {
-				data.Set("flow_control", "off")
+				data.Set(name, value)
 			}
 		}
-
-		// Make the update request
-		response, err := m.client.makeAuthenticatedRequest(ctx, "POST", endpoint, data)
-		if err != nil {
-			return NewOperationError(fmt.Sprintf("failed to update port %d", update.PortID), err)
+	}
+	if update.IngressLimit != nil {
+		data.Set("INGRESS", rateLimitFormValue(*update.IngressLimit))
+	}
+	if update.EgressLimit != nil {
+		data.Set("EGRESS", rateLimitFormValue(*update.EgressLimit))
+	}
+	if update.FlowControl != nil {
+		if *update.FlowControl {
+			data.Set("FLOW_CONTROL", "4")
+		} else {
+			data.Set("FLOW_CONTROL", "1")
 		}
+	}
+	return data
+}
 
-		// Check for errors in response
-		if errorMsg := internal.ExtractErrorMessage(response); errorMsg != "" {
-			return NewOperationError(fmt.Sprintf("update failed for port %d: %s", update.PortID, errorMsg), nil)
-		}
+// postPortUpdate sends the update form of a single port
+func (m *PortManager) postPortUpdate(ctx context.Context, endpoint string, portID int, data url.Values) error {
+	// Make the update request
+	response, err := m.client.makeWriteRequest(ctx, endpoint, data)
+	if err != nil {
+		return newWriteError(fmt.Sprintf("failed to update port %d", portID), err)
 	}
 
+	// Check for errors in response
+	if errorMsg := internal.ExtractErrorMessage(response); errorMsg != "" {
+		return NewOperationError(fmt.Sprintf("update failed for port %d: %s", portID, errorMsg), nil)
+	}
 	return nil
 }
 
+// rateLimitKbpsOrUnknown converts a rate limit reported by the switch, -1 if it can't be parsed
+func rateLimitKbpsOrUnknown(limit string) int {
+	kbps, err := ParseRateLimitKbps(limit)
+	if err != nil {
+		return -1
+	}
+	return kbps
+}
+
+// descriptionAsName turns the description of an update into the name, the firmware has no descriptions
+// apart from the names. A different name and description can't both be set then.
+func descriptionAsName(update PortUpdate, model Model) (PortUpdate, error) {
+	if update.Description == nil {
+		return update, nil
+	}
+	if update.Name != nil && *update.Name != *update.Description {
+		return update, NewOperationError(fmt.Sprintf("port %d has no description apart from its name on model %s", update.PortID, model), nil)
+	}
+	update.Name, update.Description = update.Description, nil
+	return update, update.Validate(model)
+}
+
+// mergePortUpdate fills all fields not set in the update with the port's current settings
+func mergePortUpdate(update PortUpdate, current PortSettings) PortUpdate {
+	if update.Name == nil {
+		update.Name = &current.PortName
+	}
+	if update.Speed == nil {
+		update.Speed = &current.Speed
+	}
+	if update.IngressLimit == nil {
+		update.IngressLimit = &current.IngressLimit
+	}
+	if update.EgressLimit == nil {
+		update.EgressLimit = &current.EgressLimit
+	}
+	if update.FlowControl == nil {
+		update.FlowControl = &current.FlowControl
+	}
+	return update
+}
+
 // SetPortName sets the name for a specific port
 func (m *PortManager) SetPortName(ctx context.Context, portID int, name string) error {
 	return m.UpdatePort(ctx, PortUpdate{
@@ -162,6 +427,32 @@ func (m *PortManager) SetPortName(ctx context.Context, portID int, name string)
 	})
 }
 
+// SetPortDescription sets the description of a port. The firmware has no descriptions apart from
+// the names, so the description is set as the port's name.
+func (m *PortManager) SetPortDescription(ctx context.Context, portID int, description string) error {
+	return m.UpdatePort(ctx, PortUpdate{
+		PortID:      portID,
+		Description: &description,
+	})
+}
+
+// SetPortNames renames several ports at once, e.g. after a deployment. The current settings are read
+// only once; failing ports don't stop the others and are reported together as a MultiError.
+func (m *PortManager) SetPortNames(ctx context.Context, names map[int]string) error {
+	portIDs := make([]int, 0, len(names))
+	for portID := range names {
+		portIDs = append(portIDs, portID)
+	}
+	sort.Ints(portIDs)
+
+	updates := make([]PortUpdate, 0, len(portIDs))
+	for _, portID := range portIDs {
+		name := names[portID]
+		updates = append(updates, PortUpdate{PortID: portID, Name: &name})
+	}
+	return m.updatePorts(ctx, updates, true)
+}
+
 // SetPortSpeed sets the speed for a specific port
 func (m *PortManager) SetPortSpeed(ctx context.Context, portID int, speed PortSpeed) error {
 	return m.UpdatePort(ctx, PortUpdate{
@@ -178,13 +469,53 @@ func (m *PortManager) SetPortFlowControl(ctx context.Context, portID int, enable
 	})
 }
 
-// SetPortLimits sets the ingress and egress limits for a specific port
+// SetAllFlowControl enables or disables flow control on every port of the switch. The port config
+// forms select a single port, so each port is sent separately, but the current settings are read only
+// once; failing ports don't stop the others and are reported together as a MultiError.
+func (m *PortManager) SetAllFlowControl(ctx context.Context, enabled bool) error {
+	count := m.client.GetModel().PortCount()
+	if count == 0 {
+		return NewOperationError(fmt.Sprintf("port count of %s is unknown", m.client.GetModel()), nil)
+	}
+
+	updates := make([]PortUpdate, 0, count)
+	for portID := 1; portID <= count; portID++ {
+		updates = append(updates, PortUpdate{PortID: portID, FlowControl: &enabled})
+	}
+	return m.updatePorts(ctx, updates, true)
+}
+
+// SetPortLimits sets the ingress and egress limits for a specific port. With WithLimitReadBack(true),
+// it reads the settings back and returns ErrRateLimitCoerced, naming the applied limits, if they differ.
 func (m *PortManager) SetPortLimits(ctx context.Context, portID int, ingressLimit, egressLimit string) error {
-	return m.UpdatePort(ctx, PortUpdate{
+	err := m.UpdatePort(ctx, PortUpdate{
 		PortID:       portID,
 		IngressLimit: &ingressLimit,
 		EgressLimit:  &egressLimit,
 	})
+	if err != nil || !m.client.readBack {
+		return err
+	}
+
+	applied, err := m.GetPortSettings(ctx, portID)
+	if err != nil {
+		return NewOperationError(fmt.Sprintf("failed to read back the limits of port %d", portID), err)
+	}
+	var problems MultiError
+	for _, limit := range []struct {
+		direction          string
+		requested, applied string
+		appliedKbps        int
+	}{
+		{"ingress", ingressLimit, applied.IngressLimit, applied.IngressLimitKbps},
+		{"egress", egressLimit, applied.EgressLimit, applied.EgressLimitKbps},
+	} {
+		if requestedKbps, err := ParseRateLimitKbps(limit.requested); err != nil || requestedKbps != limit.appliedKbps {
+			problems.add(NewOperationError(fmt.Sprintf("port %d applied the %s limit '%s' instead of '%s'",
+				portID, limit.direction, limit.applied, limit.requested), ErrRateLimitCoerced))
+		}
+	}
+	return problems.errorOrNil()
 }
 
 // GetPortSettings gets the settings for a specific port
@@ -219,4 +550,4 @@ func (m *PortManager) EnablePort(ctx context.Context, portID int) error {
 		PortID: portID,
 		Speed:  &speed,
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,392 @@
+package netgear
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func newFakeGS30xPorts() map[int]*fakeGS30xPort {
+	return map[int]*fakeGS30xPort{
+		1: {name: "camera", speed: "100M full", ingress: "No Limit", egress: "No Limit", flowControl: "On",
+			poeEnabled: "1", mode: "3", priority: "3", limitType: "2", limitW: "15.40", detection: "2"},
+		2: {name: "ap", speed: "auto", ingress: "No Limit", egress: "No Limit", flowControl: "Off",
+			poeEnabled: "1", mode: "0", priority: "0", limitType: "1", limitW: "30.00", detection: "2"},
+	}
+}
+
+func TestSetPortNameKeepsOtherSettings(t *testing.T) {
+	mock := newMockSwitch(t)
+	ports := newFakeGS30xPorts()
+	mock.serveGS30xConfig(ports)
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	err := client.Ports().SetPortName(context.Background(), 1, "doorbell")
+	then.AssertThat(t, err, is.Nil())
+
+	portSettings, err := client.Ports().GetPortSettings(context.Background(), 1)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, portSettings.PortName, is.EqualTo("doorbell"))
+	then.AssertThat(t, portSettings.Speed, is.EqualTo(PortSpeed("100M full")))
+	then.AssertThat(t, portSettings.IngressLimitKbps, is.EqualTo(NoRateLimitKbps))
+
+	poeSettings, err := client.POE().GetPortSettings(context.Background(), 1)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, poeSettings.Enabled, is.True())
+	then.AssertThat(t, poeSettings.Mode, is.EqualTo(POEMode("3")))
+	then.AssertThat(t, poeSettings.Priority, is.EqualTo(POEPriority("3")))
+}
+
+func TestSetPortSpeedSendsFormValue(t *testing.T) {
+	mock := newMockSwitch(t)
+	ports := newFakeGS30xPorts()
+	ports[2].speed = "100M Half"
+	mock.serveGS30xConfig(ports)
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	portSettings, err := client.Ports().GetPortSettings(context.Background(), 2)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, portSettings.Speed, is.EqualTo(PortSpeed100MHalf))
+
+	err = client.Ports().SetPortSpeed(context.Background(), 2, PortSpeed100MFull)
+	then.AssertThat(t, err, is.Nil())
+
+	requests := mock.requestsTo("POST", "/port_status.cgi")
+	then.AssertThat(t, requests, has.Length[mockRequest](1))
+	then.AssertThat(t, requests[0].Form.Get("SPEED"), is.EqualTo("6"))
+	portSettings, err = client.Ports().GetPortSettings(context.Background(), 2)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, portSettings.Speed, is.EqualTo(PortSpeed100MFull))
+}
+
+func TestSetPortSpeedFixedDisablesAutoNegotiationOnGS316(t *testing.T) {
+	tests := []struct {
+		speed  PortSpeed
+		mode   string
+		fields []string
+	}{
+		{PortSpeed100MFull, "2", []string{"2", "1"}},
+		{PortSpeedAuto, "1", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.speed), func(t *testing.T) {
+			mock := newMockSwitch(t)
+			client := newTestClient(t, mock, ModelGS316EP)
+
+			err := client.Ports().SetPortSpeed(context.Background(), 4, test.speed)
+
+			then.AssertThat(t, err, is.Nil())
+			then.AssertThat(t, test.speed.AutoNegotiation(), is.EqualTo(test.fields == nil))
+			requests := mock.requestsTo("POST", "/iss/specific/interface.html")
+			then.AssertThat(t, requests, has.Length[mockRequest](1))
+			form := requests[0].Form
+			then.AssertThat(t, form.Get("PORT_CTRL_MODE"), is.EqualTo(test.mode))
+			if test.fields == nil {
+				then.AssertThat(t, form.Has("PORT_CTRL_SPEED"), is.False())
+				then.AssertThat(t, form.Has("PORT_CTRL_DUPLEX"), is.False())
+			} else {
+				then.AssertThat(t, form.Get("PORT_CTRL_SPEED"), is.EqualTo(test.fields[0]))
+				then.AssertThat(t, form.Get("PORT_CTRL_DUPLEX"), is.EqualTo(test.fields[1]))
+			}
+		})
+	}
+}
+
+func TestSetPortLimitsReportsCoercedLimit(t *testing.T) {
+	mock := newMockSwitch(t)
+	ports := newFakeGS30xPorts()
+	mock.serveGS30xConfig(ports)
+	// like firmware snapping to another step, 2 Mbit/s is applied as 1 Mbit/s
+	accept := mock.handlers["POST /port_status.cgi"]
+	mock.handle("POST /port_status.cgi", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostForm.Get("IngressRate") == "4" {
+			r.PostForm.Set("IngressRate", "3")
+		}
+		accept(w, r)
+	})
+
+	tests := []struct {
+		name     string
+		readBack bool
+		coerced  bool
+	}{
+		{"with read-back", true, true},
+		{"without read-back", false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := newTestClient(t, mock, ModelGS308EPP, WithLimitReadBack(test.readBack))
+
+			err := client.Ports().SetPortLimits(context.Background(), 2, "2 Mbit/s", "No Limit")
+
+			then.AssertThat(t, errors.Is(err, ErrRateLimitCoerced), is.EqualTo(test.coerced))
+			if test.coerced {
+				then.AssertThat(t, err.Error(), is.StringContaining("port 2 applied the ingress limit '1 Mbit/s' instead of '2 Mbit/s'"))
+				then.AssertThat(t, strings.Contains(err.Error(), "egress"), is.False())
+			} else {
+				then.AssertThat(t, err, is.Nil())
+			}
+		})
+	}
+}
+
+func TestSetPortNames(t *testing.T) {
+	mock := newMockSwitch(t)
+	ports := newFakeGS30xPorts()
+	ports[3] = &fakeGS30xPort{name: "", speed: "auto", ingress: "No Limit", egress: "No Limit", flowControl: "Off"}
+	mock.serveGS30xConfig(ports)
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	err := client.Ports().SetPortNames(context.Background(), map[int]string{1: "AP-1", 2: "AP-2", 3: "AP-3"})
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, mock.requestsTo("GET", "/PortStatistics.cgi"), has.Length[mockRequest](1))
+	settings, err := client.Ports().GetSettings(context.Background())
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, settings, has.Length[PortSettings](3))
+	for i, setting := range settings {
+		then.AssertThat(t, setting.PortName, is.EqualTo(fmt.Sprintf("AP-%d", i+1)))
+	}
+	then.AssertThat(t, settings[0].Speed, is.EqualTo(PortSpeed100MFull))
+}
+
+func TestSetPortNamesCollectsFailures(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("GET /PortStatistics.cgi", "<table></table>")
+	mock.handle("POST /port_status.cgi", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostForm.Get("port2") != "checked" {
+			_, _ = w.Write([]byte(`alert("port is locked")`))
+		}
+	})
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	err := client.Ports().SetPortNames(context.Background(), map[int]string{1: "AP-1", 2: "AP-2", 3: "AP-3"})
+
+	var multiErr *MultiError
+	then.AssertThat(t, errors.As(err, &multiErr), is.True())
+	then.AssertThat(t, multiErr.Errors, has.Length[error](2))
+	then.AssertThat(t, strings.Contains(multiErr.Errors[0].Error(), "port 1"), is.True())
+	then.AssertThat(t, strings.Contains(multiErr.Errors[1].Error(), "port 3"), is.True())
+	then.AssertThat(t, mock.requestsTo("POST", "/port_status.cgi"), has.Length[mockRequest](3))
+}
+
+func TestGetSettingsMergesLinkStateOfDashboard(t *testing.T) {
+	dashboard, err := os.ReadFile("../../test-data/GS308EPP/dashboard.cgi.html")
+	then.AssertThat(t, err, is.Nil())
+	mock := newMockSwitch(t)
+	mock.serveGS30xConfig(newFakeGS30xPorts())
+	mock.respond("GET /dashboard.cgi", string(dashboard))
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	settings, err := client.Ports().GetSettings(context.Background())
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, settings, has.Length[PortSettings](2))
+	then.AssertThat(t, settings[0].PortName, is.EqualTo("camera"))
+	then.AssertThat(t, settings[0].Status, is.EqualTo(PortStatusConnected))
+	then.AssertThat(t, settings[0].LinkSpeed, is.EqualTo("1000M full"))
+	then.AssertThat(t, settings[1].Status, is.EqualTo(PortStatusAvailable))
+}
+
+func TestGetSettingsWithoutLinkStateKeepsEditableFields(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.serveGS30xConfig(newFakeGS30xPorts())
+	mock.respond("GET /dashboard.cgi", "")
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	settings, err := client.Ports().GetSettings(context.Background())
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, settings, has.Length[PortSettings](2))
+	then.AssertThat(t, settings[0].PortName, is.EqualTo("camera"))
+	then.AssertThat(t, settings[0].Speed, is.EqualTo(PortSpeed100MFull))
+	then.AssertThat(t, settings[0].FlowControl, is.True())
+	then.AssertThat(t, settings[0].Status, is.EqualTo(PortStatus("")))
+	then.AssertThat(t, settings[0].LinkSpeed, is.EqualTo(""))
+}
+
+func TestUpdatePortDoesNotReadDashboard(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.serveGS30xConfig(newFakeGS30xPorts())
+	client := newTestClient(t, mock, ModelGS308EPP)
+	name := "uplink"
+
+	err := client.Ports().UpdatePort(context.Background(), PortUpdate{PortID: 1, Name: &name})
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, mock.requestsTo("GET", "/dashboard.cgi"), has.Length[mockRequest](0))
+}
+
+func TestGetSettingsReturnsLinkStateWhenConfigPageFails(t *testing.T) {
+	dashboard, err := os.ReadFile("../../test-data/GS308EPP/dashboard.cgi.html")
+	then.AssertThat(t, err, is.Nil())
+	mock := newMockSwitch(t)
+	mock.respond("GET /PortStatistics.cgi", `<table><tr><th>Port</th></tr><tr><td>1</td></tr><tr><td>1</td></tr></table>`)
+	mock.respond("GET /dashboard.cgi", string(dashboard))
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	settings, err := client.Ports().GetSettings(context.Background())
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+	then.AssertThat(t, settings, has.Length[PortSettings](8))
+	then.AssertThat(t, settings[0].Status, is.EqualTo(PortStatusConnected))
+	then.AssertThat(t, settings[0].PortName, is.EqualTo(""))
+}
+
+func TestUpdatePortFailsFastOnInvalidUpdate(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.serveGS30xConfig(newFakeGS30xPorts())
+	client := newTestClient(t, mock, ModelGS305EP)
+
+	err := client.Ports().SetPortName(context.Background(), 9, "a port name longer than sixteen characters")
+
+	var multiErr *MultiError
+	then.AssertThat(t, errors.As(err, &multiErr), is.True())
+	then.AssertThat(t, multiErr.Errors, has.Length[error](2))
+	then.AssertThat(t, mock.requestsTo("GET", "/PortStatistics.cgi"), has.Length[mockRequest](0))
+	then.AssertThat(t, mock.requestsTo("POST", "/port_status.cgi"), has.Length[mockRequest](0))
+}
+
+func TestUpdatePortEchoesFormToken(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.serveGS30xConfig(newFakeGS30xPorts())
+	portStatistics := mock.handlers["GET /PortStatistics.cgi"]
+	mock.handle("GET /PortStatistics.cgi", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<input type="hidden" name="hash" value="f00d">`))
+		portStatistics(w, r)
+	})
+	rejectWithoutToken := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.PostForm.Get("hash") != "f00d" {
+				_, _ = w.Write([]byte(`alert("invalid security token")`))
+				return
+			}
+			next(w, r)
+		}
+	}
+	mock.handle("POST /port_status.cgi", rejectWithoutToken(mock.handlers["POST /port_status.cgi"]))
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	err := client.Ports().SetPortName(context.Background(), 1, "doorbell")
+
+	then.AssertThat(t, err, is.Nil())
+	requests := mock.requestsTo("POST", "/port_status.cgi")
+	then.AssertThat(t, requests, has.Length[mockRequest](1))
+	then.AssertThat(t, requests[0].Form.Get("hash"), is.EqualTo("f00d"))
+	settings, err := client.Ports().GetPortSettings(context.Background(), 1)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, settings.PortName, is.EqualTo("doorbell"))
+}
+
+func TestUpdatePortUsesFormFieldsOfModel(t *testing.T) {
+	name, speed, ingress, flowControl := "uplink", PortSpeed100MFull, "1 Mbit/s", false
+	update := PortUpdate{PortID: 2, Name: &name, Speed: &speed, IngressLimit: &ingress, FlowControl: &flowControl}
+	tests := []struct {
+		model    Model
+		endpoint string
+		expected map[string]string
+		absent   []string
+	}{
+		{ModelGS308EPP, "/port_status.cgi", map[string]string{
+			"port2": "checked", "DESCRIPTION": "uplink", "SPEED": "6", "IngressRate": "3", "EgressRate": "1", "FLOW_CONTROL": "2",
+			"priority": "0",
+		}, []string{"port", "name", "PORT_NO", "PORT_NAME"}},
+		{ModelGS316EP, "/iss/specific/interface.html", map[string]string{
+			"TYPE": "portInfo", "PORT_NO": "2", "PORT_NAME": "uplink", "PORT_CTRL_MODE": "2", "PORT_CTRL_SPEED": "2",
+			"PORT_CTRL_DUPLEX": "1", "INGRESS": "3", "EGRESS": "NOTSET", "FLOW_CONTROL": "1",
+			"COLOR1G": "NOTSET", "COLOR100M": "NOTSET", "FREQUENCY": "-1", "BRIGHTNESS": "undefined", "STATUS": "0",
+		}, []string{"port", "name", "speed", "ingress_limit"}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.model), func(t *testing.T) {
+			mock := newMockSwitch(t)
+			mock.serveGS30xConfig(newFakeGS30xPorts())
+			client := newTestClient(t, mock, test.model)
+
+			err := client.Ports().UpdatePort(context.Background(), update)
+
+			then.AssertThat(t, err, is.Nil())
+			requests := mock.requestsTo("POST", test.endpoint)
+			then.AssertThat(t, requests, has.Length[mockRequest](1))
+			for field, value := range test.expected {
+				then.AssertThat(t, requests[0].Form.Get(field), is.EqualTo(value))
+			}
+			for _, field := range test.absent {
+				then.AssertThat(t, requests[0].Form.Has(field), is.False())
+			}
+		})
+	}
+}
+
+func TestPortDescriptionAliasesName(t *testing.T) {
+	tests := []struct {
+		model     Model
+		endpoint  string
+		nameField string
+		absent    []string
+	}{
+		{ModelGS308EPP, "/port_status.cgi", "DESCRIPTION", []string{"description"}},
+		{ModelGS316EP, "/iss/specific/interface.html", "PORT_NAME", []string{"PORT_DESCRIPTION"}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.model), func(t *testing.T) {
+			mock := newMockSwitch(t)
+			mock.serveGS30xConfig(newFakeGS30xPorts())
+			mock.respond("GET /iss/specific/interface.html", `<table><tr><th>Port</th><th>Name</th><th>Speed</th></tr>`+
+				`<tr><td>2</td><td>uplink</td><td>Auto</td></tr></table>`)
+			client := newTestClient(t, mock, test.model)
+
+			settings, err := client.Ports().GetPortSettings(context.Background(), 2)
+			then.AssertThat(t, err, is.Nil())
+			then.AssertThat(t, settings.Description, is.EqualTo(settings.PortName))
+
+			err = client.Ports().SetPortDescription(context.Background(), 2, "gate camera")
+
+			then.AssertThat(t, err, is.Nil())
+			requests := mock.requestsTo("POST", test.endpoint)
+			then.AssertThat(t, requests, has.Length[mockRequest](1))
+			then.AssertThat(t, requests[0].Form.Get(test.nameField), is.EqualTo("gate camera"))
+			for _, field := range test.absent {
+				then.AssertThat(t, requests[0].Form.Has(field), is.False())
+			}
+		})
+	}
+}
+
+func TestPortDescriptionAsNameIsValidated(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.respond("GET /iss/specific/interface.html", `<table><tr><th>Port</th><th>Name</th></tr><tr><td>2</td><td>uplink</td></tr></table>`)
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	err := client.Ports().SetPortDescription(context.Background(), 2, "a description too long for a name")
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+	then.AssertThat(t, err.Error(), is.StringContaining("longer than 16 characters"))
+	then.AssertThat(t, mock.requestsTo("POST", "/iss/specific/interface.html"), has.Length[mockRequest](0))
+}
+
+func TestSetAllFlowControl(t *testing.T) {
+	mock := newMockSwitch(t)
+	client := newTestClient(t, mock, ModelGS316EP)
+
+	err := client.Ports().SetAllFlowControl(context.Background(), true)
+
+	then.AssertThat(t, err, is.Nil())
+	requests := mock.requestsTo("POST", "/iss/specific/interface.html")
+	then.AssertThat(t, requests, has.Length[mockRequest](16))
+	for i, request := range requests {
+		then.AssertThat(t, request.Form.Get("PORT_NO"), is.EqualTo(fmt.Sprintf("%d", i+1)))
+		then.AssertThat(t, request.Form.Get("FLOW_CONTROL"), is.EqualTo("4"))
+	}
+}
@@ -0,0 +1,166 @@
+package netgear
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+)
+
+// request is a single authenticated request passing through the request pipeline
+type request struct {
+	method      string
+	path        string
+	data        url.Values
+	headers     map[string]string
+	body        []byte // sent instead of data, if set, e.g. a JSON write
+	contentType string // of body
+	once        bool   // never retried, e.g. a power cycle the switch may have started
+	status      int    // HTTP status of the last response, 0 without one
+}
+
+// requestFunc performs a request and returns the response body
+type requestFunc func(ctx context.Context, req *request) (string, error)
+
+// requestStage wraps a requestFunc with one cross-cutting concern, e.g. retries or metrics
+type requestStage func(next requestFunc) requestFunc
+
+// requestStats holds the counters of the request pipeline
+type requestStats struct {
+	requests atomic.Uint64
+	retries  atomic.Uint64
+	errors   atomic.Uint64
+}
+
+// Stats is a snapshot of the request counters of a client
+type Stats struct {
+	RequestsTotal uint64 `json:"requests_total"`
+	RetriesTotal  uint64 `json:"retries_total"`
+	ErrorsTotal   uint64 `json:"errors_total"`
+}
+
+// Stats returns the number of HTTP requests sent, retries made and authenticated calls that failed
+func (c *Client) Stats() Stats {
+	return Stats{
+		RequestsTotal: c.stats.requests.Load(),
+		RetriesTotal:  c.stats.retries.Load(),
+		ErrorsTotal:   c.stats.errors.Load(),
+	}
+}
+
+// do sends an authenticated request through the pipeline; the first stage is the outermost
+func (c *Client) do(ctx context.Context, req *request) (string, error) {
+	stages := []requestStage{
+		c.metricsStage,
+		c.modelCheckStage,
+		c.retryStage,
+	}
+
+	send := c.send
+	for i := len(stages) - 1; i >= 0; i-- {
+		send = stages[i](send)
+	}
+	return send(ctx, req)
+}
+
+// metricsStage counts authenticated calls that finally failed
+func (c *Client) metricsStage(next requestFunc) requestFunc {
+	return func(ctx context.Context, req *request) (string, error) {
+		body, err := next(ctx, req)
+		if err != nil {
+			c.stats.errors.Add(1)
+		}
+		return body, err
+	}
+}
+
+// modelCheckStage checks a model of a cached token against the switch on the first request answered
+// with HTTP 401 or 404, e.g. because the switch at the address was replaced by one of another series,
+// whose endpoints differ. The token is discarded then and the detected model is used from now on.
+func (c *Client) modelCheckStage(next requestFunc) requestFunc {
+	return func(ctx context.Context, req *request) (string, error) {
+		body, err := next(ctx, req)
+		if req.status != http.StatusUnauthorized && req.status != http.StatusNotFound {
+			return body, err
+		}
+		if !c.cachedModel.CompareAndSwap(true, false) {
+			return body, err
+		}
+		cached := c.GetModel()
+		detected := c.staleCachedModel(ctx, cached)
+		if detected == "" {
+			return body, err
+		}
+		if c.verbose {
+			fmt.Printf("Warning: cached token is for model %s, but the switch is a %s - discarding the token\n", cached, detected)
+		}
+		c.mu.Lock()
+		c.model = detected
+		c.token = ""
+		c.authType = ""
+		c.mu.Unlock()
+		_ = c.tokenMgr.DeleteToken(ctx, c.address)
+		return "", NewAuthError(fmt.Sprintf("the cached session is for a %s, but the switch is a %s, login again", cached, detected), ErrSessionExpired)
+	}
+}
+
+// retryStage retries network errors, except for unresolvable host names, with exponential backoff, if enabled via WithRetry
+func (c *Client) retryStage(next requestFunc) requestFunc {
+	return func(ctx context.Context, req *request) (string, error) {
+		delay := c.retryDelay
+		for attempt := 0; ; attempt++ {
+			body, err := next(ctx, req)
+			if err == nil || !isNetworkError(err) || errors.Is(err, ErrHostResolution) || req.once || attempt >= c.maxRetries || ctx.Err() != nil {
+				return body, err
+			}
+
+			if c.verbose {
+				fmt.Printf("%s %s failed (%v), retrying in %s\n", req.method, req.path, err, delay)
+			}
+			if err := c.sleep(ctx, delay); err != nil {
+				return "", NewNetworkError(fmt.Sprintf("%s request failed", req.method), err)
+			}
+			c.stats.retries.Add(1)
+			delay *= 2
+		}
+	}
+}
+
+// send performs the HTTP request, it is the innermost stage of the pipeline
+func (c *Client) send(ctx context.Context, req *request) (string, error) {
+	c.stats.requests.Add(1)
+
+	var httpResp *http.Response
+	var err error
+	if req.method == "GET" {
+		httpResp, err = c.httpClient.Get(ctx, req.path, req.headers)
+	} else if req.body != nil {
+		httpResp, err = c.httpClient.PostBody(ctx, req.path, req.contentType, bytes.NewReader(req.body), req.headers)
+	} else {
+		httpResp, err = c.httpClient.Post(ctx, req.path, req.data, req.headers)
+	}
+	if err != nil {
+		req.status = 0
+		return "", newConnectionError(fmt.Sprintf("%s request failed", req.method), err)
+	}
+
+	req.status = httpResp.StatusCode
+	if httpResp.StatusCode == http.StatusUnauthorized {
+		httpResp.Body.Close()
+		return "", NewAuthError(fmt.Sprintf("%s %s rejected with HTTP 401, check the basic auth credentials", req.method, req.path), nil)
+	}
+	if httpResp.StatusCode == http.StatusUnsupportedMediaType {
+		httpResp.Body.Close()
+		return "", NewOperationError(fmt.Sprintf("%s %s rejected with HTTP 415", req.method, req.path), ErrUnsupportedContentType)
+	}
+	return c.httpClient.ReadBody(httpResp)
+}
+
+// isNetworkError reports whether err is a netgear network error
+func isNetworkError(err error) bool {
+	netgearErr, ok := err.(*Error)
+	return ok && netgearErr.Type == ErrorTypeNetwork
+}
@@ -0,0 +1,69 @@
+package netgear
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestStatsCountRequestsRetriesAndErrors(t *testing.T) {
+	mock := newMockSwitch(t)
+	dropNext := false
+	mock.handle("/getPoePortStatus.cgi", func(w http.ResponseWriter, r *http.Request) {
+		// a fresh connection per request, else the transport silently retries on a reused one
+		w.Header().Set("Connection", "close")
+		if dropNext {
+			// simulate a network failure by closing the connection without a response
+			dropNext = false
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		}
+	})
+	client := newTestClient(t, mock, ModelGS308EPP, WithClock(&fakeClock{}), WithRetry(1, time.Second))
+
+	_, err := client.POE().GetStatus(context.Background())
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, client.Stats(), is.EqualTo(Stats{RequestsTotal: 1}))
+
+	dropNext = true
+	_, err = client.POE().GetStatus(context.Background())
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, client.Stats(), is.EqualTo(Stats{RequestsTotal: 3, RetriesTotal: 1}))
+
+	mock.server.Close()
+	_, err = client.POE().GetStatus(context.Background())
+	then.AssertThat(t, err, is.Not(is.Nil()))
+	then.AssertThat(t, client.Stats(), is.EqualTo(Stats{RequestsTotal: 5, RetriesTotal: 2, ErrorsTotal: 1}))
+}
+
+func TestUnresolvableHostNameIsHostResolutionError(t *testing.T) {
+	// .invalid is reserved by RFC 2606, so it never resolves
+	client, err := NewClient("switch.invalid", WithEnvironmentAuth(false), WithClock(&fakeClock{}), WithRetry(3, time.Second))
+
+	then.AssertThat(t, client == nil, is.True())
+	then.AssertThat(t, errors.Is(err, ErrHostResolution), is.True())
+	then.AssertThat(t, errors.Is(err, ErrNetworkTimeout), is.False())
+}
+
+func TestCyclePowerIsNotRetried(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.handle("POST /PoEPortConfig.cgi", func(w http.ResponseWriter, r *http.Request) {
+		// the switch cycles the port, but the connection drops before the answer
+		w.Header().Set("Connection", "close")
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	})
+	client := newTestClient(t, mock, ModelGS308EPP, WithClock(&fakeClock{}), WithRetry(3, time.Second))
+
+	err := client.POE().CyclePower(context.Background(), 2)
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+	then.AssertThat(t, mock.requestsTo("POST", "/PoEPortConfig.cgi"), has.Length[mockRequest](1))
+	then.AssertThat(t, client.Stats().RetriesTotal, is.EqualTo(uint64(0)))
+}
@@ -0,0 +1,158 @@
+package netgear
+
+import (
+	"context"
+	"fmt"
+)
+
+// Snapshot reads the POE and port settings of all ports, e.g. to export them as JSON
+func (c *Client) Snapshot(ctx context.Context) (*Snapshot, error) {
+	if !c.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	snapshot := &Snapshot{Model: c.GetModel(), Timestamp: c.clock.Now()}
+	if c.Capabilities().POE {
+		poeSettings, err := c.POE().GetSettings(ctx)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.POE = poeSettings
+	}
+
+	portSettings, err := c.Ports().GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Ports = portSettings
+
+	return snapshot, nil
+}
+
+// ApplySnapshot updates every POE and port setting, which differs from the snapshot, and returns the updates.
+// With dryRun, the updates are only computed. The snapshot must be taken from a switch of the same series
+// with the same ports and capabilities, e.g. a snapshot of a detected GS30xEPx applies to a GS308EPP.
+func (c *Client) ApplySnapshot(ctx context.Context, snapshot *Snapshot, dryRun bool) (*SnapshotChanges, error) {
+	if !c.IsAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	if !isSnapshotCompatible(snapshot.Model, c.GetModel()) {
+		return nil, NewModelError(fmt.Sprintf("snapshot of a %s can't be applied to a %s", snapshot.Model, c.GetModel()), nil)
+	}
+
+	current, err := c.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changes := current.Diff(snapshot)
+	if dryRun {
+		return changes, nil
+	}
+
+	if len(changes.POE) > 0 {
+		if err := c.POE().UpdatePort(ctx, changes.POE...); err != nil {
+			return changes, err
+		}
+	}
+	if len(changes.Ports) > 0 {
+		if err := c.Ports().UpdatePort(ctx, changes.Ports...); err != nil {
+			return changes, err
+		}
+	}
+	return changes, nil
+}
+
+// Diff returns the updates, which change the settings of this snapshot to the wanted ones.
+// Ports missing from wanted are left alone, so wanted may contain only the ports to change.
+func (s *Snapshot) Diff(wanted *Snapshot) *SnapshotChanges {
+	return &SnapshotChanges{
+		POE:   diffPOESettings(s.POE, wanted.POE),
+		Ports: diffPortSettings(s.Ports, wanted.Ports),
+	}
+}
+
+// IsEmpty reports whether there is nothing to change
+func (c *SnapshotChanges) IsEmpty() bool {
+	return len(c.POE) == 0 && len(c.Ports) == 0
+}
+
+// diffPOESettings returns an update for each port with settings different from the wanted ones,
+// which sets only the differing fields
+func diffPOESettings(current, wanted []POEPortSettings) []POEPortUpdate {
+	byPort := make(map[int]POEPortSettings, len(current))
+	for _, setting := range current {
+		byPort[setting.PortID] = setting
+	}
+
+	var updates []POEPortUpdate
+	for _, want := range wanted {
+		have, ok := byPort[want.PortID]
+		update := POEPortUpdate{PortID: want.PortID}
+		changed := false
+		if !ok || have.Enabled != want.Enabled {
+			update.Enabled, changed = &want.Enabled, true
+		}
+		if !ok || have.Mode != want.Mode {
+			update.Mode, changed = &want.Mode, true
+		}
+		if !ok || have.Priority != want.Priority {
+			update.Priority, changed = &want.Priority, true
+		}
+		if !ok || have.PowerLimitType != want.PowerLimitType {
+			update.PowerLimitType, changed = &want.PowerLimitType, true
+		}
+		if !ok || have.PowerLimitW != want.PowerLimitW {
+			update.PowerLimitW, changed = &want.PowerLimitW, true
+		}
+		if !ok || have.DetectionType != want.DetectionType {
+			update.DetectionType, changed = &want.DetectionType, true
+		}
+		if changed {
+			updates = append(updates, update)
+		}
+	}
+	return updates
+}
+
+// diffPortSettings returns an update for each port with settings different from the wanted ones,
+// which sets only the differing fields
+func diffPortSettings(current, wanted []PortSettings) []PortUpdate {
+	byPort := make(map[int]PortSettings, len(current))
+	for _, setting := range current {
+		byPort[setting.PortID] = setting
+	}
+
+	var updates []PortUpdate
+	for _, want := range wanted {
+		have, ok := byPort[want.PortID]
+		update := PortUpdate{PortID: want.PortID}
+		changed := false
+		if !ok || have.PortName != want.PortName {
+			update.Name, changed = &want.PortName, true
+		}
+		if !ok || have.Speed != want.Speed {
+			update.Speed, changed = &want.Speed, true
+		}
+		if !ok || have.IngressLimit != want.IngressLimit {
+			update.IngressLimit, changed = &want.IngressLimit, true
+		}
+		if !ok || have.EgressLimit != want.EgressLimit {
+			update.EgressLimit, changed = &want.EgressLimit, true
+		}
+		if !ok || have.FlowControl != want.FlowControl {
+			update.FlowControl, changed = &want.FlowControl, true
+		}
+		if changed {
+			updates = append(updates, update)
+		}
+	}
+	return updates
+}
+
+// isSnapshotCompatible compares the series, port count and capabilities rather than the exact model,
+// since the same switch may be detected as the generic GS30xEPx or by its product name
+func isSnapshotCompatible(snapshotModel Model, model Model) bool {
+	return snapshotModel.Series() == model.Series() &&
+		snapshotModel.PortCount() == model.PortCount() &&
+		snapshotModel.Capabilities() == model.Capabilities()
+}
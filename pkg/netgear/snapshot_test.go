@@ -0,0 +1,92 @@
+package netgear
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestApplySnapshotSendsChangedSettings(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.serveGS30xConfig(newFakeGS30xPorts())
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	exported, err := client.Snapshot(context.Background())
+	then.AssertThat(t, err, is.Nil())
+	data, err := json.Marshal(exported)
+	then.AssertThat(t, err, is.Nil())
+	var snapshot Snapshot
+	then.AssertThat(t, json.Unmarshal(data, &snapshot), is.Nil())
+	snapshot.Ports[1].PortName = "uplink"
+	snapshot.POE[0].Priority = POEPriority("1")
+
+	changes, err := client.ApplySnapshot(context.Background(), &snapshot, true)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, changes.Ports, has.Length[PortUpdate](1))
+	then.AssertThat(t, *changes.Ports[0].Name, is.EqualTo("uplink"))
+	then.AssertThat(t, changes.Ports[0].Speed == nil, is.True())
+	then.AssertThat(t, changes.POE, has.Length[POEPortUpdate](1))
+	then.AssertThat(t, *changes.POE[0].Priority, is.EqualTo(POEPriority("1")))
+	then.AssertThat(t, mock.requestsTo("POST", "/port_status.cgi"), has.Length[mockRequest](0))
+	then.AssertThat(t, mock.requestsTo("POST", "/PoEPortConfig.cgi"), has.Length[mockRequest](0))
+
+	_, err = client.ApplySnapshot(context.Background(), &snapshot, false)
+
+	then.AssertThat(t, err, is.Nil())
+	portRequests := mock.requestsTo("POST", "/port_status.cgi")
+	then.AssertThat(t, portRequests, has.Length[mockRequest](1))
+	then.AssertThat(t, portRequests[0].Form.Get("port2"), is.EqualTo("checked"))
+	then.AssertThat(t, portRequests[0].Form.Get("DESCRIPTION"), is.EqualTo("uplink"))
+	poeRequests := mock.requestsTo("POST", "/PoEPortConfig.cgi")
+	then.AssertThat(t, poeRequests, has.Length[mockRequest](1))
+	then.AssertThat(t, poeRequests[0].Form.Get("priority"), is.EqualTo("1"))
+
+	changes, err = client.ApplySnapshot(context.Background(), &snapshot, true)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, changes.Ports, has.Length[PortUpdate](0))
+	then.AssertThat(t, changes.POE, has.Length[POEPortUpdate](0))
+}
+
+func TestApplySnapshotAcceptsModelOfSameSeries(t *testing.T) {
+	mock := newMockSwitch(t)
+	mock.serveGS30xConfig(newFakeGS30xPorts())
+	client := newTestClient(t, mock, ModelGS308EPP)
+	snapshot, err := client.Snapshot(context.Background())
+	then.AssertThat(t, err, is.Nil())
+	snapshot.Model = ModelGS30xEPx
+
+	changes, err := client.ApplySnapshot(context.Background(), snapshot, true)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, changes.Ports, has.Length[PortUpdate](0))
+	then.AssertThat(t, changes.POE, has.Length[POEPortUpdate](0))
+}
+
+func TestApplySnapshotRejectsModelWithOtherPortCount(t *testing.T) {
+	mock := newMockSwitch(t)
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	_, err := client.ApplySnapshot(context.Background(), &Snapshot{Model: ModelGS305EP}, false)
+
+	var netgearErr *Error
+	then.AssertThat(t, errors.As(err, &netgearErr), is.True())
+	then.AssertThat(t, netgearErr.Type, is.EqualTo(ErrorTypeModel))
+}
+
+func TestApplySnapshotRejectsOtherModel(t *testing.T) {
+	mock := newMockSwitch(t)
+	client := newTestClient(t, mock, ModelGS308EPP)
+
+	_, err := client.ApplySnapshot(context.Background(), &Snapshot{Model: ModelGS316EP}, false)
+
+	var netgearErr *Error
+	then.AssertThat(t, errors.As(err, &netgearErr), is.True())
+	then.AssertThat(t, netgearErr.Type, is.EqualTo(ErrorTypeModel))
+	then.AssertThat(t, mock.requestsTo("GET", "/PortStatistics.cgi"), has.Length[mockRequest](0))
+}
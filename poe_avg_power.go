@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type PoeAvgPowerCommand struct {
+	Address  string        `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Port     int           `required:"" help:"port number (starting with 1) to sample the power of" short:"p"`
+	Samples  int           `optional:"" help:"number of power readings to average" default:"5"`
+	Interval time.Duration `optional:"" help:"delay between two readings" default:"1s"`
+}
+
+func (poe *PoeAvgPowerCommand) Run(args *GlobalOptions) error {
+	client, err := newLibraryClient(args, poe.Address)
+	if err != nil {
+		return err
+	}
+	if err := validatePoePortIds(NetgearModel(client.GetModel()), []int{poe.Port}); err != nil {
+		return err
+	}
+	averageW, err := client.POE().SampleAveragePower(context.Background(), poe.Port, poe.Samples, poe.Interval)
+	if err != nil {
+		return err
+	}
+	prettyPrintPoeAvgPower(args.OutputFormat, poe.Port, poe.Samples, averageW)
+	return nil
+}
+
+func prettyPrintPoeAvgPower(format OutputFormat, port int, samples int, averageW float64) {
+	var header = []string{"Port ID", "Samples", "Average Power (W)"}
+	var content [][]string
+	content = append(content, []string{strconv.Itoa(port), strconv.Itoa(samples), fmt.Sprintf("%.2f", averageW)})
+	switch format {
+	case MarkdownFormat:
+		printMarkdownTable(header, content)
+	case JsonFormat:
+		printJsonDataTable("poe_avg_power", header, content)
+	default:
+		panic("not implemented format: " + format)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestPoeAvgPower(t *testing.T) {
+	readings := []string{"3.0", "5.5", "4.0", "6.5"}
+	var mu sync.Mutex
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/iss/specific/poePortStatus.html" {
+			return
+		}
+		mu.Lock()
+		reading := readings[polls%len(readings)]
+		polls++
+		mu.Unlock()
+		_, _ = w.Write([]byte(`<div class="port-wrap"><span class="port-number">3 - camera</span>` +
+			`<p class="OutputPower-text">` + reading + `</p></div>`))
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, "token", GS316EP)
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "poe", "avg-power", "--address", host, "--port", "3", "--samples", "4", "--interval", "1ms"})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	then.AssertThat(t, polls, is.EqualTo(4))
+	then.AssertThat(t, output, is.StringContaining("| 3       | 4       | 4.75              |"))
+}
+
+func TestPoeAvgPowerRejectsInvalidPort(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, "token", GS316EP)
+
+	var exitCode int
+	captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "poe", "avg-power", "--address", host, "--port", "17"})
+	})
+
+	then.AssertThat(t, exitCode, is.Not(is.EqualTo(exitCodeOK)))
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"io"
+	"ntgrrc/pkg/netgear"
+	"strconv"
+	"strings"
+)
+
+// errPoeBudgetThresholdExceeded makes 'poe budget --alert-at' exit with exitCodeThresholdExceeded
+var errPoeBudgetThresholdExceeded = errors.New("POE power budget threshold exceeded")
+
+type PoeBudgetCommand struct {
+	Address string  `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	AlertAt float64 `optional:"" help:"exit with a non-zero code, when the consumption reaches this percentage of the power budget, e.g. 90" name:"alert-at"`
+}
+
+func (poe *PoeBudgetCommand) Run(args *GlobalOptions) error {
+	if poe.AlertAt < 0 || poe.AlertAt > 100 {
+		return errors.New(fmt.Sprintf("invalid --alert-at %.1f, must be within 0..100", poe.AlertAt))
+	}
+
+	model, _, err := readTokenAndModel2GlobalOptions(args, poe.Address)
+	if err != nil {
+		return err
+	}
+	args.model = model
+
+	confPage, err := requestPoePortConfigPage(args, poe.Address)
+	if err != nil {
+		return err
+	}
+	if checkIsLoginRequired(confPage) {
+		return netgear.NewAuthError("no content. please, (re-)login first", nil)
+	}
+	budget, err := findPoeBudgetInHtml(args.model, strings.NewReader(confPage))
+	if err != nil {
+		return err
+	}
+	if budget.UsedPowerW < 0 {
+		// not reported by the firmware, so sum up the port consumption
+		statuses, err := requestPoeStatus(args, poe.Address)
+		if err != nil {
+			return err
+		}
+		budget.UsedPowerW = 0
+		for _, status := range statuses {
+			budget.UsedPowerW += float64(status.PowerInWatt)
+		}
+	}
+	budget.RemainingPowerW = budget.TotalPowerW - budget.UsedPowerW
+
+	prettyPrintPoeBudget(args.OutputFormat, budget)
+
+	if poe.AlertAt > 0 && budget.UsagePercent() >= poe.AlertAt {
+		return fmt.Errorf("%w: %.1f%% used, alert at %.1f%%", errPoeBudgetThresholdExceeded, budget.UsagePercent(), poe.AlertAt)
+	}
+	return nil
+}
+
+// findPoeBudgetInHtml returns the power budget; UsedPowerW is negative, if the page doesn't report the consumption
+func findPoeBudgetInHtml(model NetgearModel, reader io.Reader) (netgear.POEPowerBudget, error) {
+	budget := netgear.POEPowerBudget{UsedPowerW: -1}
+	doc, err := goquery.NewDocumentFromReader(reader)
+	if err != nil {
+		return budget, err
+	}
+
+	var total, used string
+	if isModel30x(model) {
+		total, _ = doc.Find("input#poeMaxPower").Attr("value")
+		used, _ = doc.Find("input#poeConsumedPower").Attr("value")
+	} else if isModel316(model) {
+		total = doc.Find("p.Power-Budget-text").Text()
+		used = doc.Find("p.Power-Consumption-text").Text()
+	} else {
+		panic("model not supported")
+	}
+
+	budget.TotalPowerW, err = strconv.ParseFloat(trimWatts(total), 64)
+	if err != nil || budget.TotalPowerW <= 0 {
+		return budget, netgear.NewOperationError("POE power budget not reported by this firmware", nil)
+	}
+	if watts, err := strconv.ParseFloat(trimWatts(used), 64); err == nil {
+		budget.UsedPowerW = watts
+	}
+	return budget, nil
+}
+
+func trimWatts(text string) string {
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "W"))
+}
+
+func prettyPrintPoeBudget(format OutputFormat, budget netgear.POEPowerBudget) {
+	var header = []string{"Power Budget (W)", "Consumption (W)", "Remaining (W)", "Usage (%)"}
+	var content [][]string
+	content = append(content, []string{
+		fmt.Sprintf("%.1f", budget.TotalPowerW),
+		fmt.Sprintf("%.1f", budget.UsedPowerW),
+		fmt.Sprintf("%.1f", budget.RemainingPowerW),
+		fmt.Sprintf("%.1f", budget.UsagePercent()),
+	})
+	switch format {
+	case MarkdownFormat:
+		printMarkdownTable(header, content)
+	case JsonFormat:
+		printJsonDataTable("poe_budget", header, content)
+	default:
+		panic("not implemented format: " + format)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestFindPoeBudgetInHtml(t *testing.T) {
+	tests := []struct {
+		model NetgearModel
+		html  string
+	}{
+		{
+			model: GS308EPP,
+			html:  `<input type="hidden" id="poeMaxPower" value="83.0"><input type="hidden" id="poeConsumedPower" value="20.5">`,
+		},
+		{
+			model: GS316EP,
+			html:  `<p class="Power-Budget-text">83.0 W</p><p class="Power-Consumption-text">20.5 W</p>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.model), func(t *testing.T) {
+			budget, err := findPoeBudgetInHtml(test.model, strings.NewReader(test.html))
+
+			then.AssertThat(t, err, is.Nil())
+			then.AssertThat(t, budget.TotalPowerW, is.EqualTo(83.0))
+			then.AssertThat(t, budget.UsedPowerW, is.EqualTo(20.5))
+		})
+	}
+}
+
+func TestFindPoeBudgetInHtmlWithoutBudget(t *testing.T) {
+	_, err := findPoeBudgetInHtml(GS308EPP, strings.NewReader(loadTestFile("GS308EPP", "PoEPortConfig.cgi.html")))
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+}
+
+func TestPoeBudgetAlertAt(t *testing.T) {
+	tests := []struct {
+		name       string
+		usedPowerW string
+		expected   int
+	}{
+		{name: "85 percent", usedPowerW: "51.0", expected: exitCodeOK},
+		{name: "95 percent", usedPowerW: "57.0", expected: exitCodeThresholdExceeded},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`<html><body><input type="hidden" id="poeMaxPower" value="60.0">` +
+					`<input type="hidden" id="poeConsumedPower" value="` + test.usedPowerW + `"></body></html>`))
+			}))
+			defer server.Close()
+			host := strings.TrimPrefix(server.URL, "http://")
+			tokenDir := t.TempDir()
+			writeTestToken(t, tokenDir, host, "token", GS305EP)
+
+			exitCode := run([]string{"--token-dir", tokenDir, "poe", "budget", "--address", host, "--alert-at", "90"})
+
+			then.AssertThat(t, exitCode, is.EqualTo(test.expected))
+		})
+	}
+}
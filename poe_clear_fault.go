@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"ntgrrc/pkg/netgear"
+	"slices"
+	"strings"
+)
+
+type PoeClearFaultCommand struct {
+	Address string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Ports   []int  `required:"" help:"port number (starting with 1), use multiple times for clearing multiple ports at once" short:"p" name:"port"`
+}
+
+func (poe *PoeClearFaultCommand) Run(args *GlobalOptions) error {
+	model, _, err := readTokenAndModel2GlobalOptions(args, poe.Address)
+	if err != nil {
+		return err
+	}
+	if err := validatePoePortIds(model, poe.Ports); err != nil {
+		return err
+	}
+
+	// a latched fault is reset by turning the port's power off and on again
+	err = resetPoePorts(args, poe.Address, poe.Ports)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := requestPoeStatus(args, poe.Address)
+	if err != nil {
+		return err
+	}
+	statuses = filter(statuses, func(status PoePortStatus) bool {
+		return slices.Contains(poe.Ports, int(status.PortIndex))
+	})
+	prettyPrintPoePortStatus(args.OutputFormat, statuses)
+
+	for _, status := range statuses {
+		if !isPoeFaultCleared(status.ErrorStatus) {
+			return netgear.NewOperationError(fmt.Sprintf("fault on port %d did not clear: %s", status.PortIndex, strings.TrimSpace(status.ErrorStatus)), nil)
+		}
+	}
+	return nil
+}
+
+func isPoeFaultCleared(errorStatus string) bool {
+	return strings.TrimSpace(errorStatus) == "" || netgear.ParsePOEFault(errorStatus) == netgear.POEFaultNone
+}
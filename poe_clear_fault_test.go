@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestPoeClearFault(t *testing.T) {
+	tests := []struct {
+		name          string
+		faultAfterRun string
+		expected      int
+	}{
+		{name: "fault clears", faultAfterRun: "No Error", expected: exitCodeOK},
+		{name: "fault stays latched", faultAfterRun: "Short Circuit", expected: exitCodeOperationError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			faultStatus := "Overload"
+			var resetPayload string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/iss/specific/poePortConf.html":
+					body, _ := io.ReadAll(r.Body)
+					form, _ := url.ParseQuery(string(body))
+					resetPayload = form.Get("PoePort")
+					faultStatus = test.faultAfterRun
+					_, _ = w.Write([]byte("SUCCESS"))
+				case r.URL.Path == "/iss/specific/poePortStatus.html":
+					_, _ = w.Write([]byte(`<div class="port-wrap"><span class="port-number">2 - camera</span>` +
+						`<p class="Fault-Status-text">` + faultStatus + `</p></div>`))
+				}
+			}))
+			defer server.Close()
+			host := strings.TrimPrefix(server.URL, "http://")
+			tokenDir := t.TempDir()
+			writeTestToken(t, tokenDir, host, "token", GS316EP)
+
+			var exitCode int
+			output := captureOutput(func() {
+				exitCode = run([]string{"--token-dir", tokenDir, "poe", "clear-fault", "--address", host, "--port", "2"})
+			})
+
+			then.AssertThat(t, exitCode, is.EqualTo(test.expected))
+			then.AssertThat(t, resetPayload, is.EqualTo("010000000000000"))
+			then.AssertThat(t, strings.Contains(output, test.faultAfterRun), is.True())
+		})
+	}
+}
+
+func TestIsPoeFaultCleared(t *testing.T) {
+	then.AssertThat(t, isPoeFaultCleared("No Error"), is.True())
+	then.AssertThat(t, isPoeFaultCleared(""), is.True())
+	then.AssertThat(t, isPoeFaultCleared("Overload"), is.False())
+}
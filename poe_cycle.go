@@ -10,11 +10,20 @@ import (
 )
 
 type PoeCyclePowerCommand struct {
-	Address string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
-	Ports   []int  `required:"" help:"port number (starting with 1), use multiple times for cycling multiple ports at once" short:"p" name:"port"`
+	Address  string   `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Ports    []int    `optional:"" help:"port number (starting with 1), use multiple times for cycling multiple ports at once" short:"p" name:"port"`
+	PortSpec []string `arg:"" optional:"" help:"port numbers or @group names of the switch's port-groups, e.g. @cameras" name:"ports"`
 }
 
 func (poe *PoeCyclePowerCommand) Run(args *GlobalOptions) error {
+	ports, err := portsWithSpec(args, poe.Ports, poe.PortSpec)
+	if err != nil {
+		return err
+	}
+	poe.Ports = ports
+	if len(poe.Ports) == 0 {
+		return errors.New("at least one port must be given, with --port or as argument, e.g. 3 or @cameras")
+	}
 	model := args.model
 	if len(model) == 0 {
 		var err error
@@ -25,6 +34,9 @@ func (poe *PoeCyclePowerCommand) Run(args *GlobalOptions) error {
 		args.model = model
 
 	}
+	if err := validatePoePortIds(model, poe.Ports); err != nil {
+		return err
+	}
 	if isModel30x(model) {
 		return poe.cyclePowerGs30xEPx(args)
 	}
@@ -35,9 +47,54 @@ func (poe *PoeCyclePowerCommand) Run(args *GlobalOptions) error {
 }
 
 func (poe *PoeCyclePowerCommand) cyclePowerGs30xEPx(args *GlobalOptions) error {
+	err := resetPoePortsGs30xEPx(args, poe.Address, poe.Ports)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := requestPoeStatus(args, poe.Address)
+	if err != nil {
+		return err
+	}
+	statuses = filter(statuses, func(status PoePortStatus) bool {
+		return slices.Contains(poe.Ports, int(status.PortIndex))
+	})
+
+	return nil
+}
+
+func (poe *PoeCyclePowerCommand) cyclePowerGs316EPx(args *GlobalOptions) error {
+	err := resetPoePortsGs316EPx(args, poe.Address, poe.Ports)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := requestPoeStatus(args, poe.Address)
+	if err != nil {
+		return err
+	}
+	statuses = filter(statuses, func(status PoePortStatus) bool {
+		return slices.Contains(poe.Ports, int(status.PortIndex))
+	})
+	prettyPrintPoePortStatus(args.OutputFormat, statuses)
+	return nil
+}
+
+// resetPoePorts turns the power of the given ports off and on again, which also clears latched faults
+func resetPoePorts(args *GlobalOptions, host string, ports []int) error {
+	if isModel30x(args.model) {
+		return resetPoePortsGs30xEPx(args, host, ports)
+	}
+	if isModel316(args.model) {
+		return resetPoePortsGs316EPx(args, host, ports)
+	}
+	panic("model not supported")
+}
+
+func resetPoePortsGs30xEPx(args *GlobalOptions, host string, ports []int) error {
 	poeExt := &PoeExt{}
 
-	settings, err := requestPoeConfiguration(args, poe.Address, poeExt)
+	settings, err := requestPoeConfiguration(args, host, poeExt)
 	if err != nil {
 		return err
 	}
@@ -47,49 +104,40 @@ func (poe *PoeCyclePowerCommand) cyclePowerGs30xEPx(args *GlobalOptions) error {
 		"ACTION": {"Reset"},
 	}
 
-	for _, switchPort := range poe.Ports {
+	for _, switchPort := range ports {
 		if switchPort < 1 || switchPort > len(settings) {
 			return errors.New(fmt.Sprintf("given port id %d, doesn't fit in range 1..%d", switchPort, len(settings)))
 		}
 		poeSettings.Add(fmt.Sprintf("port%d", switchPort-1), "checked")
 	}
 
-	result, err := requestPoeSettingsUpdate(args, poe.Address, poeSettings.Encode())
+	result, err := requestPoeSettingsUpdate(args, host, poeSettings.Encode())
 	if err != nil {
 		return err
 	}
 	if result != "SUCCESS" {
 		return errors.New(result)
 	}
-
-	statuses, err := requestPoeStatus(args, poe.Address)
-	if err != nil {
-		return err
-	}
-	statuses = filter(statuses, func(status PoePortStatus) bool {
-		return slices.Contains(poe.Ports, int(status.PortIndex))
-	})
-
 	return nil
 }
 
-func (poe *PoeCyclePowerCommand) cyclePowerGs316EPx(args *GlobalOptions) error {
-	for _, switchPort := range poe.Ports {
+func resetPoePortsGs316EPx(args *GlobalOptions, host string, ports []int) error {
+	for _, switchPort := range ports {
 		if switchPort < 1 || switchPort > gs316NoPoePorts {
 			return errors.New(fmt.Sprintf("given port id %d, doesn't fit in range 1..%d", switchPort, gs316NoPoePorts))
 		}
 	}
 
-	_, token, err := readTokenAndModel2GlobalOptions(args, poe.Address)
+	_, token, err := readTokenAndModel2GlobalOptions(args, host)
 	if err != nil {
 		return err
 	}
-	urlStr := fmt.Sprintf("http://%s/iss/specific/poePortConf.html", poe.Address)
+	urlStr := fmt.Sprintf("http://%s/iss/specific/poePortConf.html", host)
 	reqForm := url.Values{}
 	reqForm.Add("Gambit", token)
 	reqForm.Add("TYPE", "resetPoe")
-	reqForm.Add("PoePort", createPortResetPayloadGs316EPx(poe.Ports))
-	result, err := doHttpRequestAndReadResponse(args, http.MethodPost, poe.Address, urlStr, reqForm.Encode())
+	reqForm.Add("PoePort", createPortResetPayloadGs316EPx(ports))
+	result, err := doHttpRequestAndReadResponse(args, http.MethodPost, host, urlStr, reqForm.Encode())
 	if err != nil {
 		return err
 	}
@@ -99,15 +147,6 @@ func (poe *PoeCyclePowerCommand) cyclePowerGs316EPx(args *GlobalOptions) error {
 	if result != "SUCCESS" {
 		return errors.New(result)
 	}
-
-	statuses, err := requestPoeStatus(args, poe.Address)
-	if err != nil {
-		return err
-	}
-	statuses = filter(statuses, func(status PoePortStatus) bool {
-		return slices.Contains(poe.Ports, int(status.PortIndex))
-	})
-	prettyPrintPoePortStatus(args.OutputFormat, statuses)
 	return nil
 }
 
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+type PoeCycleAllCommand struct {
+	Address string        `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Stagger time.Duration `optional:"" help:"delay between two ports, so the devices don't all boot at once" default:"2s"`
+	Yes     bool          `optional:"" help:"power cycle without asking for confirmation" short:"y"`
+}
+
+func (poe *PoeCycleAllCommand) Run(args *GlobalOptions) error {
+	statuses, err := requestPoeStatus(args, poe.Address)
+	if err != nil {
+		return err
+	}
+	// disabled ports and ports without a powered device are skipped
+	statuses = filter(statuses, isPoePortDeliveringPower)
+	if len(statuses) == 0 {
+		fmt.Println("no PoE port is delivering power")
+		return nil
+	}
+
+	prettyPrintPoePortStatus(args.OutputFormat, statuses)
+	if !poe.Yes && !askConfirmation(fmt.Sprintf("power cycle these %d ports?", len(statuses))) {
+		fmt.Println("not cycled")
+		return nil
+	}
+
+	for i, status := range statuses {
+		if i > 0 {
+			time.Sleep(poe.Stagger)
+		}
+		if err := resetPoePorts(args, poe.Address, []int{int(status.PortIndex)}); err != nil {
+			return err
+		}
+		if args.Verbose {
+			fmt.Printf("power cycled port %d\n", status.PortIndex)
+		}
+	}
+	fmt.Printf("power cycled %d port(s)\n", len(statuses))
+	return nil
+}
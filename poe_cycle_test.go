@@ -1,8 +1,16 @@
 package main
 
 import (
+	"fmt"
+	"github.com/corbym/gocrest/has"
 	"github.com/corbym/gocrest/is"
 	"github.com/corbym/gocrest/then"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -13,3 +21,58 @@ func TestPoePortReset(t *testing.T) {
 	s = createPortResetPayloadGs316EPx([]int{1})
 	then.AssertThat(t, s, is.EqualTo("100000000000000"))
 }
+
+func TestPoeCycleAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		flags    []string
+		answer   string
+		expected []string
+	}{
+		{name: "confirmed", answer: "y\n", expected: []string{"100000000000000", "001000000000000"}},
+		{name: "with --yes", flags: []string{"--yes"}, expected: []string{"100000000000000", "001000000000000"}},
+		{name: "declined", answer: "n\n", expected: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var resets []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/iss/specific/poePortConf.html":
+					body, _ := io.ReadAll(r.Body)
+					form, _ := url.ParseQuery(string(body))
+					resets = append(resets, form.Get("PoePort"))
+					_, _ = w.Write([]byte("SUCCESS"))
+				case r.URL.Path == "/iss/specific/poePortStatus.html":
+					for port, status := range []string{"Delivering Power", "Disabled", "Delivering Power"} {
+						_, _ = fmt.Fprintf(w, `<div class="port-wrap"><span class="port-number">%d - camera</span>`+
+							`<span class="Status-text">%s</span></div>`, port+1, status)
+					}
+				}
+			}))
+			defer server.Close()
+			host := strings.TrimPrefix(server.URL, "http://")
+			tokenDir := t.TempDir()
+			writeTestToken(t, tokenDir, host, "token", GS316EP)
+			confirmationInput = strings.NewReader(test.answer)
+			defer func() { confirmationInput = os.Stdin }()
+
+			var exitCode int
+			output := captureOutput(func() {
+				exitCode = run(append([]string{"--token-dir", tokenDir, "poe", "cycle-all", "--address", host, "--stagger", "0s"}, test.flags...))
+			})
+
+			then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+			then.AssertThat(t, resets, has.Length[string](len(test.expected)))
+			for i, expected := range test.expected {
+				then.AssertThat(t, resets[i], is.EqualTo(expected))
+			}
+			if test.expected == nil {
+				then.AssertThat(t, output, is.StringContaining("not cycled"))
+			} else {
+				then.AssertThat(t, output, is.StringContaining("power cycled 2 port(s)"))
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"io"
+	"ntgrrc/pkg/netgear"
+	"slices"
+	"strings"
+)
+
+// poePowerManagementModes are the global power management modes of GS316 switches
+var poePowerManagementModes = []string{netgear.POEPowerManagementStatic, netgear.POEPowerManagementDynamic}
+
+type PoePowerMgmtCommand struct {
+	Address string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Mode    string `optional:"" help:"power management mode to set [static, dynamic], static reserves each port's power limit, dynamic only the consumed power" short:"m" name:"mode"`
+}
+
+func (poe *PoePowerMgmtCommand) Run(args *GlobalOptions) error {
+	mode := strings.ToLower(poe.Mode)
+	if mode != "" && !slices.Contains(poePowerManagementModes, mode) {
+		return errors.New(fmt.Sprintf("invalid power management mode '%s', allowed are %s", poe.Mode, strings.Join(poePowerManagementModes, ", ")))
+	}
+
+	model, token, err := readTokenAndModel2GlobalOptions(args, poe.Address)
+	if err != nil {
+		return err
+	}
+	if !isModel316(model) {
+		return netgear.NewModelError("PoE power management mode is only supported by GS316 switches", nil)
+	}
+	args.model = model
+
+	if mode != "" {
+		result, err := postPage(args, poe.Address, poeConfigUrl(model, poe.Address), createPoePowerManagementPayloadGs316(token, mode))
+		if err != nil {
+			return err
+		}
+		if result != "SUCCESS" {
+			return errors.New(result)
+		}
+	}
+
+	confPage, err := requestPoePortConfigPage(args, poe.Address)
+	if err != nil {
+		return err
+	}
+	if checkIsLoginRequired(confPage) {
+		return netgear.NewAuthError("no content. please, (re-)login first", nil)
+	}
+	current, err := findPoePowerManagementModeInHtml(strings.NewReader(confPage))
+	if err != nil {
+		return err
+	}
+	prettyPrintPoePowerManagementMode(args.OutputFormat, current)
+	return nil
+}
+
+func createPoePowerManagementPayloadGs316(token string, mode string) string {
+	return fmt.Sprintf("Gambit=%s&TYPE=%s&POWER_MANAGEMENT_MODE=%s", token, "submitPoeMgmt", strings.ToUpper(mode))
+}
+
+// findPoePowerManagementModeInHtml returns the mode of the GS316 POE config page as static or dynamic
+func findPoePowerManagementModeInHtml(reader io.Reader) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(reader)
+	if err != nil {
+		return "", err
+	}
+
+	text := strings.ToLower(strings.Join(strings.Fields(doc.Find("p.Power-Management-Mode-text").Text()), " "))
+	switch text {
+	case "static", "static power", "class", "class based", "class-based":
+		return netgear.POEPowerManagementStatic, nil
+	case "dynamic", "dynamic power":
+		return netgear.POEPowerManagementDynamic, nil
+	case "":
+		return "", netgear.NewOperationError("PoE power management mode not reported by this firmware", nil)
+	default:
+		return "", netgear.NewParsingError(fmt.Sprintf("unknown PoE power management mode '%s'", text), nil)
+	}
+}
+
+func prettyPrintPoePowerManagementMode(format OutputFormat, mode string) {
+	var header = []string{"Power Management Mode"}
+	var content = [][]string{{mode}}
+	switch format {
+	case MarkdownFormat:
+		printMarkdownTable(header, content)
+	case JsonFormat:
+		printJsonDataTable("poe_power_management", header, content)
+	default:
+		panic("not implemented format: " + format)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestFindPoePowerManagementModeInHtml(t *testing.T) {
+	tests := []struct {
+		html     string
+		expected string
+	}{
+		{html: `<p class="Power-Management-Mode-text">Dynamic Power</p>`, expected: "dynamic"},
+		{html: `<p class="Power-Management-Mode-text"> Static </p>`, expected: "static"},
+		{html: `<p class="Power-Management-Mode-text">Class based</p>`, expected: "static"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.html, func(t *testing.T) {
+			mode, err := findPoePowerManagementModeInHtml(strings.NewReader(test.html))
+
+			then.AssertThat(t, err, is.Nil())
+			then.AssertThat(t, mode, is.EqualTo(test.expected))
+		})
+	}
+}
+
+func TestFindPoePowerManagementModeInHtmlWithoutMode(t *testing.T) {
+	_, err := findPoePowerManagementModeInHtml(strings.NewReader(loadTestFile("GS316EP", "poePortConf.html")))
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+}
+
+func TestPoePowerManagementSetMode(t *testing.T) {
+	var payload string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			payload = string(body)
+			_, _ = w.Write([]byte("SUCCESS"))
+			return
+		}
+		_, _ = w.Write([]byte(`<html><body><p class="Power-Management-Mode-text">Dynamic Power</p></body></html>`))
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, "token", GS316EP)
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "poe", "power-management", "--address", host, "--mode", "dynamic"})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	then.AssertThat(t, payload, is.StringContaining("POWER_MANAGEMENT_MODE=DYNAMIC"))
+	then.AssertThat(t, payload, is.StringContaining("TYPE=submitPoeMgmt"))
+	then.AssertThat(t, output, is.StringContaining("| dynamic "))
+}
+
+func TestPoePowerManagementRejectsInvalidMode(t *testing.T) {
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, "localhost:1", "token", GS316EP)
+
+	exitCode := run([]string{"--token-dir", tokenDir, "poe", "power-management", "--address", "localhost:1", "--mode", "auto"})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeGeneralError))
+}
+
+func TestPoePowerManagementNotSupportedByGS30x(t *testing.T) {
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, "localhost:1", "token", GS308EPP)
+
+	exitCode := run([]string{"--token-dir", tokenDir, "poe", "power-management", "--address", "localhost:1"})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeModelError))
+}
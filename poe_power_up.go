@@ -0,0 +1,250 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"io"
+	"net/url"
+	"ntgrrc/pkg/netgear"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type PoePowerUpSetting struct {
+	PortIndex int8
+	PortName  string
+	Mode      string
+	Delay     string
+}
+
+type PoePowerUpCommand struct {
+	Address string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Ports   []int  `optional:"" help:"port number (starting with 1), use multiple times for setting multiple ports at once" short:"p" name:"port"`
+	Mode    string `optional:"" help:"power-up mode [immediate, delayed]" short:"m" name:"mode"`
+	Delay   *int   `optional:"" help:"power-up delay in seconds, 1-300, implies the delayed mode" name:"delay"`
+}
+
+func (poe *PoePowerUpCommand) Run(args *GlobalOptions) error {
+	model, _, err := readTokenAndModel2GlobalOptions(args, poe.Address)
+	if err != nil {
+		return err
+	}
+	if err := validatePoePortIds(model, poe.Ports); err != nil {
+		return err
+	}
+
+	if poe.Mode == "" && poe.Delay == nil {
+		settings, err := requestPoePowerUpSettings(args, poe.Address)
+		if err != nil {
+			return err
+		}
+		if len(poe.Ports) > 0 {
+			settings = filter(settings, func(setting PoePowerUpSetting) bool {
+				return slices.Contains(poe.Ports, int(setting.PortIndex))
+			})
+		}
+		prettyPrintPoePowerUpSettings(args.OutputFormat, settings)
+		return nil
+	}
+
+	if len(poe.Ports) == 0 {
+		return errors.New("at least one port must be given with --port to change the power-up configuration")
+	}
+	config, err := poe.powerUpConfig()
+	if err != nil {
+		return err
+	}
+
+	if isModel30x(model) {
+		err = poe.runPoePowerUpSetGs30x(args, config)
+	} else if isModel316(model) {
+		err = poe.runPoePowerUpSetGs316(args, config)
+	} else {
+		panic(fmt.Sprintf("model %s not supported", model))
+	}
+	if err != nil {
+		return err
+	}
+
+	settings, err := requestPoePowerUpSettings(args, poe.Address)
+	settings = filter(settings, func(setting PoePowerUpSetting) bool {
+		return slices.Contains(poe.Ports, int(setting.PortIndex))
+	})
+	prettyPrintPoePowerUpSettings(args.OutputFormat, settings)
+	return err
+}
+
+// powerUpConfig builds and validates the requested configuration, a delay without mode implies the delayed mode
+func (poe *PoePowerUpCommand) powerUpConfig() (netgear.POEPowerUpConfig, error) {
+	config := netgear.POEPowerUpConfig{Mode: netgear.POEPowerUpMode(strings.ToLower(poe.Mode))}
+	if poe.Delay != nil {
+		config.DelaySeconds = *poe.Delay
+		if config.Mode == "" {
+			config.Mode = netgear.POEPowerUpModeDelayed
+		}
+	}
+	return config, config.Validate()
+}
+
+func (poe *PoePowerUpCommand) runPoePowerUpSetGs30x(args *GlobalOptions, config netgear.POEPowerUpConfig) error {
+	poeExt := &PoeExt{}
+	currentPoeConfigs, err := requestPoeConfiguration(args, poe.Address, poeExt)
+	if err != nil {
+		return err
+	}
+
+	for _, portId := range poe.Ports {
+		if portId > len(currentPoeConfigs) || portId < 1 {
+			return errors.New(fmt.Sprintf("given port id %d, doesn't fit in range 1..%d", portId, len(currentPoeConfigs)))
+		}
+
+		// the switch resets all settings not part of the update, hence the current ones are sent as well
+		poeConfig := currentPoeConfigs[portId-1]
+		adminMode := "0"
+		if poeConfig.PortPwr {
+			adminMode = "1"
+		}
+
+		poeSettings := url.Values{
+			"hash":           {poeExt.Hash},
+			"ACTION":         {"Apply"},
+			"portID":         {strconv.Itoa(portId - 1)},
+			"ADMIN_MODE":     {adminMode},
+			"PORT_PRIO":      {poeConfig.PortPrio},
+			"POW_MOD":        {poeConfig.PwrMode},
+			"POW_LIMT_TYP":   {poeConfig.LimitType},
+			"POW_LIMT":       {poeConfig.PwrLimit},
+			"DETEC_TYP":      {poeConfig.DetecType},
+			"DISCONNECT_TYP": {poeConfig.LongerDetect},
+			"POW_UP_MODE":    {bidiMapLookup(string(config.Mode), powerUpModeMap)},
+			"POW_UP_DELAY":   {strconv.Itoa(config.DelaySeconds)},
+		}
+
+		result, err := requestPoeSettingsUpdate(args, poe.Address, poeSettings.Encode())
+		if err != nil {
+			return err
+		}
+
+		if result != "SUCCESS" {
+			return errors.New(result)
+		}
+	}
+	return nil
+}
+
+func (poe *PoePowerUpCommand) runPoePowerUpSetGs316(args *GlobalOptions, config netgear.POEPowerUpConfig) error {
+	_, token, err := readTokenAndModel2GlobalOptions(args, poe.Address)
+	if err != nil {
+		return err
+	}
+
+	for _, portId := range poe.Ports {
+		if portId < 1 || portId > gs316NoPoePorts {
+			return errors.New(fmt.Sprintf("given port id %d, doesn't fit in range 1..%d", portId, gs316NoPoePorts))
+		}
+
+		urlStr := fmt.Sprintf("http://%s/iss/specific/poePortConf.html", poe.Address)
+		result, err := postPage(args, poe.Address, urlStr, createPoePowerUpPayloadGs316(token, portId, config))
+		if err != nil {
+			return err
+		}
+
+		if result != "SUCCESS" {
+			return errors.New(result)
+		}
+	}
+	return nil
+}
+
+func createPoePowerUpPayloadGs316(token string, portId int, config netgear.POEPowerUpConfig) string {
+	// same field order as for 'poe set', all settings not changed here are NOTSET
+	payload := fmt.Sprintf("Gambit=%s&TYPE=%s&PORT_NO=%s", token, "submitPoe", strconv.Itoa(portId))
+	for _, field := range []string{"POWER_LIMIT_VALUE", "PRIORITY", "POWER_MODE", "POWER_LIMIT_TYPE", "DETECTION", "ADMIN_STATE", "DISCONNECT_TYPE"} {
+		payload += fmt.Sprintf("&%s=%s", field, "NOTSET")
+	}
+	payload += fmt.Sprintf("&POWER_UP_MODE=%s", bidiMapLookup(string(config.Mode), powerUpModeMap))
+	payload += fmt.Sprintf("&POWER_UP_DELAY=%d", config.DelaySeconds)
+	return payload
+}
+
+func requestPoePowerUpSettings(args *GlobalOptions, host string) ([]PoePowerUpSetting, error) {
+	confPage, err := requestPoePortConfigPage(args, host)
+	if err != nil {
+		return nil, err
+	}
+	if checkIsLoginRequired(confPage) {
+		return nil, netgear.NewAuthError("no content. please, (re-)login first", nil)
+	}
+	settings, err := findPoePowerUpInHtml(args.model, strings.NewReader(confPage))
+	if err != nil {
+		return nil, err
+	}
+	if len(settings) == 0 {
+		return nil, netgear.NewModelError("the firmware of this switch doesn't support a PoE power-up delay", nil)
+	}
+	return settings, nil
+}
+
+func findPoePowerUpInHtml(model NetgearModel, reader io.Reader) ([]PoePowerUpSetting, error) {
+	doc, err := goquery.NewDocumentFromReader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings []PoePowerUpSetting
+	if isModel30x(model) {
+		doc.Find("li.poePortSettingListItem").Each(func(i int, s *goquery.Selection) {
+			mode, exists := s.Find("input#hidPwrUpMode").Attr("value")
+			if !exists {
+				return
+			}
+			setting := PoePowerUpSetting{}
+			id, _ := s.Find("input[type=hidden].port").Attr("value")
+			var id64, _ = strconv.ParseInt(id, 10, 8)
+			setting.PortIndex = int8(id64)
+			setting.PortName, _ = s.Find("input[type=hidden].portName").Attr("value")
+			setting.Mode = bidiMapLookup(mode, powerUpModeMap)
+			setting.Delay, _ = s.Find("input.pwrUpDelay").Attr("value")
+			settings = append(settings, setting)
+		})
+	} else if isModel316(model) {
+		doc.Find("div#POE_SETTING div.port-wrap").Each(func(i int, s *goquery.Selection) {
+			modeSel := s.Find("p.Power-Up-Mode-text")
+			if modeSel.Length() == 0 {
+				return
+			}
+			setting := PoePowerUpSetting{}
+			idAndName := strings.TrimSpace(s.Find("span.port-number").Text())
+			setting.PortIndex, setting.PortName = parsePortIdAndName(idAndName)
+			setting.Mode = strings.ToLower(strings.TrimSpace(modeSel.Text()))
+			setting.Delay = strings.TrimSpace(s.Find("p.Power-Up-Delay-text").Text())
+			settings = append(settings, setting)
+		})
+	} else {
+		panic("model not supported")
+	}
+	return settings, nil
+}
+
+func prettyPrintPoePowerUpSettings(format OutputFormat, settings []PoePowerUpSetting) {
+	var header = []string{"Port ID", "Port Name", "Power-up Mode", "Delay (s)"}
+	var content [][]string
+	for _, setting := range settings {
+		var row []string
+		row = append(row, fmt.Sprintf("%d", setting.PortIndex))
+		row = append(row, setting.PortName)
+		row = append(row, setting.Mode)
+		row = append(row, setting.Delay)
+		content = append(content, row)
+	}
+	switch format {
+	case MarkdownFormat:
+		printMarkdownTable(header, content)
+	case JsonFormat:
+		printJsonDataTable("poe_power_up", header, content)
+	default:
+		panic("not implemented format: " + format)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+	"ntgrrc/pkg/netgear"
+)
+
+func TestFindPoePowerUpInHtml(t *testing.T) {
+	tests := []struct {
+		model         NetgearModel
+		html          string
+		expectedName  string
+		expectedMode  string
+		expectedDelay string
+	}{
+		{
+			model: GS308EPP,
+			html: `<ul><li class="poePortSettingListItem"><input type="hidden" class="port" value="2">` +
+				`<input type="hidden" class="portName" value="camera"><input type="hidden" id="hidPwrUpMode" value="1">` +
+				`<input type="hidden" class="pwrUpDelay" value="30"></li></ul>`,
+			expectedName:  "camera",
+			expectedMode:  "delayed",
+			expectedDelay: "30",
+		},
+		{
+			model: GS316EP,
+			html: `<div id="POE_SETTING"><div class="port-wrap"><span class="port-number">2 - camera</span>` +
+				`<p class="Power-Up-Mode-text">Immediate</p><p class="Power-Up-Delay-text">0</p></div></div>`,
+			expectedName:  "camera",
+			expectedMode:  "immediate",
+			expectedDelay: "0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.model), func(t *testing.T) {
+			settings, err := findPoePowerUpInHtml(test.model, strings.NewReader(test.html))
+
+			then.AssertThat(t, err, is.Nil())
+			then.AssertThat(t, settings, has.Length[PoePowerUpSetting](1))
+			then.AssertThat(t, settings[0].PortIndex, is.EqualTo(int8(2)))
+			then.AssertThat(t, settings[0].PortName, is.EqualTo(test.expectedName))
+			then.AssertThat(t, settings[0].Mode, is.EqualTo(test.expectedMode))
+			then.AssertThat(t, settings[0].Delay, is.EqualTo(test.expectedDelay))
+		})
+	}
+}
+
+func TestFindPoePowerUpInHtmlWithoutSupport(t *testing.T) {
+	content, err := os.ReadFile("test-data/GS308EPP/PoEPortConfig.cgi.html")
+	then.AssertThat(t, err, is.Nil())
+
+	settings, err := findPoePowerUpInHtml(GS308EPP, strings.NewReader(string(content)))
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, settings, has.Length[PoePowerUpSetting](0))
+}
+
+func TestPoePowerUpConfigFromFlags(t *testing.T) {
+	delay := 45
+	cmd := PoePowerUpCommand{Delay: &delay}
+
+	config, err := cmd.powerUpConfig()
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, config.Mode, is.EqualTo(netgear.POEPowerUpModeDelayed))
+	then.AssertThat(t, config.DelaySeconds, is.EqualTo(45))
+
+	tooLong := 301
+	cmd = PoePowerUpCommand{Delay: &tooLong}
+	_, err = cmd.powerUpConfig()
+	then.AssertThat(t, err, is.Not(is.Nil()))
+}
+
+func TestCreatePoePowerUpPayloadGs316(t *testing.T) {
+	payload := createPoePowerUpPayloadGs316("token42", 3, netgear.POEPowerUpConfig{
+		Mode:         netgear.POEPowerUpModeDelayed,
+		DelaySeconds: 10,
+	})
+
+	then.AssertThat(t, payload, is.EqualTo("Gambit=token42&TYPE=submitPoe&PORT_NO=3"+
+		"&POWER_LIMIT_VALUE=NOTSET&PRIORITY=NOTSET&POWER_MODE=NOTSET&POWER_LIMIT_TYPE=NOTSET"+
+		"&DETECTION=NOTSET&ADMIN_STATE=NOTSET&DISCONNECT_TYPE=NOTSET&POWER_UP_MODE=1&POWER_UP_DELAY=10"))
+}
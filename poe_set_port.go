@@ -6,6 +6,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"io"
 	"net/url"
+	"ntgrrc/pkg/netgear"
 	"slices"
 	"strconv"
 	"strings"
@@ -28,10 +29,12 @@ type PoeSetConfigCommand struct {
 	PortPwr      string `optional:"" help:"power state for port [enable, disable]" short:"s" name:"power"`
 	PwrMode      string `optional:"" help:"power mode [802.3af, legacy, pre-802.3at, 802.3at]" short:"m" name:"mode"`
 	PortPrio     string `optional:"" help:"priority [low, high, critical]" short:"r" name:"priority"`
-	LimitType    string `optional:"" help:"power limit type [none, class, user]" short:"t" name:"limit-type"`
+	LimitType    string `optional:"" help:"power limit type [none, class, user]" name:"limit-type"`
 	PwrLimit     string `optional:"" help:"power limit (W) [e.g. '30.0']" short:"l" name:"pwr-limit"`
 	DetecType    string `optional:"" help:"detection type [IEEE 802, legacy, 4pt 802.3af + Legacy]" short:"e" name:"detect-type"`
 	LongerDetect string `optional:"" help:"longer detection time [enable, disable]" name:"longer-detection-time"`
+	Diff         bool   `optional:"" help:"show the changes against the current settings first, and apply them only when confirmed" name:"diff"`
+	Yes          bool   `optional:"" help:"with --diff, apply the changes without asking" name:"yes" short:"y"`
 }
 
 type PoeExt struct {
@@ -49,6 +52,19 @@ func (poe *PoeSetConfigCommand) Run(args *GlobalOptions) error {
 		}
 	}
 	args.model = model // TODO: make the invariant of this variable consistent in the whole app
+	if err := validatePoePortIds(model, poe.Ports); err != nil {
+		return err
+	}
+	if poe.Diff {
+		changes, err := poe.diffPoeSettings(args)
+		if err != nil {
+			return err
+		}
+		apply, err := confirmChanges(args, changes, poe.Yes)
+		if !apply {
+			return err
+		}
+	}
 
 	if isModel30x(model) {
 		return poe.runPoeSetConfigGs30x(args)
@@ -284,7 +300,7 @@ func requestPoeConfiguration(args *GlobalOptions, host string, poeExt *PoeExt) (
 	}
 
 	if checkIsLoginRequired(settingsPage) {
-		return settings, errors.New("no content. please, (re-)login first")
+		return settings, netgear.NewAuthError("no content. please, (re-)login first", nil)
 	}
 
 	settings, err = findPoePortConfInHtml(args.model, strings.NewReader(settingsPage))
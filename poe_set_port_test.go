@@ -1,8 +1,12 @@
 package main
 
 import (
+	"github.com/corbym/gocrest/has"
 	"github.com/corbym/gocrest/is"
 	"github.com/corbym/gocrest/then"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 )
@@ -283,3 +287,66 @@ func TestCreatePoeSetConfigPayloadGs316_just_mandatory_no_optional_fields(t *tes
 	then.AssertThat(t, payload, is.StringContaining("ADMIN_STATE=NOTSET"))
 	then.AssertThat(t, payload, is.StringContaining("DISCONNECT_TYPE=NOTSET"))
 }
+
+func TestPoeSetRejectsPortOutOfRangeBeforeRequest(t *testing.T) {
+	tokenDir := t.TempDir()
+	// nothing listens on this address, so any request would end with a network error
+	writeTestToken(t, tokenDir, "localhost:1", "token", GS305EP)
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "poe", "set", "--address", "localhost:1", "--port", "5", "--power", "disable"})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOperationError))
+	then.AssertThat(t, output, is.StringContaining("port 5 out of range (GS305EP has 4 ports)"))
+}
+
+// newPoeConfigServer serves the GS305EP POE config page and records the paths of POST requests
+func newPoeConfigServer(t *testing.T) (host string, posts *[]string) {
+	posts = &[]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			*posts = append(*posts, r.URL.Path)
+			_, _ = w.Write([]byte("SUCCESS"))
+			return
+		}
+		_, _ = w.Write([]byte(loadTestFile(string(GS305EP), "PoEPortConfig.cgi.html")))
+	}))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://"), posts
+}
+
+func TestPoeSetDiffWithoutChangesMakesNoRequest(t *testing.T) {
+	host, posts := newPoeConfigServer(t)
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, "token", GS305EP)
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "poe", "set", "--address", host, "--port", "1",
+			"--power", "disable", "--mode", "802.3at", "--priority", "low", "--diff"})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	then.AssertThat(t, output, is.StringContaining("no changes"))
+	then.AssertThat(t, *posts, has.Length[string](0))
+}
+
+func TestPoeSetDiffAppliesOnlyWhenConfirmed(t *testing.T) {
+	host, posts := newPoeConfigServer(t)
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, "token", GS305EP)
+	confirmationInput = strings.NewReader("n\n")
+	defer func() { confirmationInput = os.Stdin }()
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "poe", "set", "--address", host, "--port", "1", "--mode", "802.3af", "--diff"})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeNotApplied))
+	then.AssertThat(t, output, is.StringContaining("| 1       | POE mode | 802.3af |"))
+	then.AssertThat(t, output, is.StringContaining("not applied"))
+	then.AssertThat(t, *posts, has.Length[string](0))
+}
@@ -1,13 +1,13 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 	"io"
 	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"ntgrrc/pkg/netgear"
 )
 
 const gs316NoPoePorts = 15
@@ -44,7 +44,7 @@ func (poe *PoeShowSettingsCommand) Run(args *GlobalOptions) error {
 		return err
 	}
 	if checkIsLoginRequired(confPage) {
-		return errors.New("no content. please, (re-)login first")
+		return netgear.NewAuthError("no content. please, (re-)login first", nil)
 	}
 	var settings []PoePortSetting
 	settings, err = findPoePortConfInHtml(args.model, strings.NewReader(confPage))
@@ -56,7 +56,7 @@ func (poe *PoeShowSettingsCommand) Run(args *GlobalOptions) error {
 }
 
 func prettyPrintPoePortSettings(model NetgearModel, format OutputFormat, settings []PoePortSetting) {
-	var header = []string{"Port ID", "Port Name", "Port Power", "Mode", "Priority", "Limit Type", "Limit (W)", "Type", "Longer Detection Time"}
+	var header = []string{"Port ID", "Port Name", "Port Power", "Mode", "Priority", "Power Limit Type", "Power Limit (W)", "Detection Type", "Longer Detection Time"}
 	var content [][]string
 	for _, setting := range settings {
 		var row []string
@@ -95,12 +95,15 @@ func prettyPrintPoePortSettings(model NetgearModel, format OutputFormat, setting
 	case MarkdownFormat:
 		printMarkdownTable(header, content)
 	case JsonFormat:
-		printJsonDataTable("poe_settings", header, content)
+		printJsonDataTable("poe_settings", poeSettingsJsonKeys, content)
 	default:
 		panic("not implemented format: " + format)
 	}
 }
 
+// poeSettingsJsonKeys name the JSON fields of the settings columns, like the json tags of netgear.POEPortSettings
+var poeSettingsJsonKeys = []string{"port_id", "port_name", "enabled", "mode", "priority", "power_limit_type", "power_limit_w", "detection_type", "longer_detection_time"}
+
 func asTextPortPower(portPwr bool) string {
 	if portPwr {
 		return "enabled"
@@ -109,15 +112,17 @@ func asTextPortPower(portPwr bool) string {
 }
 
 func requestPoePortConfigPage(args *GlobalOptions, host string) (string, error) {
-	if isModel30x(args.model) {
-		url := fmt.Sprintf("http://%s/PoEPortConfig.cgi", host)
-		return requestPage(args, host, url)
+	return requestPage(args, host, poeConfigUrl(args.model, host))
+}
+
+func poeConfigUrl(model NetgearModel, host string) string {
+	if isModel30x(model) {
+		return fmt.Sprintf("http://%s/PoEPortConfig.cgi", host)
 	}
-	if isModel316(args.model) {
-		url := fmt.Sprintf("http://%s/iss/specific/poePortConf.html", host)
-		return requestPage(args, host, url)
+	if isModel316(model) {
+		return fmt.Sprintf("http://%s/iss/specific/poePortConf.html", host)
 	}
-	panic(fmt.Sprintf("model '%s' not supported", args.model))
+	panic(fmt.Sprintf("model '%s' not supported", model))
 }
 
 func findPoePortConfInHtml(model NetgearModel, reader io.Reader) ([]PoePortSetting, error) {
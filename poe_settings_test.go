@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -150,3 +151,27 @@ func TestPrettyPrintJsonSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestPrettyPrintSettingsIncludesDetectionAndPowerLimit(t *testing.T) {
+	settings := []PoePortSetting{{PortIndex: 1, PortName: "camera", PortPwr: true, PwrMode: "802.3at", PortPrio: "Low",
+		LimitType: "User", PwrLimit: "30.0", DetecType: "IEEE802", LongerDetect: "Disable"}}
+
+	markdown := captureOutput(func() {
+		prettyPrintPoePortSettings(GS316EP, MarkdownFormat, settings)
+	})
+
+	for _, column := range []string{"Detection Type", "Power Limit Type", "Power Limit (W)"} {
+		then.AssertThat(t, strings.Contains(markdown, column), is.True())
+	}
+
+	output := captureOutput(func() {
+		prettyPrintPoePortSettings(GS316EP, JsonFormat, settings)
+	})
+
+	var parsed map[string][]map[string]string
+	then.AssertThat(t, json.Unmarshal([]byte(output), &parsed), is.Nil())
+	then.AssertThat(t, parsed["poe_settings"], has.Length[map[string]string](1))
+	then.AssertThat(t, parsed["poe_settings"][0]["detection_type"], is.EqualTo("IEEE802"))
+	then.AssertThat(t, parsed["poe_settings"][0]["power_limit_type"], is.EqualTo("User"))
+	then.AssertThat(t, parsed["poe_settings"][0]["power_limit_w"], is.EqualTo("30.0"))
+}
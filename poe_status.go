@@ -1,10 +1,16 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/PuerkitoBio/goquery"
 	"io"
+	"math"
+	"ntgrrc/pkg/netgear"
+	"os"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -19,6 +25,28 @@ type PoePortStatus struct {
 	CurrentInMilliAmps   int32
 	PowerInWatt          float32
 	TemperatureInCelsius int32
+	// UnparsedReadings names the readings, e.g. "power_w", which the switch reported as text like "N/A";
+	// their values are 0, but not measured
+	UnparsedReadings []string
+}
+
+// IsReadingValid reports whether the reading, named like in --fields, was parsed from a number
+func (status PoePortStatus) IsReadingValid(field string) bool {
+	return !slices.Contains(status.UnparsedReadings, field)
+}
+
+// parseReadings sets the measured values, noting the ones, which aren't numbers
+func (status *PoePortStatus) parseReadings(voltage string, current string, power string, temperature string) {
+	var ok [4]bool
+	status.VoltageInVolt, ok[0] = parseInt32OK(voltage)
+	status.CurrentInMilliAmps, ok[1] = parseInt32OK(current)
+	status.PowerInWatt, ok[2] = parseFloat32OK(power)
+	status.TemperatureInCelsius, ok[3] = parseInt32OK(temperature)
+	for i, field := range []string{"voltage_v", "current_ma", "power_w", "temperature_c"} {
+		if !ok[i] {
+			status.UnparsedReadings = append(status.UnparsedReadings, field)
+		}
+	}
 }
 
 type PoeCommand struct {
@@ -26,22 +54,148 @@ type PoeCommand struct {
 	PoeShowSettingsCommand PoeShowSettingsCommand `cmd:"" name:"settings" help:"show current PoE settings for all ports"`
 	PoeSetPowerCommand     PoeSetConfigCommand    `cmd:"" name:"set" help:"set new PoE settings per each PORT number"`
 	PoeCyclePowerCommand   PoeCyclePowerCommand   `cmd:"" name:"cycle" help:"power cycle one or more PoE ports"`
+	PoeCycleAllCommand     PoeCycleAllCommand     `cmd:"" name:"cycle-all" help:"power cycle all PoE ports delivering power, e.g. to reboot all cameras"`
+	PoePowerUpCommand      PoePowerUpCommand      `cmd:"" name:"power-up" help:"show or set the PoE power-up mode and delay per port"`
+	PoeAvgPowerCommand     PoeAvgPowerCommand     `cmd:"" name:"avg-power" help:"show the average power of a PoE port over several readings, e.g. of a camera with a noisy consumption"`
+	PoeBudgetCommand       PoeBudgetCommand       `cmd:"" name:"budget" help:"show the PoE power budget and its usage, optionally alert when over a threshold"`
+	PoeClearFaultCommand   PoeClearFaultCommand   `cmd:"" name:"clear-fault" help:"reset PoE ports, which latched off after a fault like an overload"`
+	PoePowerMgmtCommand    PoePowerMgmtCommand    `cmd:"" name:"power-management" help:"show or set how the PoE power budget is allocated to the ports (GS316 only)"`
 }
 
 type PoeStatusCommand struct {
-	Address string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Address     string   `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Fields      []string `optional:"" help:"comma separated fields to show, e.g. port_id,power_w" name:"fields"`
+	VerifyPower bool     `optional:"" help:"warn when the reported power deviates from voltage × current, which hints at a parse error" name:"verify-power"`
+	OnlyActive  bool     `optional:"" help:"show only ports delivering power" name:"only-active"`
+	SortBy      string   `optional:"" help:"sort the ports by [port, power, name], power descending" enum:"port,power,name" default:"port" name:"sort-by"`
+	JsonStable  bool     `optional:"" help:"print versioned JSON with snake_case keys and typed values, a stable contract for scripts" name:"json-stable"`
+	Prometheus  bool     `optional:"" help:"print metrics in the Prometheus text format, e.g. for node_exporter's textfile collector" name:"prometheus"`
+	OutputFile  string   `optional:"" help:"write the --prometheus metrics into this file instead of printing them, replacing it atomically" name:"output-file" type:"path"`
 }
 
+// poeStatusJsonVersion is incremented on any incompatible change of the --json-stable output
+const poeStatusJsonVersion = 1
+
+// PoeStatusStableJson is the --json-stable output, its keys are the json tags of netgear.POEPortStatus
+type PoeStatusStableJson struct {
+	Version   int                    `json:"version"`
+	PoeStatus []PoeStatusStableEntry `json:"poe_status"`
+}
+
+// PoeStatusStableEntry is a port of the --json-stable output, keyed like netgear.POEPortStatus without
+// its temperature_status, which the status pages don't report. Readings, which the switch didn't report
+// as numbers, are null instead of a misleading 0, rather than listed in unparsed_readings.
+type PoeStatusStableEntry struct {
+	PortID       int              `json:"port_id"`
+	PortName     string           `json:"port_name"`
+	Status       string           `json:"status"`
+	PowerClass   string           `json:"power_class"`
+	VoltageV     *float64         `json:"voltage_v"`
+	CurrentMA    *float64         `json:"current_ma"`
+	PowerW       *float64         `json:"power_w"`
+	TemperatureC *float64         `json:"temperature_c"`
+	ErrorStatus  string           `json:"error_status"`
+	Fault        netgear.POEFault `json:"fault"`
+	FaultReason  string           `json:"fault_reason"`
+}
+
+// poeStatusColumns are the columns of the status table, their fields are named like the json tags of netgear.POEPortStatus
+var poeStatusColumns = []tableColumn{
+	{"port_id", "Port ID"},
+	{"port_name", "Port Name"},
+	{"status", "Status"},
+	{"power_class", "PortPwr class"},
+	{"voltage_v", "Voltage (V)"},
+	{"current_ma", "Current (mA)"},
+	{"power_w", "PortPwr (W)"},
+	{"temperature_c", "Temp. (°C)"},
+	{"error_status", "Error status"},
+	{"fault_reason", "Fault reason"},
+}
+
+// poeStatusFields name the status columns for --fields
+var poeStatusFields = fieldsOf(poeStatusColumns)
+
 func (poe *PoeStatusCommand) Run(args *GlobalOptions) error {
+	if err := validateFields(poeStatusFields, poe.Fields); err != nil {
+		return err
+	}
+	if poe.JsonStable && len(poe.Fields) > 0 {
+		return errors.New("--fields can't be combined with --json-stable, which always prints all fields")
+	}
+	if poe.Prometheus && (poe.JsonStable || len(poe.Fields) > 0) {
+		return errors.New("--prometheus can't be combined with --json-stable or --fields")
+	}
+	if poe.OutputFile != "" && !poe.Prometheus {
+		return errors.New("--output-file requires --prometheus")
+	}
 	statuses, err := requestPoeStatus(args, poe.Address)
 	if err != nil {
 		return err
 	}
-	prettyPrintPoePortStatus(args.OutputFormat, statuses)
+	if poe.OnlyActive {
+		statuses = filter(statuses, isPoePortDeliveringPower)
+	}
+	sortPoePortStatus(statuses, poe.SortBy)
+	if poe.Prometheus {
+		err = printPoePortStatusPrometheus(statuses, poe.OutputFile)
+		if err != nil {
+			return err
+		}
+	} else if poe.JsonStable {
+		err = printPoePortStatusStableJson(statuses)
+		if err != nil {
+			return err
+		}
+	} else {
+		prettyPrintPoePortStatus(args.OutputFormat, statuses, poe.Fields...)
+	}
+	if poe.VerifyPower {
+		// on stderr, so the output stays parsable
+		for _, warning := range verifyPoePower(statuses) {
+			fmt.Fprintln(os.Stderr, colorize(os.Stderr, ansiYellow, "WARN:")+" "+warning)
+		}
+	}
 	return nil
 
 }
 
+func isPoePortDeliveringPower(status PoePortStatus) bool {
+	return strings.EqualFold(strings.TrimSpace(status.PoePortStatus), "Delivering Power")
+}
+
+// sortPoePortStatus sorts by port id, by name or by power, the highest consumption first
+func sortPoePortStatus(statuses []PoePortStatus, sortBy string) {
+	switch sortBy {
+	case "power":
+		sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].PowerInWatt > statuses[j].PowerInWatt })
+	case "name":
+		sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].PortName < statuses[j].PortName })
+	default:
+		sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].PortIndex < statuses[j].PortIndex })
+	}
+}
+
+// verifyPoePower returns a warning for each port, which reported power deviates from voltage × current
+func verifyPoePower(statuses []PoePortStatus) []string {
+	var warnings []string
+	for _, status := range statuses {
+		if len(status.UnparsedReadings) > 0 {
+			continue
+		}
+		reading := netgear.POEPortStatus{
+			VoltageV:  float64(status.VoltageInVolt),
+			CurrentMA: float64(status.CurrentInMilliAmps),
+			PowerW:    float64(status.PowerInWatt),
+		}
+		if !reading.IsPowerConsistent(netgear.DefaultPowerTolerance) {
+			warnings = append(warnings, fmt.Sprintf("port %d reports %.2f W, but %d V × %d mA are %.2f W",
+				status.PortIndex, status.PowerInWatt, status.VoltageInVolt, status.CurrentInMilliAmps, reading.PowerFromVI()))
+		}
+	}
+	return warnings
+}
+
 func requestPoeStatus(args *GlobalOptions, address string) ([]PoePortStatus, error) {
 	var result []PoePortStatus
 	statusPage, err := requestPoePortStatusPage(args, address)
@@ -49,7 +203,7 @@ func requestPoeStatus(args *GlobalOptions, address string) ([]PoePortStatus, err
 		return result, err
 	}
 	if checkIsLoginRequired(statusPage) {
-		return result, errors.New("no content. please, (re-)login first")
+		return result, netgear.NewAuthError("no content. please, (re-)login first", nil)
 	}
 	result, err = findPortStatusInHtml(args.model, strings.NewReader(statusPage))
 	if err != nil {
@@ -58,8 +212,8 @@ func requestPoeStatus(args *GlobalOptions, address string) ([]PoePortStatus, err
 	return result, nil
 }
 
-func prettyPrintPoePortStatus(format OutputFormat, statuses []PoePortStatus) {
-	var header = []string{"Port ID", "Port Name", "Status", "PortPwr class", "Voltage (V)", "Current (mA)", "PortPwr (W)", "Temp. (°C)", "Error status"}
+func prettyPrintPoePortStatus(format OutputFormat, statuses []PoePortStatus, fields ...string) {
+	var header = headerOf(poeStatusColumns)
 	var content [][]string
 	for _, status := range statuses {
 		var row []string
@@ -67,13 +221,15 @@ func prettyPrintPoePortStatus(format OutputFormat, statuses []PoePortStatus) {
 		row = append(row, status.PortName)
 		row = append(row, status.PoePortStatus)
 		row = append(row, status.PoePowerClass)
-		row = append(row, fmt.Sprintf("%d", status.VoltageInVolt))
-		row = append(row, fmt.Sprintf("%d", status.CurrentInMilliAmps))
-		row = append(row, fmt.Sprintf("%.2f", status.PowerInWatt))
-		row = append(row, fmt.Sprintf("%d", status.TemperatureInCelsius))
+		row = append(row, formatPoeReading(status, "voltage_v", fmt.Sprintf("%d", status.VoltageInVolt)))
+		row = append(row, formatPoeReading(status, "current_ma", fmt.Sprintf("%d", status.CurrentInMilliAmps)))
+		row = append(row, formatPoeReading(status, "power_w", fmt.Sprintf("%.2f", status.PowerInWatt)))
+		row = append(row, formatPoeReading(status, "temperature_c", fmt.Sprintf("%d", status.TemperatureInCelsius)))
 		row = append(row, status.ErrorStatus)
+		row = append(row, netgear.ParsePOEFaultReason(status.ErrorStatus))
 		content = append(content, row)
 	}
+	header, content = selectColumns(poeStatusFields, header, content, fields)
 	switch format {
 	case MarkdownFormat:
 		printMarkdownTable(header, content)
@@ -84,18 +240,65 @@ func prettyPrintPoePortStatus(format OutputFormat, statuses []PoePortStatus) {
 	}
 }
 
+// formatPoeReading shows unparsed readings as "n/a" instead of a misleading 0
+func formatPoeReading(status PoePortStatus, field string, value string) string {
+	if !status.IsReadingValid(field) {
+		return "n/a"
+	}
+	return value
+}
+
+// stableReading returns the reading for the --json-stable output, nil if it wasn't parsed
+func stableReading(status PoePortStatus, field string, value float64) *float64 {
+	if !status.IsReadingValid(field) {
+		return nil
+	}
+	return &value
+}
+
+// printPoePortStatusStableJson prints the statuses in the documented --json-stable format
+func printPoePortStatusStableJson(statuses []PoePortStatus) error {
+	result := PoeStatusStableJson{
+		Version:   poeStatusJsonVersion,
+		PoeStatus: make([]PoeStatusStableEntry, 0, len(statuses)),
+	}
+	for _, status := range statuses {
+		result.PoeStatus = append(result.PoeStatus, PoeStatusStableEntry{
+			PortID:       int(status.PortIndex),
+			PortName:     status.PortName,
+			Status:       status.PoePortStatus,
+			PowerClass:   status.PoePowerClass,
+			VoltageV:     stableReading(status, "voltage_v", float64(status.VoltageInVolt)),
+			CurrentMA:    stableReading(status, "current_ma", float64(status.CurrentInMilliAmps)),
+			PowerW:       stableReading(status, "power_w", math.Round(float64(status.PowerInWatt)*100)/100), // without float32 noise
+			TemperatureC: stableReading(status, "temperature_c", float64(status.TemperatureInCelsius)),
+			ErrorStatus:  status.ErrorStatus,
+			Fault:        netgear.ParsePOEFault(status.ErrorStatus),
+			FaultReason:  netgear.ParsePOEFaultReason(status.ErrorStatus),
+		})
+	}
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(jsonData))
+	return nil
+}
+
 func requestPoePortStatusPage(args *GlobalOptions, host string) (string, error) {
 	model, _, err := readTokenAndModel2GlobalOptions(args, host)
 	if err != nil {
 		return "", err
 	}
+	return requestPage(args, host, poeStatusUrl(model, host))
+}
+
+func poeStatusUrl(model NetgearModel, host string) string {
 	if isModel30x(model) {
-		url := fmt.Sprintf("http://%s/getPoePortStatus.cgi", host)
-		return requestPage(args, host, url)
+		return fmt.Sprintf("http://%s/getPoePortStatus.cgi", host)
 	}
 	if isModel316(model) {
-		url := fmt.Sprintf("http://%s/iss/specific/poePortStatus.html?GetData=TRUE", host)
-		return requestPage(args, host, url)
+		return fmt.Sprintf("http://%s/iss/specific/poePortStatus.html?GetData=TRUE", host)
 	}
 	panic("model not supported")
 }
@@ -131,20 +334,22 @@ func findPortStatusInGs30xEPxHtml(reader io.Reader) ([]PoePortStatus, error) {
 		powerClassText := s.Find("span.poe-portPwr-width span").Text()
 		stat.PoePowerClass = getPowerClassFromI18nString(powerClassText)
 
+		var voltage, current, power, temperature string
 		s.Find("div.poe_port_status div div span").Each(func(i int, s *goquery.Selection) {
 			switch i {
 			case 1:
-				stat.VoltageInVolt = parseInt32(s.Text())
+				voltage = s.Text()
 			case 3:
-				stat.CurrentInMilliAmps = parseInt32(s.Text())
+				current = s.Text()
 			case 5:
-				stat.PowerInWatt = parseFloat32(s.Text())
+				power = s.Text()
 			case 7:
-				stat.TemperatureInCelsius = parseInt32(s.Text())
+				temperature = s.Text()
 			case 9:
 				stat.ErrorStatus = strings.TrimSpace(s.Text())
 			}
 		})
+		stat.parseReadings(voltage, current, power, temperature)
 		statuses = append(statuses, stat)
 	})
 
@@ -164,10 +369,11 @@ func findPortStatusInGs316EPxHtml(reader io.Reader) ([]PoePortStatus, error) {
 		stat.PortIndex, stat.PortName = parsePortIdAndName(s.Find("span.port-number").Text())
 		stat.PoePortStatus = s.Find("span.Status-text").Text()
 		stat.PoePowerClass = getPowerClassFromI18nString(s.Find("span.Class-text").Text())
-		stat.VoltageInVolt = parseInt32(s.Find("p.OutputVoltage-text").Text())
-		stat.CurrentInMilliAmps = parseInt32(s.Find("p.OutputCurrent-text").Text())
-		stat.PowerInWatt = parseFloat32(s.Find("p.OutputPower-text").Text())
-		stat.TemperatureInCelsius = parseInt32(s.Find("p.Temperature-text").Text())
+		stat.parseReadings(
+			s.Find("p.OutputVoltage-text").Text(),
+			s.Find("p.OutputCurrent-text").Text(),
+			s.Find("p.OutputPower-text").Text(),
+			s.Find("p.Temperature-text").Text())
 		stat.ErrorStatus = s.Find("p.Fault-Status-text").Text()
 		statuses = append(statuses, stat)
 	})
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// poeStatusMetric is one gauge of the --prometheus output
+type poeStatusMetric struct {
+	name  string
+	help  string
+	field string // of --fields, to skip unparsed readings
+	value func(status PoePortStatus) float64
+}
+
+// poeStatusMetrics use the Prometheus base units, so the current is in amperes
+var poeStatusMetrics = []poeStatusMetric{
+	{"ntgrrc_poe_power_watts", "Power delivered by the PoE port in watts.", "power_w", func(status PoePortStatus) float64 {
+		return math.Round(float64(status.PowerInWatt)*100) / 100
+	}},
+	{"ntgrrc_poe_voltage_volts", "Voltage of the PoE port in volts.", "voltage_v", func(status PoePortStatus) float64 {
+		return float64(status.VoltageInVolt)
+	}},
+	{"ntgrrc_poe_current_amperes", "Current of the PoE port in amperes.", "current_ma", func(status PoePortStatus) float64 {
+		return float64(status.CurrentInMilliAmps) / 1000
+	}},
+	{"ntgrrc_poe_temperature_celsius", "Temperature of the PoE port in degrees celsius.", "temperature_c", func(status PoePortStatus) float64 {
+		return float64(status.TemperatureInCelsius)
+	}},
+}
+
+// printPoePortStatusPrometheus prints the metrics, or writes them into outputFile, if given
+func printPoePortStatusPrometheus(statuses []PoePortStatus, outputFile string) error {
+	if outputFile == "" {
+		return writePoePortStatusPrometheus(os.Stdout, statuses)
+	}
+	var buffer bytes.Buffer
+	if err := writePoePortStatusPrometheus(&buffer, statuses); err != nil {
+		return err
+	}
+	return writeFileAtomically(outputFile, buffer.Bytes())
+}
+
+// writePoePortStatusPrometheus writes the statuses in the Prometheus text exposition format
+func writePoePortStatusPrometheus(w io.Writer, statuses []PoePortStatus) error {
+	for _, metric := range poeStatusMetrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", metric.name, metric.help, metric.name); err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			if !status.IsReadingValid(metric.field) {
+				// a missing sample, rather than a wrong 0
+				continue
+			}
+			value := strconv.FormatFloat(metric.value(status), 'f', -1, 64)
+			if _, err := fmt.Fprintf(w, "%s{port=\"%d\"} %s\n", metric.name, status.PortIndex, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeFileAtomically replaces the file via a rename, so a collector never reads a partially written file
+func writeFileAtomically(filename string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
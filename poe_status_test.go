@@ -1,12 +1,19 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
 	"github.com/corbym/gocrest/has"
 	"github.com/corbym/gocrest/is"
 	"github.com/corbym/gocrest/then"
+	"ntgrrc/pkg/netgear"
 )
 
 func TestFindPortStatusInHtml(t *testing.T) {
@@ -157,3 +164,186 @@ func TestPrettyPrintJsonStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestPrettyPrintJsonStatusWithFields(t *testing.T) {
+	statuses, err := findPortStatusInHtml("GS305EP", strings.NewReader(loadTestFile("GS305EP", "getPoePortStatus.cgi.html")))
+	then.AssertThat(t, err, is.Nil())
+
+	output := captureOutput(func() {
+		prettyPrintPoePortStatus(JsonFormat, statuses, "port_id", "power_w")
+	})
+
+	var result map[string][]map[string]string
+	err = json.Unmarshal([]byte(output), &result)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, result["poe_status"], has.Length[map[string]string](4))
+	then.AssertThat(t, result["poe_status"][0], is.EqualTo(map[string]string{"Port ID": "1", "PortPwr (W)": "4.40"}))
+}
+
+func TestPoeStatusWithUnknownField(t *testing.T) {
+	err := validateFields(poeStatusFields, []string{"port_id", "watts"})
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+	then.AssertThat(t, strings.Contains(err.Error(), "unknown field 'watts'"), is.True())
+	then.AssertThat(t, strings.Contains(err.Error(), "power_w"), is.True())
+}
+
+func TestVerifyPoePower(t *testing.T) {
+	statuses := []PoePortStatus{
+		{PortIndex: 1, VoltageInVolt: 53, CurrentInMilliAmps: 82, PowerInWatt: 4.4},
+		{PortIndex: 2, VoltageInVolt: 0, CurrentInMilliAmps: 0, PowerInWatt: 0},
+		{PortIndex: 3, VoltageInVolt: 53, CurrentInMilliAmps: 82, PowerInWatt: 53},
+	}
+
+	warnings := verifyPoePower(statuses)
+
+	then.AssertThat(t, warnings, has.Length[string](1))
+	then.AssertThat(t, warnings[0], is.EqualTo("port 3 reports 53.00 W, but 53 V × 82 mA are 4.35 W"))
+}
+
+func TestPoeStatusOnlyActiveAndSortByPower(t *testing.T) {
+	statuses, err := findPortStatusInHtml(GS308EPP, strings.NewReader(loadTestFile("GS308EPP", "getPoePortStatus.cgi.html")))
+	then.AssertThat(t, err, is.Nil())
+
+	active := filter(statuses, isPoePortDeliveringPower)
+	sortPoePortStatus(active, "power")
+
+	then.AssertThat(t, active, has.Length[PoePortStatus](2))
+	then.AssertThat(t, active[0].PortIndex, is.EqualTo(int8(1)))
+	then.AssertThat(t, active[0].PowerInWatt, is.EqualTo(float32(5.8)))
+	then.AssertThat(t, active[1].PortIndex, is.EqualTo(int8(3)))
+	for _, status := range active {
+		then.AssertThat(t, status.PoePortStatus, is.EqualTo("Delivering Power"))
+	}
+}
+
+func TestSortPoePortStatus(t *testing.T) {
+	statuses := []PoePortStatus{
+		{PortIndex: 1, PortName: "camera", PowerInWatt: 2.5},
+		{PortIndex: 2, PortName: "ap", PowerInWatt: 7.1},
+		{PortIndex: 3, PortName: "bridge", PowerInWatt: 0},
+	}
+
+	sortPoePortStatus(statuses, "power")
+	then.AssertThat(t, []int8{statuses[0].PortIndex, statuses[1].PortIndex, statuses[2].PortIndex}, is.EqualTo([]int8{2, 1, 3}))
+
+	sortPoePortStatus(statuses, "name")
+	then.AssertThat(t, []int8{statuses[0].PortIndex, statuses[1].PortIndex, statuses[2].PortIndex}, is.EqualTo([]int8{2, 3, 1}))
+
+	sortPoePortStatus(statuses, "port")
+	then.AssertThat(t, []int8{statuses[0].PortIndex, statuses[1].PortIndex, statuses[2].PortIndex}, is.EqualTo([]int8{1, 2, 3}))
+}
+
+func TestPoeStatusStableJsonKeysMatchLibraryStruct(t *testing.T) {
+	statuses, err := findPortStatusInHtml("GS305EP", strings.NewReader(loadTestFile("GS305EP", "getPoePortStatus.cgi.html")))
+	then.AssertThat(t, err, is.Nil())
+
+	output := captureOutput(func() {
+		err = printPoePortStatusStableJson(statuses)
+	})
+
+	then.AssertThat(t, err, is.Nil())
+	var result map[string]json.RawMessage
+	then.AssertThat(t, json.Unmarshal([]byte(output), &result), is.Nil())
+	then.AssertThat(t, string(result["version"]), is.EqualTo("1"))
+	var ports []map[string]interface{}
+	then.AssertThat(t, json.Unmarshal(result["poe_status"], &ports), is.Nil())
+	then.AssertThat(t, ports, has.Length[map[string]interface{}](4))
+
+	var expectedKeys []string
+	structType := reflect.TypeOf(netgear.POEPortStatus{})
+	for i := 0; i < structType.NumField(); i++ {
+		// the status pages don't report a temperature status, unparsed readings are null instead
+		if key := structType.Field(i).Tag.Get("json"); key != "temperature_status" && !strings.HasPrefix(key, "unparsed_readings") {
+			expectedKeys = append(expectedKeys, key)
+		}
+	}
+	var keys []string
+	for key := range ports[0] {
+		keys = append(keys, key)
+	}
+	sort.Strings(expectedKeys)
+	sort.Strings(keys)
+	then.AssertThat(t, keys, is.EqualTo(expectedKeys))
+	then.AssertThat(t, ports[0]["port_id"], is.EqualTo[interface{}](float64(1)))
+	then.AssertThat(t, ports[0]["power_w"], is.EqualTo[interface{}](4.4))
+}
+
+func TestPoeStatusPrometheusOutput(t *testing.T) {
+	statuses, err := findPortStatusInHtml("GS305EP", strings.NewReader(loadTestFile("GS305EP", "getPoePortStatus.cgi.html")))
+	then.AssertThat(t, err, is.Nil())
+
+	var buffer bytes.Buffer
+	err = writePoePortStatusPrometheus(&buffer, statuses)
+
+	then.AssertThat(t, err, is.Nil())
+	output := buffer.String()
+	then.AssertThat(t, output, is.StringContaining("# TYPE ntgrrc_poe_power_watts gauge\n"))
+	then.AssertThat(t, output, is.StringContaining("ntgrrc_poe_power_watts{port=\"1\"} 4.4\n"))
+	then.AssertThat(t, output, is.StringContaining("ntgrrc_poe_voltage_volts{port=\"1\"} 53\n"))
+	then.AssertThat(t, output, is.StringContaining("ntgrrc_poe_current_amperes{port=\"1\"} 0.082\n"))
+	then.AssertThat(t, output, is.StringContaining("ntgrrc_poe_temperature_celsius{port=\"1\"} 30\n"))
+}
+
+func TestPoeStatusPrometheusWritesOutputFile(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "poe.prom")
+	statuses := []PoePortStatus{{PortIndex: 2, PowerInWatt: 1.5}}
+
+	err := printPoePortStatusPrometheus(statuses, outputFile)
+
+	then.AssertThat(t, err, is.Nil())
+	content, err := os.ReadFile(outputFile)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, string(content), is.StringContaining("ntgrrc_poe_power_watts{port=\"2\"} 1.5\n"))
+}
+
+func TestPoeStatusMarksUnparsedReadings(t *testing.T) {
+	html := `<div class="port-wrap"><span class="port-number">1</span>
+		<p class="OutputVoltage-text">N/A</p><p class="OutputCurrent-text">0</p>
+		<p class="OutputPower-text"></p><p class="Temperature-text">-5</p></div>`
+
+	statuses, err := findPortStatusInGs316EPxHtml(strings.NewReader(html))
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, statuses[0].UnparsedReadings, is.EqualTo([]string{"voltage_v", "power_w"}))
+	then.AssertThat(t, statuses[0].IsReadingValid("current_ma"), is.True())
+	then.AssertThat(t, statuses[0].TemperatureInCelsius, is.EqualTo(int32(-5)))
+
+	var buffer bytes.Buffer
+	then.AssertThat(t, writePoePortStatusPrometheus(&buffer, statuses), is.Nil())
+	then.AssertThat(t, strings.Contains(buffer.String(), "ntgrrc_poe_power_watts{"), is.False())
+	then.AssertThat(t, buffer.String(), is.StringContaining("ntgrrc_poe_current_amperes{port=\"1\"} 0\n"))
+}
+
+func TestPoeStatusStableJsonHasNullForUnparsedReadings(t *testing.T) {
+	statuses := []PoePortStatus{{PortIndex: 1, CurrentInMilliAmps: 0, PowerInWatt: 0, UnparsedReadings: []string{"voltage_v", "power_w"}}}
+
+	output := captureOutput(func() {
+		then.AssertThat(t, printPoePortStatusStableJson(statuses), is.Nil())
+	})
+
+	var result PoeStatusStableJson
+	then.AssertThat(t, json.Unmarshal([]byte(output), &result), is.Nil())
+	then.AssertThat(t, result.PoeStatus[0].VoltageV == nil, is.True())
+	then.AssertThat(t, result.PoeStatus[0].PowerW == nil, is.True())
+	then.AssertThat(t, *result.PoeStatus[0].CurrentMA, is.EqualTo(0.0))
+	then.AssertThat(t, output, is.StringContaining(`"power_w": null`))
+}
+
+func TestPoeStatusShowsFaultReason(t *testing.T) {
+	html := loadTestFile("GS316EP", "poePortStatus_GetData_true.html")
+	html = strings.Replace(html, "No Error", "Power Denied (budget exceeded)", 1)
+	html = strings.Replace(html, "No Error", "Overload", 1)
+	statuses, err := findPortStatusInGs316EPxHtml(strings.NewReader(html))
+	then.AssertThat(t, err, is.Nil())
+
+	output := captureOutput(func() {
+		prettyPrintPoePortStatus(JsonFormat, statuses, "port_id", "error_status", "fault_reason")
+	})
+
+	var result map[string][]map[string]string
+	then.AssertThat(t, json.Unmarshal([]byte(output), &result), is.Nil())
+	then.AssertThat(t, result["poe_status"][0]["Fault reason"], is.EqualTo("the switch's POE power budget is exceeded"))
+	then.AssertThat(t, result["poe_status"][1]["Fault reason"], is.EqualTo("the powered device draws more than the port's power limit"))
+	then.AssertThat(t, result["poe_status"][2]["Fault reason"], is.EqualTo(""))
+}
@@ -74,6 +74,11 @@ var detecTypeMap = map[string]string{
 	"3": "4pt 802.3af + Legacy",
 }
 
+var powerUpModeMap = map[string]string{
+	"0": "immediate",
+	"1": "delayed",
+}
+
 var longerDetectMap = map[string]string{
 	"0": "Get Value Fault",
 	"2": "disable",
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+type PortDescribeCommand struct {
+	Address     string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Port        int    `required:"" help:"port number (starting with 1) to describe" short:"p"`
+	Description string `arg:"" help:"the port's description, set as its name, as the firmware has no separate description"`
+}
+
+func (describe *PortDescribeCommand) Run(args *GlobalOptions) error {
+	client, err := newLibraryClient(args, describe.Address)
+	if err != nil {
+		return err
+	}
+	err = client.Ports().SetPortDescription(context.Background(), describe.Port, describe.Description)
+	if err != nil {
+		return err
+	}
+	if !args.Quiet {
+		fmt.Printf("described port %d as '%s'\n", describe.Port, describe.Description)
+	}
+	return nil
+}
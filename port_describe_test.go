@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestPortDescribe(t *testing.T) {
+	tests := []struct {
+		name          string
+		page          string
+		expectedField string
+	}{
+		{"name only", `<table><tr><th>Port</th><th>Name</th></tr><tr><td>5</td><td>ap</td></tr></table>`, "PORT_NAME"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var posted map[string][]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/iss/specific/interface.html" {
+					return
+				}
+				if r.Method == http.MethodPost {
+					_ = r.ParseForm()
+					posted = r.PostForm
+					return
+				}
+				_, _ = w.Write([]byte(test.page))
+			}))
+			defer server.Close()
+			host := strings.TrimPrefix(server.URL, "http://")
+			tokenDir := t.TempDir()
+			writeTestToken(t, tokenDir, host, "token", GS316EP)
+
+			var exitCode int
+			output := captureOutput(func() {
+				exitCode = run([]string{"--token-dir", tokenDir, "port", "describe", "--address", host, "--port", "5", "lobby AP"})
+			})
+
+			then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+			then.AssertThat(t, posted[test.expectedField], is.EqualTo([]string{"lobby AP"}))
+			then.AssertThat(t, output, is.StringContaining("described port 5 as 'lobby AP'"))
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type PortFlowControlCommand struct {
+	Address string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	All     bool   `optional:"" help:"change flow control on every port of the switch"`
+	State   string `arg:"" help:"whether flow control is turned [on, off]" enum:"on,off"`
+}
+
+func (flowControl *PortFlowControlCommand) Run(args *GlobalOptions) error {
+	if !flowControl.All {
+		return errors.New("--all is required, use 'port set --flow-control' to change single ports")
+	}
+	client, err := newLibraryClient(args, flowControl.Address)
+	if err != nil {
+		return err
+	}
+	err = client.Ports().SetAllFlowControl(context.Background(), flowControl.State == "on")
+	if err != nil {
+		return err
+	}
+	if !args.Quiet {
+		fmt.Printf("turned flow control %s on all %d ports\n", flowControl.State, client.GetModel().PortCount())
+	}
+	return nil
+}
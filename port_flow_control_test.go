@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestPortFlowControlAll(t *testing.T) {
+	var mu sync.Mutex
+	flowControl := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/iss/specific/interface.html" {
+			_ = r.ParseForm()
+			mu.Lock()
+			flowControl[r.PostForm.Get("PORT_NO")] = r.PostForm.Get("FLOW_CONTROL")
+			mu.Unlock()
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, "token", GS316EP)
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "port", "flow-control", "--address", host, "--all", "off"})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	then.AssertThat(t, len(flowControl), is.EqualTo(16))
+	for _, value := range flowControl {
+		then.AssertThat(t, value, is.EqualTo("1"))
+	}
+	then.AssertThat(t, output, is.StringContaining("off on all 16 ports"))
+}
+
+func TestPortFlowControlRequiresAll(t *testing.T) {
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"port", "flow-control", "--address", "localhost:1", "on"})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeGeneralError))
+	then.AssertThat(t, output, is.StringContaining("--all is required"))
+}
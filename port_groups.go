@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// portGroupPrefix marks a port group name in a port spec, e.g. @cameras
+const portGroupPrefix = "@"
+
+// parsePortSpec expands port numbers and @group names, each argument may list several separated by commas.
+// Groups are looked up in the port-groups of the switch config. Ports are returned in the given order, without duplicates.
+func parsePortSpec(spec []string, groups map[string][]int) ([]int, error) {
+	var ports []int
+	add := func(port int) {
+		if !slices.Contains(ports, port) {
+			ports = append(ports, port)
+		}
+	}
+	for _, arg := range spec {
+		for _, token := range strings.Split(arg, ",") {
+			token = strings.TrimSpace(token)
+			if token == "" {
+				continue
+			}
+			if name, isGroup := strings.CutPrefix(token, portGroupPrefix); isGroup {
+				group, ok := groups[name]
+				if !ok {
+					return nil, errors.New(fmt.Sprintf("port group '%s' is not defined, add it to the port-groups of the switch in %s", name, switchesFileName))
+				}
+				for _, port := range group {
+					add(port)
+				}
+				continue
+			}
+			port, err := strconv.Atoi(token)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("invalid port '%s', expected a port number or a %sgroup name", token, portGroupPrefix))
+			}
+			add(port)
+		}
+	}
+	return ports, nil
+}
+
+// portsWithSpec appends the ports of the port spec to the ports given with --port, without duplicates.
+// Groups are those of the configured switch; naming one fails, if the default switches file is malformed.
+func portsWithSpec(args *GlobalOptions, ports []int, spec []string) ([]int, error) {
+	if args.switchesErr != nil && namesPortGroup(spec) {
+		return nil, args.switchesErr
+	}
+	expanded, err := parsePortSpec(spec, args.switchConfig.PortGroups)
+	if err != nil {
+		return nil, err
+	}
+	var result []int
+	for _, port := range append(slices.Clone(ports), expanded...) {
+		if !slices.Contains(result, port) {
+			result = append(result, port)
+		}
+	}
+	return result, nil
+}
+
+// namesPortGroup reports whether the port spec names a port group
+func namesPortGroup(spec []string) bool {
+	for _, arg := range spec {
+		for _, token := range strings.Split(arg, ",") {
+			if strings.HasPrefix(strings.TrimSpace(token), portGroupPrefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+var testPortGroups = map[string][]int{
+	"cameras": {1, 2, 5},
+	"uplinks": {8},
+}
+
+func TestParsePortSpecExpandsGroup(t *testing.T) {
+	ports, err := parsePortSpec([]string{"@cameras"}, testPortGroups)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, ports, is.EqualTo([]int{1, 2, 5}))
+}
+
+func TestParsePortSpecUndefinedGroup(t *testing.T) {
+	_, err := parsePortSpec([]string{"@printers"}, testPortGroups)
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+	then.AssertThat(t, err.Error(), is.StringContaining("port group 'printers' is not defined"))
+}
+
+func TestParsePortSpecMixesPortsAndGroups(t *testing.T) {
+	ports, err := parsePortSpec([]string{"3,@cameras", "@uplinks", "2"}, testPortGroups)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, ports, is.EqualTo([]int{3, 1, 2, 5, 8}))
+}
+
+func TestParsePortSpecInvalidPort(t *testing.T) {
+	_, err := parsePortSpec([]string{"cameras"}, testPortGroups)
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"ntgrrc/pkg/netgear"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type PortImportCommand struct {
+	Address string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	File    string `required:"" help:"CSV file with the columns port,name,speed,ingress_limit,egress_limit,flow_control" type:"existingfile"`
+	DryRun  bool   `optional:"" help:"only show the changes, without applying them" name:"dry-run"`
+}
+
+// portImportColumns are the columns of the port import CSV file, in any order
+var portImportColumns = []string{"port", "name", "speed", "ingress_limit", "egress_limit", "flow_control"}
+
+// portImportRow is a row of the port import CSV file, along with its line number for error messages
+type portImportRow struct {
+	line   int
+	update netgear.PortUpdate
+}
+
+func (portImport *PortImportCommand) Run(args *GlobalOptions) error {
+	file, err := os.Open(portImport.File)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	rows, err := readPortImportRows(file)
+	if err != nil {
+		return errors.New(fmt.Sprintf("invalid port import file '%s': %v", portImport.File, err))
+	}
+
+	client, err := newLibraryClient(args, portImport.Address)
+	if err != nil {
+		return err
+	}
+	var problems []string
+	for _, row := range rows {
+		if err := row.update.Validate(client.GetModel()); err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: %v", row.line, err))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(fmt.Sprintf("invalid port import file '%s':\n%s", portImport.File, strings.Join(problems, "\n")))
+	}
+
+	ctx := args.context()
+	settings, err := client.Ports().GetSettings(ctx)
+	if err != nil {
+		return err
+	}
+	updates := changedPortUpdates(rows, settings)
+	if len(updates) > 0 && !portImport.DryRun {
+		if err := client.Ports().UpdatePort(ctx, updates...); err != nil {
+			return err
+		}
+	}
+	prettyPrintSnapshotChanges(args.OutputFormat, &netgear.SnapshotChanges{Ports: updates})
+	return nil
+}
+
+// readPortImportRows parses the CSV, an empty cell keeps the port's current setting.
+// All malformed rows are reported together, each with its line number.
+func readPortImportRows(reader io.Reader) ([]portImportRow, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to read the header: %v", err))
+	}
+	columns := map[string]int{}
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !slices.Contains(portImportColumns, column) {
+			return nil, errors.New(fmt.Sprintf("unknown column '%s', valid columns are %s", column, strings.Join(portImportColumns, ",")))
+		}
+		columns[column] = i
+	}
+	if _, ok := columns["port"]; !ok {
+		return nil, errors.New("the column 'port' is missing")
+	}
+
+	var rows []portImportRow
+	var problems []string
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := csvReader.FieldPos(0)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		update, err := parsePortImportRecord(record, columns)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		rows = append(rows, portImportRow{line: line, update: update})
+	}
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "\n"))
+	}
+	return rows, nil
+}
+
+func parsePortImportRecord(record []string, columns map[string]int) (netgear.PortUpdate, error) {
+	cell := func(column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var update netgear.PortUpdate
+	portID, err := strconv.Atoi(cell("port"))
+	if err != nil {
+		return update, errors.New(fmt.Sprintf("invalid port '%s'", cell("port")))
+	}
+	update.PortID = portID
+	if name := cell("name"); name != "" {
+		update.Name = &name
+	}
+	if speed := cell("speed"); speed != "" {
+		portSpeed := netgear.PortSpeed(speed)
+		update.Speed = &portSpeed
+	}
+	if ingress := cell("ingress_limit"); ingress != "" {
+		update.IngressLimit = &ingress
+	}
+	if egress := cell("egress_limit"); egress != "" {
+		update.EgressLimit = &egress
+	}
+	if flowControl := cell("flow_control"); flowControl != "" {
+		enabled, ok := parseOnOff(flowControl)
+		if !ok {
+			return update, errors.New(fmt.Sprintf("invalid flow control '%s', must be on or off", flowControl))
+		}
+		update.FlowControl = &enabled
+	}
+	return update, nil
+}
+
+// parseOnOff accepts on/off as well as true/false
+func parseOnOff(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "on", "true":
+		return true, true
+	case "off", "false":
+		return false, true
+	}
+	return false, false
+}
+
+// changedPortUpdates drops the settings, which the ports have already, and the rows without a change left
+func changedPortUpdates(rows []portImportRow, settings []netgear.PortSettings) []netgear.PortUpdate {
+	current := make(map[int]netgear.PortSettings, len(settings))
+	for _, setting := range settings {
+		current[setting.PortID] = setting
+	}
+
+	var updates []netgear.PortUpdate
+	for _, row := range rows {
+		update := row.update
+		if setting, ok := current[update.PortID]; ok {
+			if update.Name != nil && *update.Name == setting.PortName {
+				update.Name = nil
+			}
+			if update.Speed != nil && strings.EqualFold(string(*update.Speed), string(setting.Speed)) {
+				update.Speed = nil
+			}
+			if update.IngressLimit != nil && isSameRateLimit(*update.IngressLimit, setting.IngressLimitKbps) {
+				update.IngressLimit = nil
+			}
+			if update.EgressLimit != nil && isSameRateLimit(*update.EgressLimit, setting.EgressLimitKbps) {
+				update.EgressLimit = nil
+			}
+			if update.FlowControl != nil && *update.FlowControl == setting.FlowControl {
+				update.FlowControl = nil
+			}
+		}
+		if update.Name != nil || update.Speed != nil || update.IngressLimit != nil || update.EgressLimit != nil || update.FlowControl != nil {
+			updates = append(updates, update)
+		}
+	}
+	return updates
+}
+
+func isSameRateLimit(limit string, currentKbps int) bool {
+	kbps, err := netgear.ParseRateLimitKbps(limit)
+	return err == nil && kbps == currentKbps
+}
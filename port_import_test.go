@@ -0,0 +1,135 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+// startPortImportServer serves a GS316EP interface page of 3 ports and records the posted port forms
+func startPortImportServer(t *testing.T) (string, func() map[string]map[string][]string) {
+	var mu sync.Mutex
+	posted := map[string]map[string][]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/iss/specific/interface.html" {
+			return
+		}
+		if r.Method == http.MethodPost {
+			_ = r.ParseForm()
+			mu.Lock()
+			posted[r.PostForm.Get("PORT_NO")] = r.PostForm
+			mu.Unlock()
+			return
+		}
+		_, _ = w.Write([]byte(`<table><tr><th>Port</th><th>Name</th><th>Speed</th></tr>` +
+			`<tr><td>1</td><td>ap</td><td>Auto</td></tr>` +
+			`<tr><td>2</td><td>cam</td><td>Auto</td></tr>` +
+			`<tr><td>3</td><td>printer</td><td>Auto</td></tr></table>`))
+	}))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://"), func() map[string]map[string][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return posted
+	}
+}
+
+func writePortImportFile(t *testing.T, content string) string {
+	file := filepath.Join(t.TempDir(), "ports.csv")
+	then.AssertThat(t, os.WriteFile(file, []byte(content), 0644), is.Nil())
+	return file
+}
+
+func TestPortImport(t *testing.T) {
+	host, posted := startPortImportServer(t)
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, "token", GS316EP)
+	file := writePortImportFile(t, "port,name,speed,ingress_limit,egress_limit,flow_control\n"+
+		"1,lobby AP,,,,\n"+
+		"2,camera,100M full,,,\n"+
+		"3,printer,,,,\n")
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "port", "import", "--address", host, "--file", file})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	then.AssertThat(t, len(posted()), is.EqualTo(2))
+	then.AssertThat(t, posted()["1"]["PORT_NAME"], is.EqualTo([]string{"lobby AP"}))
+	then.AssertThat(t, posted()["2"]["PORT_NAME"], is.EqualTo([]string{"camera"}))
+	then.AssertThat(t, posted()["2"]["PORT_CTRL_SPEED"], is.EqualTo([]string{"2"}))
+	then.AssertThat(t, output, is.StringContaining("lobby AP"))
+	then.AssertThat(t, strings.Contains(output, "printer"), is.False())
+}
+
+func TestPortImportDryRun(t *testing.T) {
+	host, posted := startPortImportServer(t)
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, "token", GS316EP)
+	file := writePortImportFile(t, "port,name\n1,lobby AP\n")
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "port", "import", "--address", host, "--file", file, "--dry-run"})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	then.AssertThat(t, len(posted()), is.EqualTo(0))
+	then.AssertThat(t, output, is.StringContaining("lobby AP"))
+}
+
+func TestPortImportReportsMalformedRows(t *testing.T) {
+	host, posted := startPortImportServer(t)
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, "token", GS316EP)
+	file := writePortImportFile(t, "port,name,flow_control\n1,lobby AP,on\nx,camera,\n3,printer,maybe\n")
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "port", "import", "--address", host, "--file", file})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeGeneralError))
+	then.AssertThat(t, len(posted()), is.EqualTo(0))
+	then.AssertThat(t, output, is.StringContaining("line 3: invalid port 'x'"))
+	then.AssertThat(t, output, is.StringContaining("line 4: invalid flow control 'maybe'"))
+}
+
+func TestReadPortImportRowsValidatesHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"unknown column", "port,label\n1,ap\n", "unknown column 'label'"},
+		{"missing port", "name,speed\nap,auto\n", "the column 'port' is missing"},
+		{"empty file", "", "failed to read the header"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := readPortImportRows(strings.NewReader(test.content))
+
+			then.AssertThat(t, err.Error(), is.StringContaining(test.expected))
+		})
+	}
+}
+
+func TestReadPortImportRowsKeepsEmptyCells(t *testing.T) {
+	rows, err := readPortImportRows(strings.NewReader("port,name,ingress_limit\n5,,4 Mbit/s\n"))
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, rows, has.Length[portImportRow](1))
+	then.AssertThat(t, rows[0].line, is.EqualTo(2))
+	then.AssertThat(t, rows[0].update.Name == nil, is.True())
+	then.AssertThat(t, *rows[0].update.IngressLimit, is.EqualTo("4 Mbit/s"))
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type PortRenameCommand struct {
+	Address      string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Ports        []int  `required:"" help:"port number (starting with 1), use multiple times for renaming multiple ports at once" short:"p" name:"port"`
+	NameTemplate string `required:"" help:"template for the port names, %d is replaced by the port number, e.g. 'AP-%d'" short:"n" name:"name-template"`
+}
+
+func (rename *PortRenameCommand) Run(args *GlobalOptions) error {
+	names, err := portNamesFromTemplate(rename.NameTemplate, rename.Ports)
+	if err != nil {
+		return err
+	}
+	portSet := PortSetCommand{
+		Address: rename.Address,
+		Ports:   rename.Ports,
+		names:   names,
+	}
+	return portSet.Run(args)
+}
+
+// portNamesFromTemplate generates a name per port, by replacing %d in the template with the port number
+func portNamesFromTemplate(template string, ports []int) (map[int]string, error) {
+	if strings.Count(template, "%d") != 1 {
+		return nil, errors.New(fmt.Sprintf("name template '%s' must contain %%d exactly once", template))
+	}
+	names := make(map[int]string, len(ports))
+	for _, port := range ports {
+		name := fmt.Sprintf(template, port)
+		if strings.Contains(name, "%!") {
+			return nil, errors.New(fmt.Sprintf("name template '%s' must not contain other formatting verbs than %%d", template))
+		}
+		if len(name) > 16 {
+			return nil, errors.New(fmt.Sprintf("port name '%s' could not be set. PortSetting name must be 16 characters or less", name))
+		}
+		names[port] = name
+	}
+	return names, nil
+}
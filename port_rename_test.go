@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestPortNamesFromTemplate(t *testing.T) {
+	names, err := portNamesFromTemplate("AP-%d", []int{1, 2, 3})
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, names, is.EqualTo(map[int]string{1: "AP-1", 2: "AP-2", 3: "AP-3"}))
+}
+
+func TestPortNamesFromTemplateRejectsInvalidTemplates(t *testing.T) {
+	for _, template := range []string{"AP", "AP-%d-%d", "AP-%s-%d", "a-very-long-name-%d"} {
+		t.Run(template, func(t *testing.T) {
+			_, err := portNamesFromTemplate(template, []int{1})
+			then.AssertThat(t, err, is.Not(is.Nil()))
+		})
+	}
+}
+
+func TestPortRenameNamesEachPortOnGs316(t *testing.T) {
+	names, err := portNamesFromTemplate("AP-%d", []int{1, 2, 3})
+	then.AssertThat(t, err, is.Nil())
+	portSet := PortSetCommand{Ports: []int{1, 2, 3}, names: names}
+
+	for _, port := range []string{"1", "2", "3"} {
+		payload, err := createPortSettingUpdatePayloadGs316ep(&portSet, PortSetting{Name: "old"}, "token", port)
+
+		then.AssertThat(t, err, is.Nil())
+		then.AssertThat(t, payload.Get("PORT_NAME"), is.EqualTo("AP-"+port))
+	}
+}
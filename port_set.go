@@ -40,6 +40,22 @@ type PortSetCommand struct {
 	IngressRateLimit string  `optional:"" help:"set an incoming rate limit for the port ['1 Mbit/s', '128 Mbit/s', '16 Mbit/s', '2 Mbit/s', '256 Mbit/s', '32 Mbit/s', '4 Mbit/s', '512 Kbit/s', '512 Mbit/s', '64 Mbit/s', '8 Mbit/s', 'No Limit']" short:"i"`
 	EgressRateLimit  string  `optional:"" help:"set an outgoing rate limit for the port ['1 Mbit/s', '128 Mbit/s', '16 Mbit/s', '2 Mbit/s', '256 Mbit/s', '32 Mbit/s', '4 Mbit/s', '512 Kbit/s', '512 Mbit/s', '64 Mbit/s', '8 Mbit/s', 'No Limit']" short:"o"`
 	FlowControl      string  `optional:"" help:"enable/disable flow control on port ['Off', 'On']" short:"c"`
+	Diff             bool    `optional:"" help:"show the changes against the current settings first, and apply them only when confirmed" name:"diff"`
+	Yes              bool    `optional:"" help:"with --diff, apply the changes without asking" name:"yes" short:"y"`
+	// names overrides Name per port, see PortRenameCommand
+	names map[int]string
+}
+
+// nameFor returns the name to set for a port; if no name was given, it's the existing name (otherwise an empty
+// port name is always considered to be the "new" value which blanks the port name on the next setting update)
+func (portSet *PortSetCommand) nameFor(portId int, currentName string) string {
+	if name, ok := portSet.names[portId]; ok {
+		return name
+	}
+	if portSet.Name == nil {
+		return currentName
+	}
+	return *portSet.Name
 }
 
 func (portSet *PortSetCommand) Run(args *GlobalOptions) error {
@@ -53,6 +69,19 @@ func (portSet *PortSetCommand) Run(args *GlobalOptions) error {
 		args.model = model
 
 	}
+	if err := validatePortIds(model, portSet.Ports); err != nil {
+		return err
+	}
+	if portSet.Diff {
+		changes, err := portSet.diffPortSettings(args)
+		if err != nil {
+			return err
+		}
+		apply, err := confirmChanges(args, changes, portSet.Yes)
+		if !apply {
+			return err
+		}
+	}
 	if isModel30x(model) {
 		return portSet.runPortSetGs30xEPx(args)
 	}
@@ -76,13 +105,7 @@ func (portSet *PortSetCommand) runPortSetGs30xEPx(args *GlobalOptions) error {
 
 		portSetting := settings[switchPort-1]
 
-		// If the port name was not set by the user, set it to the existing name (otherwise an empty port name is always considered to be the
-		// "new" value which blanks the port name on the setting next update)
-		if portSet.Name == nil {
-			portSet.Name = &portSetting.Name
-		}
-
-		name, err := comparePortSettings(Name, portSetting.Name, *portSet.Name)
+		name, err := comparePortSettings(Name, portSetting.Name, portSet.nameFor(switchPort, portSetting.Name))
 		if err != nil {
 			return err
 		}
@@ -189,17 +212,12 @@ func (portSet *PortSetCommand) runPortSetGs316EPx(args *GlobalOptions) (err erro
 }
 
 func createPortSettingUpdatePayloadGs316ep(portSet *PortSetCommand, currentSetting PortSetting, token string, portId string) (url.Values, error) {
-	// If the port name was not set by the user, set it to the existing name (otherwise an empty port name is always considered to be the
-	// "new" value which blanks the port name on the setting next update)
-	if portSet.Name == nil {
-		portSet.Name = &currentSetting.Name
-	}
-
+	portIndex, _ := strconv.Atoi(portId)
 	newSetting := url.Values{
 		"Gambit":    {token},
 		"TYPE":      {"portInfo"},
 		"PORT_NO":   {portId},
-		"PORT_NAME": {*portSet.Name},
+		"PORT_NAME": {portSet.nameFor(portIndex, currentSetting.Name)},
 		// default values, for all requests (not entirely sure about the meaning)
 		"COLOR1G":    {"NOTSET"},
 		"COLOR100M":  {"NOTSET"},
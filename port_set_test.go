@@ -1,9 +1,13 @@
 package main
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 
+	"github.com/corbym/gocrest/has"
 	"github.com/corbym/gocrest/is"
 	"github.com/corbym/gocrest/then"
 )
@@ -180,3 +184,46 @@ func TestCreatePortSettingUpdatePayloadGs316ep(t *testing.T) {
 
 	then.AssertThat(t, value.Encode(), is.StringContaining("FLOW_CONTROL=4"))
 }
+
+func TestPortSetDiffNotConfirmedExitsWithNotApplied(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer string
+	}{
+		{"declined", "n\n"},
+		{"end of input", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var posts []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					posts = append(posts, r.URL.Path)
+					return
+				}
+				if r.URL.Path == "/" {
+					_, _ = w.Write([]byte(loadTestFile(string(GS308EPP), "_root.html")))
+					return
+				}
+				_, _ = w.Write([]byte(loadTestFile(string(GS308EPP), "dashboard.cgi.html")))
+			}))
+			defer server.Close()
+			host := strings.TrimPrefix(server.URL, "http://")
+			tokenDir := t.TempDir()
+			writeTestToken(t, tokenDir, host, "token", GS308EPP)
+			confirmationInput = strings.NewReader(test.answer)
+			defer func() { confirmationInput = os.Stdin }()
+
+			var exitCode int
+			output := captureOutput(func() {
+				exitCode = run([]string{"--token-dir", tokenDir, "port", "set", "--address", host, "--port", "1", "--name", "uplink", "--diff"})
+			})
+
+			then.AssertThat(t, exitCode, is.EqualTo(exitCodeNotApplied))
+			then.AssertThat(t, output, is.StringContaining("uplink"))
+			then.AssertThat(t, output, is.StringContaining("changes not applied"))
+			then.AssertThat(t, posts, has.Length[string](0))
+		})
+	}
+}
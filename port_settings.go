@@ -1,29 +1,52 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 	"github.com/PuerkitoBio/goquery"
 	"io"
+	"ntgrrc/pkg/netgear"
 	"strconv"
 	"strings"
 )
 
 type PortCommand struct {
-	PortSettingsCommand PortSettingsCommand `cmd:"" name:"settings" help:"show switch port settings" default:"1"`
-	PortSetCommand      PortSetCommand      `cmd:"" name:"set" help:"set properties for a port number"`
+	PortSettingsCommand    PortSettingsCommand    `cmd:"" name:"settings" help:"show switch port settings" default:"1"`
+	PortSetCommand         PortSetCommand         `cmd:"" name:"set" help:"set properties for a port number"`
+	PortRenameCommand      PortRenameCommand      `cmd:"" name:"rename" help:"rename multiple ports by a name template, e.g. 'AP-%d'"`
+	PortFlowControlCommand PortFlowControlCommand `cmd:"" name:"flow-control" help:"turn flow control on or off for all ports at once"`
+	PortDescribeCommand    PortDescribeCommand    `cmd:"" name:"describe" help:"set the description of a port, which is its name"`
+	PortImportCommand      PortImportCommand      `cmd:"" name:"import" help:"apply port settings from a CSV file, skipping unchanged ports"`
 }
 
 type PortSettingsCommand struct {
-	Address string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Address string   `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Fields  []string `optional:"" help:"comma separated fields to show, e.g. port_id,port_name" name:"fields"`
 }
 
+// portSettingsColumns are the columns of the settings table, their fields are named like the json tags of netgear.PortSettings
+var portSettingsColumns = []tableColumn{
+	{"port_id", "Port ID"},
+	{"port_name", "Port Name"},
+	{"speed", "Speed"},
+	{"ingress_limit", "Ingress Limit"},
+	{"egress_limit", "Egress Limit"},
+	{"flow_control", "Flow Control"},
+	{"status", "Port Status"},
+	{"link_speed", "Link Speed"},
+}
+
+// portSettingsFields name the settings columns for --fields
+var portSettingsFields = fieldsOf(portSettingsColumns)
+
 func (port *PortSettingsCommand) Run(args *GlobalOptions) error {
+	if err := validateFields(portSettingsFields, port.Fields); err != nil {
+		return err
+	}
 	settings, _, err := requestPortSettings(args, port.Address)
 	if err != nil {
 		return err
 	}
-	prettyPrintPortSettings(args.model, args.OutputFormat, settings)
+	prettyPrintPortSettingsWithSummary(args.model, args.OutputFormat, settings, port.Fields...)
 	return nil
 }
 
@@ -33,22 +56,13 @@ func requestPortSettings(args *GlobalOptions, host string) (portSettings []PortS
 		return portSettings, hash, err
 	}
 
-	var requestUrl string
-	if isModel30x(model) {
-		requestUrl = fmt.Sprintf("http://%s/dashboard.cgi", host)
-	} else if isModel316(model) {
-		requestUrl = fmt.Sprintf("http://%s/iss/specific/dashboard.html", host)
-	} else {
-		panic("model not supported")
-	}
-
-	dashboardData, err := requestPage(args, host, requestUrl)
+	dashboardData, err := requestPage(args, host, portSettingsUrl(model, host))
 	if err != nil {
 		return portSettings, hash, err
 	}
 
 	if checkIsLoginRequired(dashboardData) {
-		return portSettings, hash, errors.New("no content. please, (re-)login first")
+		return portSettings, hash, netgear.NewAuthError("no content. please, (re-)login first", nil)
 	}
 
 	hash, err = findHashInHtml(model, strings.NewReader(dashboardData))
@@ -65,44 +79,104 @@ func requestPortSettings(args *GlobalOptions, host string) (portSettings []PortS
 	return portSettings, hash, err
 }
 
-func prettyPrintPortSettings(model NetgearModel, format OutputFormat, settings []PortSetting) {
+func portSettingsUrl(model NetgearModel, host string) string {
+	if isModel30x(model) {
+		return fmt.Sprintf("http://%s/dashboard.cgi", host)
+	}
+	if isModel316(model) {
+		return fmt.Sprintf("http://%s/iss/specific/dashboard.html", host)
+	}
+	panic("model not supported")
+}
 
-	var header = []string{"Port ID", "Port Name", "Speed", "Ingress Limit", "Egress Limit", "Flow Control", "Port Status", "Link Speed"}
+// portSettingsSummary aggregates the port settings, it's the footer of the 'port settings' table
+type portSettingsSummary struct {
+	ConnectedPorts  int `json:"connected_ports"`
+	PortsWithLimits int `json:"ports_with_limits"`
+	DisabledPorts   int `json:"disabled_ports"`
+}
+
+func prettyPrintPortSettings(model NetgearModel, format OutputFormat, settings []PortSetting, fields ...string) {
+	header, content := portSettingsTable(model, settings, fields)
+	switch format {
+	case MarkdownFormat:
+		printMarkdownTable(header, content)
+	case JsonFormat:
+		printJsonDataTable("port_settings", header, content)
+	default:
+		panic("not implemented format: " + format)
+	}
+}
+
+// prettyPrintPortSettingsWithSummary prints the settings like prettyPrintPortSettings, followed by their summary
+func prettyPrintPortSettingsWithSummary(model NetgearModel, format OutputFormat, settings []PortSetting, fields ...string) {
+	header, content := portSettingsTable(model, settings, fields)
+	summary := summarizePortSettings(model, settings)
+	switch format {
+	case MarkdownFormat:
+		printMarkdownTable(header, content)
+		fmt.Printf("\nconnected ports: %d, ports with limits: %d, disabled ports: %d\n",
+			summary.ConnectedPorts, summary.PortsWithLimits, summary.DisabledPorts)
+	case JsonFormat:
+		printJsonDataTableWithSummary("port_settings", header, content, summary)
+	default:
+		panic("not implemented format: " + format)
+	}
+}
+
+func portSettingsTable(model NetgearModel, settings []PortSetting, fields []string) ([]string, [][]string) {
+	var header = headerOf(portSettingsColumns)
 	var content [][]string
 
 	for _, setting := range settings {
+		setting = displayedPortSetting(model, setting)
 		var row []string
 		row = append(row, fmt.Sprintf("%d", setting.Index))
 		row = append(row, setting.Name)
-		if isModel30x(model) {
-			setting.Speed = bidiMapLookup(setting.Speed, portSpeedMap)
-		}
 		row = append(row, setting.Speed)
-		if isModel30x(model) {
-			setting.IngressRateLimit = bidiMapLookup(setting.IngressRateLimit, portRateLimitMap)
-		}
 		row = append(row, setting.IngressRateLimit)
-		if isModel30x(model) {
-			setting.EgressRateLimit = bidiMapLookup(setting.EgressRateLimit, portRateLimitMap)
-		}
 		row = append(row, setting.EgressRateLimit)
-		if isModel30x(model) {
-			setting.FlowControl = bidiMapLookup(setting.FlowControl, portFlowControlMap)
-		}
 		row = append(row, setting.FlowControl)
 		row = append(row, setting.PortStatus)
 		row = append(row, setting.LinkSpeed)
 		content = append(content, row)
 	}
-	switch format {
-	case MarkdownFormat:
-		printMarkdownTable(header, content)
-	case JsonFormat:
-		printJsonDataTable("port_settings", header, content)
-	default:
-		panic("not implemented format: " + format)
+	return selectColumns(portSettingsFields, header, content, fields)
+}
+
+// displayedPortSetting returns the setting with human-readable values, GS30x settings are codes
+func displayedPortSetting(model NetgearModel, setting PortSetting) PortSetting {
+	if isModel30x(model) {
+		setting.Speed = bidiMapLookup(setting.Speed, portSpeedMap)
+		setting.IngressRateLimit = bidiMapLookup(setting.IngressRateLimit, portRateLimitMap)
+		setting.EgressRateLimit = bidiMapLookup(setting.EgressRateLimit, portRateLimitMap)
+		setting.FlowControl = bidiMapLookup(setting.FlowControl, portFlowControlMap)
+	}
+	return setting
+}
+
+// summarizePortSettings counts the connected ports, the ports with an ingress or egress limit and the disabled ports
+func summarizePortSettings(model NetgearModel, settings []PortSetting) portSettingsSummary {
+	noLimit := portRateLimitMap["1"]
+	summary := portSettingsSummary{}
+	for _, setting := range settings {
+		setting = displayedPortSetting(model, setting)
+		if isPortConnected(setting) {
+			summary.ConnectedPorts++
+		}
+		if !strings.EqualFold(setting.IngressRateLimit, noLimit) || !strings.EqualFold(setting.EgressRateLimit, noLimit) {
+			summary.PortsWithLimits++
+		}
+		if strings.EqualFold(setting.Speed, portSpeedDisable) || strings.EqualFold(setting.PortStatus, "DISABLED") {
+			summary.DisabledPorts++
+		}
 	}
+	return summary
+}
 
+// isPortConnected reports whether a link is up, GS30x firmware shows "UP", GS316 firmware "CONNECTED"
+func isPortConnected(setting PortSetting) bool {
+	return strings.EqualFold(setting.PortStatus, "UP") || strings.EqualFold(setting.PortStatus, "CONNECTED")
 }
 
 func findPortSettingsInHtml(model NetgearModel, reader io.Reader) ([]PortSetting, error) {
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"encoding/json"
 	"github.com/corbym/gocrest/has"
 	"github.com/corbym/gocrest/is"
 	"github.com/corbym/gocrest/then"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 )
@@ -68,3 +71,36 @@ func TestFindPortSettingsInHtml(t *testing.T) {
 	}
 
 }
+
+func TestPortSettingsPrintsSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/iss/specific/dashboard.html" {
+			_, _ = w.Write([]byte(loadTestFile("GS316EP", "dashboard.html")))
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, "token", GS316EP)
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "--output-format", "json", "port", "settings", "--address", host})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	var result struct {
+		PortSettings []map[string]string `json:"port_settings"`
+		Summary      portSettingsSummary `json:"summary"`
+	}
+	then.AssertThat(t, json.Unmarshal([]byte(output), &result), is.Nil())
+	then.AssertThat(t, result.PortSettings, has.Length[map[string]string](16))
+	then.AssertThat(t, result.Summary, is.EqualTo(portSettingsSummary{ConnectedPorts: 2, PortsWithLimits: 3, DisabledPorts: 1}))
+
+	output = captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "port", "settings", "--address", host})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	then.AssertThat(t, output, is.StringContaining("connected ports: 2, ports with limits: 3, disabled ports: 1"))
+}
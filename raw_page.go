@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"ntgrrc/pkg/netgear"
+	"os"
+)
+
+type RawPageCommand struct {
+	Address    string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Page       string `required:"" enum:"poe-status,poe-config,port-settings,login" help:"the page to fetch, one of: poe-status, poe-config, port-settings, login" name:"page"`
+	OutputFile string `optional:"" help:"write the HTML into this file instead of printing it" name:"output-file" type:"path"`
+}
+
+func (raw *RawPageCommand) Run(args *GlobalOptions) error {
+	model, _, err := readTokenAndModel2GlobalOptions(args, raw.Address)
+	if err != nil {
+		return err
+	}
+
+	html, err := requestRawPage(args, model, raw.Address, raw.Page)
+	if err != nil {
+		return err
+	}
+
+	if raw.OutputFile != "" {
+		return os.WriteFile(raw.OutputFile, []byte(html), 0644)
+	}
+	fmt.Print(html)
+	return nil
+}
+
+// requestRawPage fetches a page the same way the commands parsing it do, without parsing it
+func requestRawPage(args *GlobalOptions, model NetgearModel, host string, page string) (string, error) {
+	if page == "login" {
+		requestUrl, err := loginPageUrl(model, host)
+		if err != nil {
+			return "", err
+		}
+		return doUnauthenticatedHttpRequestAndReadResponse(args, "GET", requestUrl, "")
+	}
+
+	var requestUrl string
+	switch page {
+	case "poe-status":
+		requestUrl = poeStatusUrl(model, host)
+	case "poe-config":
+		requestUrl = poeConfigUrl(model, host)
+	case "port-settings":
+		requestUrl = portSettingsUrl(model, host)
+	default:
+		return "", netgear.NewOperationError(fmt.Sprintf("unknown page '%s'", page), nil)
+	}
+
+	html, err := requestPage(args, host, requestUrl)
+	if err != nil {
+		return "", err
+	}
+	if checkIsLoginRequired(html) {
+		return "", netgear.NewAuthError("no content. please, (re-)login first", nil)
+	}
+	return html, nil
+}
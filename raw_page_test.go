@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestRawPagePrintsPoeStatusVerbatim(t *testing.T) {
+	mock := NewMockHTTPServer(GS305EP)
+	defer mock.Close()
+	host := strings.TrimPrefix(mock.URL(), "http://")
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, mock.sessionToken, GS305EP)
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "raw-page", "--address", host, "--page", "poe-status"})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	then.AssertThat(t, output, is.EqualTo(loadTestFile(string(GS305EP), "getPoePortStatus.cgi.html")))
+}
+
+func TestRawPageWritesOutputFile(t *testing.T) {
+	mock := NewMockHTTPServer(GS305EP)
+	defer mock.Close()
+	host := strings.TrimPrefix(mock.URL(), "http://")
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, mock.sessionToken, GS305EP)
+	outputFile := filepath.Join(t.TempDir(), "poe-config.html")
+
+	exitCode := run([]string{"--token-dir", tokenDir, "raw-page", "--address", host, "--page", "poe-config", "--output-file", outputFile})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	content, err := os.ReadFile(outputFile)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, string(content), is.EqualTo(loadTestFile(string(GS305EP), "PoEPortConfig.cgi.html")))
+}
+
+func TestRawPageRequiresLogin(t *testing.T) {
+	mock := NewMockHTTPServer(GS305EP)
+	defer mock.Close()
+	host := strings.TrimPrefix(mock.URL(), "http://")
+	tokenDir := t.TempDir()
+	writeTestToken(t, tokenDir, host, "expired-token", GS305EP)
+
+	exitCode := run([]string{"--token-dir", tokenDir, "raw-page", "--address", host, "--page", "poe-status"})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeAuthError))
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"ntgrrc/pkg/netgear"
+)
+
+type SelfTestCommand struct {
+	Address  string `required:"" help:"the Netgear switch's IP address or host name to connect to" short:"a"`
+	Password string `optional:"" help:"the admin console's password to test the login with; if omitted, the stored session is used" short:"p"`
+}
+
+// selfTestStep is one read-only operation of the self-test
+type selfTestStep struct {
+	name     string
+	run      func(args *GlobalOptions, host string) error
+	required bool // the following steps can't run without it
+}
+
+// selfTestResult is the outcome of a step, err is nil for a passed step
+type selfTestResult struct {
+	name    string
+	err     error
+	skipped bool
+}
+
+func (selftest *SelfTestCommand) Run(args *GlobalOptions) error {
+	results := runSelfTest(args, selftest.Address, selfTestSteps(selftest.Password))
+	prettyPrintSelfTestResults(args.OutputFormat, results)
+
+	failed := 0
+	for _, result := range results {
+		if result.err != nil || result.skipped {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return netgear.NewOperationError(fmt.Sprintf("%d of %d self-test steps did not pass", failed, len(results)), nil)
+	}
+	return nil
+}
+
+// selfTestSteps lists the read-only operations, which are safe to run on a production switch.
+// Logging in only creates a new session, it doesn't change the switch's configuration.
+func selfTestSteps(password string) []selfTestStep {
+	return []selfTestStep{
+		{"detect model", func(args *GlobalOptions, host string) error {
+			model, err := detectNetgearModel(args, host)
+			args.model = model
+			return err
+		}, true},
+		{"login", func(args *GlobalOptions, host string) error {
+			if password == "" {
+				model, _, err := readTokenAndModel2GlobalOptions(args, host)
+				args.model = model
+				return err
+			}
+			seedValue, err := getSeedValueFromSwitch(args, host)
+			if err != nil {
+				return err
+			}
+			return doLogin(args, host, encryptPassword(password, seedValue))
+		}, true},
+		{"poe status", func(args *GlobalOptions, host string) error {
+			_, err := requestPoeStatus(args, host)
+			return err
+		}, false},
+		{"poe settings", func(args *GlobalOptions, host string) error {
+			_, err := requestPoeConfiguration(args, host, &PoeExt{})
+			return err
+		}, false},
+		{"port settings", func(args *GlobalOptions, host string) error {
+			_, _, err := requestPortSettings(args, host)
+			return err
+		}, false},
+	}
+}
+
+// runSelfTest runs the steps in order; once a required step failed, the others are skipped
+func runSelfTest(args *GlobalOptions, host string, steps []selfTestStep) []selfTestResult {
+	var results []selfTestResult
+	aborted := false
+	for _, step := range steps {
+		if aborted {
+			results = append(results, selfTestResult{name: step.name, skipped: true})
+			continue
+		}
+		err := step.run(args, host)
+		results = append(results, selfTestResult{name: step.name, err: err})
+		if err != nil && step.required {
+			aborted = true
+		}
+	}
+	return results
+}
+
+func prettyPrintSelfTestResults(format OutputFormat, results []selfTestResult) {
+	var header = []string{"Step", "Result", "Error"}
+	var content [][]string
+	for _, result := range results {
+		switch {
+		case result.skipped:
+			content = append(content, []string{result.name, "skipped", ""})
+		case result.err != nil:
+			content = append(content, []string{result.name, "failed", result.err.Error()})
+		default:
+			content = append(content, []string{result.name, "passed", ""})
+		}
+	}
+	switch format {
+	case MarkdownFormat:
+		printMarkdownTable(header, content)
+	case JsonFormat:
+		printJsonDataTable("selftest", header, content)
+	default:
+		panic("not implemented format: " + format)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestSelfTestReportsAllReadStepsPassing(t *testing.T) {
+	pages := map[string]string{
+		"/":                                "_root.html",
+		"/wmi/login":                       "login.html",
+		"/redirect.html":                   "redirect.html",
+		"/iss/specific/poePortStatus.html": "poePortStatus_GetData_true.html",
+		"/iss/specific/poePortConf.html":   "poePortConf.html",
+		"/iss/specific/dashboard.html":     "dashboard.html",
+	}
+	var writes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path != "/redirect.html" {
+			writes = append(writes, r.URL.Path)
+		}
+		if page, ok := pages[r.URL.Path]; ok {
+			_, _ = w.Write([]byte(loadTestFile(string(GS316EP), page)))
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", t.TempDir(), "-f", "json", "selftest", "--address", host, "--password", "secret"})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	var result map[string][]map[string]string
+	then.AssertThat(t, json.Unmarshal([]byte(output), &result), is.Nil())
+	steps := result["selftest"]
+	then.AssertThat(t, steps, has.Length[map[string]string](5))
+	for _, step := range steps {
+		then.AssertThat(t, step["Result"], is.EqualTo("passed"))
+	}
+	then.AssertThat(t, writes, has.Length[string](0))
+}
+
+func TestSelfTestSkipsStepsAfterFailedLogin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(loadTestFile(string(GS316EP), "_root.html")))
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", t.TempDir(), "selftest", "--address", host})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOperationError))
+	then.AssertThat(t, output, is.StringContaining("detect model  | passed"))
+	then.AssertThat(t, output, is.StringContaining("port settings | skipped"))
+	then.AssertThat(t, output, is.StringContaining("4 of 5 self-test steps did not pass"))
+}
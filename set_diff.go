@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"ntgrrc/pkg/netgear"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// errChangesNotApplied makes a --diff exit with exitCodeNotApplied, when the changes weren't confirmed
+var errChangesNotApplied = errors.New("changes not applied")
+
+// confirmationInput is read for the answer, when --diff asks whether to apply the changes
+var confirmationInput io.Reader = os.Stdin
+
+// confirmChanges prints the changes of a --diff and reports whether to apply them: right away with --yes,
+// else after asking. Without changes there is nothing to apply. If the changes aren't confirmed,
+// e.g. declined or at the end of the input, errChangesNotApplied is returned.
+func confirmChanges(args *GlobalOptions, changes *netgear.SnapshotChanges, yes bool) (bool, error) {
+	if changes.IsEmpty() {
+		fmt.Println("no changes")
+		return false, nil
+	}
+	prettyPrintSnapshotChanges(args.OutputFormat, changes)
+	if yes {
+		return true, nil
+	}
+
+	if askConfirmation("apply these changes?") {
+		return true, nil
+	}
+	return false, errChangesNotApplied
+}
+
+// askConfirmation asks the question and reports whether it was answered with yes, anything else means no
+func askConfirmation(question string) bool {
+	fmt.Print(question + " [y/N] ")
+	answer, _ := bufio.NewReader(confirmationInput).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// displayedValue returns a setting like the settings tables show it, GS30x settings are codes
+func displayedValue(model NetgearModel, value string, mapping map[string]string) string {
+	if isModel30x(model) {
+		return bidiMapLookup(value, mapping)
+	}
+	return value
+}
+
+// wantedValue returns the value given by a flag, or the current one, if the flag is empty or means the same
+func wantedValue(model NetgearModel, current string, flag string, mapping map[string]string) string {
+	if flag == "" {
+		return current
+	}
+	if text, ok := mapping[flag]; ok && isModel30x(model) {
+		flag = text
+	}
+	if strings.EqualFold(current, flag) {
+		return current
+	}
+	return flag
+}
+
+// diffPoeSettings computes the changes of 'poe set' against the current settings, without applying them
+func (poe *PoeSetConfigCommand) diffPoeSettings(args *GlobalOptions) (*netgear.SnapshotChanges, error) {
+	if poe.LongerDetect != "" {
+		return nil, fmt.Errorf("--longer-detection-time can't be combined with --diff")
+	}
+	settings, err := requestPoeConfiguration(args, poe.Address, &PoeExt{})
+	if err != nil {
+		return nil, err
+	}
+
+	current := &netgear.Snapshot{}
+	wanted := &netgear.Snapshot{}
+	for _, setting := range settings {
+		have := netgear.POEPortSettings{
+			PortID:         int(setting.PortIndex),
+			PortName:       setting.PortName,
+			Enabled:        setting.PortPwr,
+			Mode:           netgear.POEMode(displayedValue(args.model, setting.PwrMode, pwrModeMap)),
+			Priority:       netgear.POEPriority(displayedValue(args.model, setting.PortPrio, portPrioMap)),
+			PowerLimitType: netgear.POELimitType(displayedValue(args.model, setting.LimitType, limitTypeMap)),
+			PowerLimitW:    float64(parseFloat32(setting.PwrLimit)),
+			DetectionType:  displayedValue(args.model, setting.DetecType, detecTypeMap),
+		}
+		current.POE = append(current.POE, have)
+		if !slices.Contains(poe.Ports, have.PortID) {
+			continue
+		}
+
+		want := have
+		if strings.Contains(strings.ToLower(poe.PortPwr), "enable") {
+			want.Enabled = true
+		} else if strings.Contains(strings.ToLower(poe.PortPwr), "disable") {
+			want.Enabled = false
+		}
+		want.Mode = netgear.POEMode(wantedValue(args.model, string(have.Mode), poe.PwrMode, pwrModeMap))
+		want.Priority = netgear.POEPriority(wantedValue(args.model, string(have.Priority), poe.PortPrio, portPrioMap))
+		want.PowerLimitType = netgear.POELimitType(wantedValue(args.model, string(have.PowerLimitType), poe.LimitType, limitTypeMap))
+		if poe.PwrLimit != "" {
+			limit, err := strconv.ParseFloat(poe.PwrLimit, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid power limit value: '%s'", poe.PwrLimit)
+			}
+			want.PowerLimitW = limit
+		}
+		want.DetectionType = wantedValue(args.model, have.DetectionType, poe.DetecType, detecTypeMap)
+		wanted.POE = append(wanted.POE, want)
+	}
+	return current.Diff(wanted), nil
+}
+
+// diffPortSettings computes the changes of 'port set' against the current settings, without applying them
+func (portSet *PortSetCommand) diffPortSettings(args *GlobalOptions) (*netgear.SnapshotChanges, error) {
+	settings, _, err := requestPortSettings(args, portSet.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	current := &netgear.Snapshot{}
+	wanted := &netgear.Snapshot{}
+	for _, setting := range settings {
+		have := netgear.PortSettings{
+			PortID:       int(setting.Index),
+			PortName:     setting.Name,
+			Speed:        netgear.PortSpeed(displayedValue(args.model, setting.Speed, portSpeedMap)),
+			IngressLimit: displayedValue(args.model, setting.IngressRateLimit, portRateLimitMap),
+			EgressLimit:  displayedValue(args.model, setting.EgressRateLimit, portRateLimitMap),
+			FlowControl:  strings.EqualFold(displayedValue(args.model, setting.FlowControl, portFlowControlMap), "on"),
+		}
+		current.Ports = append(current.Ports, have)
+		if !slices.Contains(portSet.Ports, have.PortID) {
+			continue
+		}
+
+		want := have
+		want.PortName = portSet.nameFor(have.PortID, have.PortName)
+		want.Speed = netgear.PortSpeed(wantedValue(args.model, string(have.Speed), portSet.Speed, portSpeedMap))
+		want.IngressLimit = wantedValue(args.model, have.IngressLimit, portSet.IngressRateLimit, portRateLimitMap)
+		want.EgressLimit = wantedValue(args.model, have.EgressLimit, portSet.EgressRateLimit, portRateLimitMap)
+		if portSet.FlowControl != "" {
+			want.FlowControl = strings.EqualFold(wantedValue(args.model, "", portSet.FlowControl, portFlowControlMap), "on")
+		}
+		wanted.Ports = append(wanted.Ports, want)
+	}
+	return current.Diff(wanted), nil
+}
@@ -17,16 +17,19 @@ import (
 
 // Config holds all program configuration
 type Config struct {
-	SwitchAddress string
-	Debug         bool
-	DryRun        bool
-	SkipPOE       bool
-	SkipBandwidth bool
-	SkipLEDs      bool
-	JSONOutput    bool
-	Delay         time.Duration
-	Timeout       time.Duration
-	Verbose       bool
+	SwitchAddress  string
+	Debug          bool
+	DryRun         bool
+	SkipPOE        bool
+	SkipBandwidth  bool
+	SkipLEDs       bool
+	JSONOutput     bool
+	Delay          time.Duration
+	Timeout        time.Duration
+	Verbose        bool
+	NoColor        bool
+	RestoreWorkers int
+	RestorePoll    time.Duration
 }
 
 // TestContext holds the test execution context
@@ -98,11 +101,15 @@ func parseFlags() *Config {
 	flag.BoolVar(&config.SkipLEDs, "skip-leds", false, "Skip LED control tests")
 	flag.BoolVar(&config.JSONOutput, "json", false, "Output results in JSON format")
 	flag.BoolVar(&config.Verbose, "verbose", false, "Verbose output (more details)")
+	flag.BoolVar(&config.NoColor, "no-color", false, "Disable colored output (also disabled by NO_COLOR or when piping)")
 
 	var delaySeconds int
 	var timeoutSeconds int
+	var restorePollSeconds int
 	flag.IntVar(&delaySeconds, "delay", 2, "Delay between operations in seconds")
 	flag.IntVar(&timeoutSeconds, "timeout", 30, "Operation timeout in seconds")
+	flag.IntVar(&config.RestoreWorkers, "restore-workers", 1, "Number of ports restored in parallel")
+	flag.IntVar(&restorePollSeconds, "restore-poll", 0, "Seconds to poll each restored port until it reflects the restored settings (0 = no polling)")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Switch Test Program - ntgrrc Library Validation\n\n")
@@ -135,6 +142,7 @@ func parseFlags() *Config {
 	config.SwitchAddress = args[0]
 	config.Delay = time.Duration(delaySeconds) * time.Second
 	config.Timeout = time.Duration(timeoutSeconds) * time.Second
+	config.RestorePoll = time.Duration(restorePollSeconds) * time.Second
 
 	// Validate configuration
 	if config.Delay < 0 || config.Delay > 60*time.Second {
@@ -161,7 +169,7 @@ func runTests(ctx context.Context, testCtx *TestContext) int {
 	config := testCtx.Config
 
 	// Initialize reporter
-	testCtx.Reporter = NewReporter(config.JSONOutput, config.Verbose)
+	testCtx.Reporter = NewReporter(config.JSONOutput, config.Verbose, colorEnabled(config.NoColor, os.Stdout))
 	
 	if !config.JSONOutput {
 		testCtx.Reporter.PrintHeader(config.SwitchAddress)
@@ -189,6 +197,8 @@ func runTests(ctx context.Context, testCtx *TestContext) int {
 
 	// Step 2: Initialize state management
 	testCtx.StateManager = NewStateManager(client, config.Debug)
+	testCtx.StateManager.SetConcurrency(config.RestoreWorkers)
+	testCtx.StateManager.SetRestorePolling(config.RestorePoll)
 	if err := testCtx.StateManager.CaptureInitialState(ctx); err != nil {
 		testCtx.Reporter.RecordError("state_capture", err)
 		if !config.JSONOutput {
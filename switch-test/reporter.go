@@ -5,6 +5,15 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	"golang.org/x/term"
+)
+
+// ANSI escape codes for the status symbols
+const (
+	ansiGreen = "\033[32m"
+	ansiRed   = "\033[31m"
+	ansiReset = "\033[0m"
 )
 
 // TestResult represents the result of a single test operation
@@ -51,6 +60,7 @@ type TestSummary struct {
 type Reporter struct {
 	jsonOutput     bool
 	verbose        bool
+	color          bool
 	testResults    []TestResult
 	portOperations []PortOperation
 	errors         []TestResult
@@ -59,17 +69,39 @@ type Reporter struct {
 	failedOps      int
 }
 
-// NewReporter creates a new test reporter
-func NewReporter(jsonOutput, verbose bool) *Reporter {
+// NewReporter creates a new test reporter, color enables ANSI colors for the status symbols
+func NewReporter(jsonOutput, verbose, color bool) *Reporter {
 	return &Reporter{
 		jsonOutput:     jsonOutput,
 		verbose:        verbose,
+		color:          color,
 		testResults:    make([]TestResult, 0),
 		portOperations: make([]PortOperation, 0),
 		errors:         make([]TestResult, 0),
 	}
 }
 
+// colorEnabled tells whether output to the file gets colored: only terminals do,
+// unless disabled by --no-color or the NO_COLOR environment variable
+func colorEnabled(noColor bool, file *os.File) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return term.IsTerminal(int(file.Fd()))
+}
+
+// statusSymbol returns ✓ or ✗, colored if enabled
+func (r *Reporter) statusSymbol(success bool) string {
+	symbol, color := "✓", ansiGreen
+	if !success {
+		symbol, color = "✗", ansiRed
+	}
+	if !r.color {
+		return symbol
+	}
+	return color + symbol + ansiReset
+}
+
 // PrintHeader prints the test program header
 func (r *Reporter) PrintHeader(switchAddress string) {
 	if r.jsonOutput {
@@ -128,10 +160,7 @@ func (r *Reporter) RecordPortOperation(portID int, operation string, success boo
 
 	// Print detailed operation result if verbose
 	if r.verbose && !r.jsonOutput {
-		status := "✓"
-		if !success {
-			status = "✗"
-		}
+		status := r.statusSymbol(success)
 		fmt.Printf("    %s Port %d %s", status, portID, operation)
 		if err != nil {
 			fmt.Printf(": %v", err)
@@ -175,10 +204,7 @@ func (r *Reporter) printTextReport(duration time.Duration) {
 	if len(r.testResults) > 0 {
 		fmt.Println("\nTest Results:")
 		for _, result := range r.testResults {
-			status := "✓"
-			if !result.Success {
-				status = "✗"
-			}
+			status := r.statusSymbol(result.Success)
 			fmt.Printf("  %s %s", status, result.Name)
 			if result.Message != "" {
 				fmt.Printf(" (%s)", result.Message)
@@ -194,7 +220,7 @@ func (r *Reporter) printTextReport(duration time.Duration) {
 	if len(r.errors) > 0 {
 		fmt.Println("\nErrors Encountered:")
 		for _, err := range r.errors {
-			fmt.Printf("  ✗ %s: %s\n", err.Name, err.Error)
+			fmt.Printf("  %s %s: %s\n", r.statusSymbol(false), err.Name, err.Error)
 		}
 	}
 
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestReporterPrintsNoColorsWhenPiped(t *testing.T) {
+	r, w, _ := os.Pipe()
+	oldStdout := os.Stdout
+	os.Stdout = w
+	outputC := make(chan string)
+	go func() {
+		output, _ := io.ReadAll(r)
+		outputC <- string(output)
+	}()
+
+	reporter := NewReporter(false, true, colorEnabled(false, w))
+	reporter.RecordPortOperation(1, "disable POE", true, nil)
+	reporter.RecordPortOperation(2, "disable POE", false, errors.New("port is locked"))
+	reporter.RecordError("restore state", errors.New("timeout"))
+	reporter.PrintFinalReport(time.Second)
+
+	w.Close()
+	os.Stdout = oldStdout
+	output := <-outputC
+
+	then.AssertThat(t, strings.Contains(output, "✓ Port 1 disable POE"), is.True())
+	then.AssertThat(t, strings.Contains(output, "✗ Port 2 disable POE"), is.True())
+	then.AssertThat(t, strings.Contains(output, "\033["), is.False())
+}
+
+func TestReporterColorsStatusSymbols(t *testing.T) {
+	reporter := NewReporter(false, false, true)
+
+	then.AssertThat(t, reporter.statusSymbol(true), is.EqualTo(ansiGreen+"✓"+ansiReset))
+	then.AssertThat(t, reporter.statusSymbol(false), is.EqualTo(ansiRed+"✗"+ansiReset))
+}
@@ -3,7 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
-	"reflect"
+	"sync"
 	"time"
 
 	"ntgrrc/pkg/netgear"
@@ -18,19 +18,49 @@ type SwitchState struct {
 	Timestamp    time.Time
 }
 
+// restoreThrottle is the pause of a restore worker after each port, to avoid overwhelming the switch
+const restoreThrottle = 100 * time.Millisecond
+
+// restorePollInterval is the pause between two reads of a port, which doesn't reflect the restored settings yet
+const restorePollInterval = 500 * time.Millisecond
+
 // StateManager handles backup and restoration of switch state
 type StateManager struct {
 	client       *netgear.Client
 	initialState *SwitchState
 	debug        bool
+	concurrency  int           // number of ports restored in parallel
+	throttle     time.Duration // pause of a worker after each port
+	pollTimeout  time.Duration // how long to poll a restored port until it reflects the settings, 0 to not poll
+	pollInterval time.Duration // pause between two polls of a port
 }
 
-// NewStateManager creates a new state manager
+// NewStateManager creates a new state manager, restoring one port at a time
 func NewStateManager(client *netgear.Client, debug bool) *StateManager {
 	return &StateManager{
-		client: client,
-		debug:  debug,
+		client:       client,
+		debug:        debug,
+		concurrency:  1,
+		throttle:     restoreThrottle,
+		pollInterval: restorePollInterval,
+	}
+}
+
+// SetConcurrency sets how many ports RestoreState restores in parallel
+func (sm *StateManager) SetConcurrency(workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	sm.concurrency = workers
+}
+
+// SetRestorePolling makes RestoreState read each port back after writing it, until it reflects the
+// restored settings, failing the port after timeout. A timeout of 0 turns polling off.
+func (sm *StateManager) SetRestorePolling(timeout time.Duration) {
+	if timeout < 0 {
+		timeout = 0
 	}
+	sm.pollTimeout = timeout
 }
 
 // CaptureInitialState captures the current state of the switch
@@ -94,31 +124,43 @@ func (sm *StateManager) RestoreState(ctx context.Context) error {
 		fmt.Println("Restoring switch to initial state...")
 	}
 
-	var errors []error
+	var failures []error
 
 	// Restore POE settings first (this includes enable/disable state)
+	var poeRestores []func(ctx context.Context) error
 	for _, setting := range sm.initialState.POESettings {
 		// Create POE update to restore settings
 		update := netgear.POEPortUpdate{
-			PortID:              setting.PortID,
-			Enabled:             &setting.Enabled,
-			Mode:                &setting.Mode,
-			Priority:            &setting.Priority,
-			PowerLimitType:      &setting.PowerLimitType,
-			PowerLimitW:         &setting.PowerLimitW,
+			PortID:         setting.PortID,
+			Enabled:        &setting.Enabled,
+			Mode:           &setting.Mode,
+			Priority:       &setting.Priority,
+			PowerLimitType: &setting.PowerLimitType,
+			PowerLimitW:    &setting.PowerLimitW,
 		}
-		
-		err := sm.client.POE().UpdatePort(ctx, update)
-		if err != nil {
-			errors = append(errors, fmt.Errorf("failed to restore POE settings for port %d: %w", setting.PortID, err))
-			continue
-		}
-		
-		// Small delay between operations to avoid overwhelming the switch
-		time.Sleep(100 * time.Millisecond)
+		initial := setting
+		poeRestores = append(poeRestores, func(ctx context.Context) error {
+			if err := sm.client.POE().UpdatePort(ctx, update); err != nil {
+				return fmt.Errorf("failed to restore POE settings for port %d: %w", update.PortID, err)
+			}
+			return sm.pollRestored(ctx, "POE settings", update.PortID, func(ctx context.Context) (bool, error) {
+				current, err := sm.client.POE().GetSettings(ctx)
+				if err != nil {
+					return false, err
+				}
+				for _, setting := range current {
+					if setting.PortID == initial.PortID {
+						return poeSettingMatches(initial, setting), nil
+					}
+				}
+				return false, nil
+			})
+		})
 	}
+	failures = append(failures, sm.runRestores(ctx, poeRestores)...)
 
 	// Restore port settings (bandwidth, etc.)
+	var portRestores []func(ctx context.Context) error
 	for _, setting := range sm.initialState.PortSettings {
 		// Create port update to restore settings
 		update := netgear.PortUpdate{
@@ -129,29 +171,32 @@ func (sm *StateManager) RestoreState(ctx context.Context) error {
 			EgressLimit:  &setting.EgressLimit,
 			FlowControl:  &setting.FlowControl,
 		}
-		
-		err := sm.client.Ports().UpdatePort(ctx, update)
-		if err != nil {
-			errors = append(errors, fmt.Errorf("failed to restore port settings for port %d: %w", setting.PortID, err))
-			continue
-		}
-		
-		time.Sleep(100 * time.Millisecond)
+		initial := setting
+		portRestores = append(portRestores, func(ctx context.Context) error {
+			if err := sm.client.Ports().UpdatePort(ctx, update); err != nil {
+				return fmt.Errorf("failed to restore port settings for port %d: %w", update.PortID, err)
+			}
+			return sm.pollRestored(ctx, "port settings", update.PortID, func(ctx context.Context) (bool, error) {
+				current, err := sm.client.Ports().GetSettings(ctx)
+				if err != nil {
+					return false, err
+				}
+				for _, setting := range current {
+					if setting.PortID == initial.PortID {
+						return portSettingMatches(initial, setting), nil
+					}
+				}
+				return false, nil
+			})
+		})
 	}
+	failures = append(failures, sm.runRestores(ctx, portRestores)...)
 
 	// LED control is not available in the current library implementation
 	// Skip LED restoration
 
-	// Return combined errors if any
-	if len(errors) > 0 {
-		var errMsg string
-		for i, err := range errors {
-			if i > 0 {
-				errMsg += "; "
-			}
-			errMsg += err.Error()
-		}
-		return fmt.Errorf("state restoration encountered %d errors: %s", len(errors), errMsg)
+	if len(failures) > 0 {
+		return &netgear.MultiError{Errors: failures}
 	}
 
 	if sm.debug {
@@ -161,6 +206,63 @@ func (sm *StateManager) RestoreState(ctx context.Context) error {
 	return nil
 }
 
+// pollRestored reads a restored port until restored reports the settings took, at most sm.pollTimeout.
+// Without a timeout it returns right away.
+func (sm *StateManager) pollRestored(ctx context.Context, what string, portID int, restored func(ctx context.Context) (bool, error)) error {
+	if sm.pollTimeout <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, sm.pollTimeout)
+	defer cancel()
+
+	for {
+		ok, err := restored(ctx)
+		if err == nil && ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("failed to read back the restored %s of port %d: %w", what, portID, err)
+			}
+			return fmt.Errorf("port %d did not reflect the restored %s within %s", portID, what, sm.pollTimeout)
+		case <-time.After(sm.pollInterval):
+		}
+		if sm.debug {
+			fmt.Printf("Port %d doesn't reflect the restored %s yet, reading again\n", portID, what)
+		}
+	}
+}
+
+// runRestores runs the restores of independent ports with at most sm.concurrency in parallel,
+// each worker pausing after a port, and returns the errors of all failed restores
+func (sm *StateManager) runRestores(ctx context.Context, restores []func(ctx context.Context) error) []error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures []error
+	)
+	workers := make(chan struct{}, sm.concurrency)
+	for _, restore := range restores {
+		workers <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := restore(ctx)
+			if err != nil {
+				mu.Lock()
+				failures = append(failures, err)
+				mu.Unlock()
+			}
+			// Small delay between operations to avoid overwhelming the switch
+			time.Sleep(sm.throttle)
+			<-workers
+		}()
+	}
+	wg.Wait()
+	return failures
+}
+
 // ValidateStateRestoration verifies that the current state matches the initial state
 func (sm *StateManager) ValidateStateRestoration(ctx context.Context) error {
 	if sm.initialState == nil {
@@ -234,11 +336,7 @@ func (sm *StateManager) comparePOESettings(initial, current []netgear.POEPortSet
 			return false
 		}
 
-		// Compare key fields (ignore transient fields like current power draw)
-		if initialSetting.Enabled != currentSetting.Enabled ||
-		   initialSetting.Mode != currentSetting.Mode ||
-		   initialSetting.Priority != currentSetting.Priority ||
-		   initialSetting.PowerLimitW != currentSetting.PowerLimitW {
+		if !poeSettingMatches(initialSetting, currentSetting) {
 			if sm.debug {
 				fmt.Printf("POE settings mismatch for port %d: initial=%+v, current=%+v\n", 
 					portID, initialSetting, currentSetting)
@@ -250,6 +348,14 @@ func (sm *StateManager) comparePOESettings(initial, current []netgear.POEPortSet
 	return true
 }
 
+// poeSettingMatches compares the key fields of a port's POE settings, ignoring transient ones like the power draw
+func poeSettingMatches(initial, current netgear.POEPortSettings) bool {
+	return initial.Enabled == current.Enabled &&
+		initial.Mode == current.Mode &&
+		initial.Priority == current.Priority &&
+		initial.PowerLimitW == current.PowerLimitW
+}
+
 // comparePortSettings compares two sets of port settings for equality
 func (sm *StateManager) comparePortSettings(initial, current []netgear.PortSettings) bool {
 	if len(initial) != len(current) {
@@ -278,8 +384,7 @@ func (sm *StateManager) comparePortSettings(initial, current []netgear.PortSetti
 			return false
 		}
 
-		// Compare key fields
-		if !reflect.DeepEqual(initialSetting, currentSetting) {
+		if !portSettingMatches(initialSetting, currentSetting) {
 			if sm.debug {
 				fmt.Printf("Port settings mismatch for port %d: initial=%+v, current=%+v\n", 
 					portID, initialSetting, currentSetting)
@@ -291,6 +396,15 @@ func (sm *StateManager) comparePortSettings(initial, current []netgear.PortSetti
 	return true
 }
 
+// portSettingMatches compares the restored fields of a port's settings, ignoring the link state and speed
+func portSettingMatches(initial, current netgear.PortSettings) bool {
+	return initial.PortName == current.PortName &&
+		initial.Speed == current.Speed &&
+		initial.IngressLimit == current.IngressLimit &&
+		initial.EgressLimit == current.EgressLimit &&
+		initial.FlowControl == current.FlowControl
+}
+
 // GetStateSummary returns a human-readable summary of the current state
 func (sm *StateManager) GetStateSummary() string {
 	if sm.initialState == nil {
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+
+	"ntgrrc/pkg/netgear"
+)
+
+func TestRestoreStateInParallelAggregatesErrors(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		restored = map[string][]string{}
+		running  int
+		peak     int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			return
+		}
+		_ = r.ParseForm()
+		port := r.PostForm.Get("port")
+		if r.URL.Path == "/iss/specific/interface.html" {
+			port = r.PostForm.Get("PORT_NO")
+		}
+		mu.Lock()
+		restored[r.URL.Path] = append(restored[r.URL.Path], port)
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if r.URL.Path == "/iss/specific/interface.html" && (port == "3" || port == "11") {
+			_, _ = w.Write([]byte(`alert("port is locked")`))
+		}
+	}))
+	defer server.Close()
+
+	tokenMgr := netgear.NewMemoryTokenManager()
+	_ = tokenMgr.StoreToken(context.Background(), server.URL, "test-token", netgear.ModelGS316EP)
+	client, err := netgear.NewClient(server.URL, netgear.WithTokenManager(tokenMgr))
+	then.AssertThat(t, err, is.Nil())
+
+	state := &SwitchState{}
+	for port := 1; port <= netgear.ModelGS316EP.PortCount(); port++ {
+		state.PortSettings = append(state.PortSettings, netgear.PortSettings{
+			PortID: port, Speed: netgear.PortSpeedAuto, IngressLimit: "No Limit", EgressLimit: "No Limit",
+		})
+		if port <= netgear.ModelGS316EP.POEPortCount() {
+			state.POESettings = append(state.POESettings, netgear.POEPortSettings{
+				PortID: port, Enabled: true, Mode: netgear.POEMode8023at, Priority: netgear.POEPriorityLow,
+				PowerLimitType: netgear.POELimitTypeUser, PowerLimitW: 15,
+			})
+		}
+	}
+	sm := NewStateManager(client, false)
+	sm.SetConcurrency(4)
+	sm.throttle = time.Millisecond
+	sm.initialState = state
+
+	err = sm.RestoreState(context.Background())
+
+	var multiErr *netgear.MultiError
+	then.AssertThat(t, errors.As(err, &multiErr), is.True())
+	then.AssertThat(t, multiErr.Errors, has.Length[error](2))
+	then.AssertThat(t, restored["/iss/specific/poePortConf.html"], has.Length[string](15))
+	then.AssertThat(t, restored["/iss/specific/interface.html"], has.Length[string](16))
+	then.AssertThat(t, peak <= 4, is.True())
+}
+
+func TestRestoreStatePollsUntilPortReflectsSettings(t *testing.T) {
+	var (
+		mu             sync.Mutex
+		enabled        = "1"
+		posted         bool
+		readsAfterPost int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/iss/specific/poePortConf.html" {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPost {
+			posted = true
+			return
+		}
+		// the switch applies the write late, only the second read after it shows the restored state
+		if posted {
+			readsAfterPost++
+			if readsAfterPost == 2 {
+				enabled = "1"
+			}
+		}
+		_, _ = fmt.Fprintf(w, `<li class="poePortSettingListItem"><input type="hidden" class="port" value="1">`+
+			`<input type="hidden" id="hidPortPwr" value="%s"><input type="hidden" id="hidPwrMode" value="802.3at">`+
+			`<input type="hidden" id="hidPortPrio" value="low"><input type="hidden" id="hidLimitType" value="user">`+
+			`<input type="hidden" class="pwrLimit" value="15"></li>`, enabled)
+	}))
+	defer server.Close()
+
+	tokenMgr := netgear.NewMemoryTokenManager()
+	_ = tokenMgr.StoreToken(context.Background(), server.URL, "test-token", netgear.ModelGS316EP)
+	client, err := netgear.NewClient(server.URL, netgear.WithTokenManager(tokenMgr))
+	then.AssertThat(t, err, is.Nil())
+	settings, err := client.POE().GetSettings(context.Background())
+	then.AssertThat(t, err, is.Nil())
+	mu.Lock()
+	enabled = "0"
+	mu.Unlock()
+
+	sm := NewStateManager(client, false)
+	sm.SetRestorePolling(time.Second)
+	sm.pollInterval = time.Millisecond
+	sm.throttle = time.Millisecond
+	sm.initialState = &SwitchState{POESettings: settings}
+
+	err = sm.RestoreState(context.Background())
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, readsAfterPost, is.EqualTo(2))
+}
+
+func TestRestoreStatePollsPortIgnoringLinkState(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		status = "Up"
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/iss/specific/interface.html" || r.Method != http.MethodGet {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = fmt.Fprintf(w, `<table><tr><th>Port</th><th>Name</th><th>Speed</th><th>Ingress</th><th>Egress</th>`+
+			`<th>Flow Control</th><th>Status</th><th>Link Speed</th></tr><tr><td>1</td><td>uplink</td><td>Auto</td>`+
+			`<td>No Limit</td><td>No Limit</td><td>Off</td><td>%s</td><td>1000M</td></tr></table>`, status)
+	}))
+	defer server.Close()
+
+	tokenMgr := netgear.NewMemoryTokenManager()
+	_ = tokenMgr.StoreToken(context.Background(), server.URL, "test-token", netgear.ModelGS316EP)
+	client, err := netgear.NewClient(server.URL, netgear.WithTokenManager(tokenMgr))
+	then.AssertThat(t, err, is.Nil())
+	settings, err := client.Ports().GetSettings(context.Background())
+	then.AssertThat(t, err, is.Nil())
+	// the link went down meanwhile, which the restore doesn't touch
+	mu.Lock()
+	status = "Down"
+	mu.Unlock()
+
+	sm := NewStateManager(client, false)
+	sm.SetRestorePolling(100 * time.Millisecond)
+	sm.pollInterval = time.Millisecond
+	sm.throttle = time.Millisecond
+	sm.initialState = &SwitchState{PortSettings: settings}
+
+	err = sm.RestoreState(context.Background())
+
+	then.AssertThat(t, err, is.Nil())
+}
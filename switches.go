@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"ntgrrc/pkg/netgear"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+const switchesFileName = "switches.yaml"
+
+// SwitchConfig describes a switch, which can be addressed by a friendly name instead of its address
+type SwitchConfig struct {
+	Address     string           `yaml:"address"`
+	Model       string           `yaml:"model"`
+	PasswordEnv string           `yaml:"password-env"`
+	PortGroups  map[string][]int `yaml:"port-groups"` // named groups of ports, used as @name, e.g. in poe cycle
+}
+
+// defaultSwitchesFilename returns ~/.config/ntgrrc/switches.yaml
+func defaultSwitchesFilename() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "ntgrrc", switchesFileName)
+}
+
+// loadSwitchesConfig reads the named switches from the given file.
+// A missing file is not an error, it just means no names are configured.
+func loadSwitchesConfig(filename string) (map[string]SwitchConfig, error) {
+	switches := map[string]SwitchConfig{}
+	if filename == "" {
+		return switches, nil
+	}
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return switches, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(data, &switches)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid switches config file '%s': %s", filename, err))
+	}
+	return switches, nil
+}
+
+// switchesEnvWarnings reports the malformed entries of NETGEAR_SWITCHES, which would silently leave a switch without password
+func switchesEnvWarnings() []string {
+	var warnings []string
+	_, problems := netgear.NewEnvironmentPasswordManager().SwitchesConfig()
+	for _, problem := range problems {
+		warnings = append(warnings, fmt.Sprintf("%v, the entry is ignored", problem))
+	}
+	return warnings
+}
+
+// resolveSwitch looks up a switch by name and falls back to treating the name as a literal address
+func resolveSwitch(switches map[string]SwitchConfig, nameOrAddress string) SwitchConfig {
+	if config, ok := switches[nameOrAddress]; ok && config.Address != "" {
+		return config
+	}
+	return SwitchConfig{Address: nameOrAddress}
+}
+
+// applySwitchConfig replaces a switch name given with --address in the selected command with the configured
+// address. A configured password environment variable and model are used, if the command accepts them.
+// The resolved config is returned, e.g. for expanding the port groups of the switch.
+func applySwitchConfig(command reflect.Value, switches map[string]SwitchConfig) SwitchConfig {
+	for command.Kind() == reflect.Pointer {
+		command = command.Elem()
+	}
+	if command.Kind() != reflect.Struct {
+		return SwitchConfig{}
+	}
+	address := command.FieldByName("Address")
+	if !address.IsValid() || address.Kind() != reflect.String {
+		return SwitchConfig{}
+	}
+
+	config := resolveSwitch(switches, address.String())
+	address.SetString(config.Address)
+
+	password := command.FieldByName("Password")
+	if config.PasswordEnv != "" && password.IsValid() && password.Kind() == reflect.String && password.String() == "" {
+		password.SetString(os.Getenv(config.PasswordEnv))
+	}
+	model := command.FieldByName("Model")
+	if config.Model != "" && model.IsValid() && model.Kind() == reflect.String && model.String() == "" {
+		model.SetString(config.Model)
+	}
+	return config
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+const testSwitchesConfig = `
+office-switch:
+  address: 192.168.1.10
+  model: GS308EP
+  password-env: OFFICE_SWITCH_PASSWORD
+lab:
+  address: gs316ep.lab.local
+  port-groups:
+    cameras: [1, 2, 5]
+`
+
+func writeTestSwitchesConfig(t *testing.T) string {
+	filename := filepath.Join(t.TempDir(), switchesFileName)
+	err := os.WriteFile(filename, []byte(testSwitchesConfig), 0600)
+	then.AssertThat(t, err, is.Nil())
+	return filename
+}
+
+func TestResolveSwitchByName(t *testing.T) {
+	switches, err := loadSwitchesConfig(writeTestSwitchesConfig(t))
+	then.AssertThat(t, err, is.Nil())
+
+	config := resolveSwitch(switches, "office-switch")
+
+	then.AssertThat(t, config.Address, is.EqualTo("192.168.1.10"))
+	then.AssertThat(t, config.Model, is.EqualTo("GS308EP"))
+	then.AssertThat(t, config.PasswordEnv, is.EqualTo("OFFICE_SWITCH_PASSWORD"))
+}
+
+func TestResolveSwitchPassesLiteralAddressThrough(t *testing.T) {
+	switches, err := loadSwitchesConfig(writeTestSwitchesConfig(t))
+	then.AssertThat(t, err, is.Nil())
+
+	config := resolveSwitch(switches, "192.168.1.99")
+
+	then.AssertThat(t, config, is.EqualTo(SwitchConfig{Address: "192.168.1.99"}))
+}
+
+func TestLoadSwitchesConfigMissingFile(t *testing.T) {
+	switches, err := loadSwitchesConfig(filepath.Join(t.TempDir(), "does-not-exist.yaml"))
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, len(switches), is.EqualTo(0))
+	then.AssertThat(t, resolveSwitch(switches, "office-switch").Address, is.EqualTo("office-switch"))
+}
+
+func TestLoadSwitchesConfigInvalidFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), switchesFileName)
+	err := os.WriteFile(filename, []byte("office-switch: [unclosed"), 0600)
+	then.AssertThat(t, err, is.Nil())
+
+	_, err = loadSwitchesConfig(filename)
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+}
+
+func TestApplySwitchConfigToLoginCommand(t *testing.T) {
+	t.Setenv("OFFICE_SWITCH_PASSWORD", "secret")
+	switches, err := loadSwitchesConfig(writeTestSwitchesConfig(t))
+	then.AssertThat(t, err, is.Nil())
+	login := &LoginCommand{Address: "office-switch"}
+
+	applySwitchConfig(reflect.ValueOf(login), switches)
+
+	then.AssertThat(t, login.Address, is.EqualTo("192.168.1.10"))
+	then.AssertThat(t, login.Password, is.EqualTo("secret"))
+	then.AssertThat(t, login.Model, is.EqualTo("GS308EP"))
+}
+
+func TestApplySwitchConfigKeepsExplicitPassword(t *testing.T) {
+	t.Setenv("OFFICE_SWITCH_PASSWORD", "secret")
+	switches, err := loadSwitchesConfig(writeTestSwitchesConfig(t))
+	then.AssertThat(t, err, is.Nil())
+	login := &LoginCommand{Address: "office-switch", Password: "given"}
+
+	applySwitchConfig(reflect.ValueOf(login), switches)
+
+	then.AssertThat(t, login.Password, is.EqualTo("given"))
+}
+
+func TestApplySwitchConfigExpandsPortGroups(t *testing.T) {
+	switches, err := loadSwitchesConfig(writeTestSwitchesConfig(t))
+	then.AssertThat(t, err, is.Nil())
+	cycle := &PoeCyclePowerCommand{Address: "lab", Ports: []int{7, 2}, PortSpec: []string{"@cameras"}}
+	args := &GlobalOptions{switchConfig: applySwitchConfig(reflect.ValueOf(cycle), switches)}
+
+	ports, err := portsWithSpec(args, cycle.Ports, cycle.PortSpec)
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, cycle.Address, is.EqualTo("gs316ep.lab.local"))
+	then.AssertThat(t, ports, is.EqualTo([]int{7, 2, 1, 5}))
+}
+
+func TestApplySwitchConfigRejectsGroupOfOtherSwitch(t *testing.T) {
+	switches, err := loadSwitchesConfig(writeTestSwitchesConfig(t))
+	then.AssertThat(t, err, is.Nil())
+	cycle := &PoeCyclePowerCommand{Address: "office-switch", PortSpec: []string{"@cameras"}}
+	args := &GlobalOptions{switchConfig: applySwitchConfig(reflect.ValueOf(cycle), switches)}
+
+	_, err = portsWithSpec(args, cycle.Ports, cycle.PortSpec)
+
+	then.AssertThat(t, err, is.Not(is.Nil()))
+}
+
+func TestSwitchesEnvWarnings(t *testing.T) {
+	t.Setenv("NETGEAR_SWITCHES", "office-switch=secret;lab")
+
+	warnings := switchesEnvWarnings()
+
+	then.AssertThat(t, warnings, is.EqualTo([]string{
+		"parsing error: NETGEAR_SWITCHES entry 2 has no '=' between host and password, the entry is ignored"}))
+}
+
+func TestRunQuietHidesSwitchesEnvWarnings(t *testing.T) {
+	t.Setenv("NETGEAR_SWITCHES", "office-switch=secret;lab")
+	oldStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	var exitCode int
+	captureOutput(func() {
+		exitCode = run([]string{"--quiet", "version"})
+	})
+	w.Close()
+	os.Stderr = oldStderr
+	stderr, _ := io.ReadAll(r)
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	then.AssertThat(t, string(stderr), is.EqualTo(""))
+}
+
+// writeMalformedDefaultSwitchesConfig writes a malformed switches.yaml into the home directory of the test
+func writeMalformedDefaultSwitchesConfig(t *testing.T) string {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	filename := filepath.Join(home, ".config", "ntgrrc", switchesFileName)
+	then.AssertThat(t, os.MkdirAll(filepath.Dir(filename), 0700), is.Nil())
+	then.AssertThat(t, os.WriteFile(filename, []byte("office-switch: [unclosed"), 0600), is.Nil())
+	return filename
+}
+
+func TestRunWarnsAboutMalformedDefaultSwitchesConfig(t *testing.T) {
+	writeMalformedDefaultSwitchesConfig(t)
+	oldStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	var exitCode int
+	captureOutput(func() {
+		exitCode = run([]string{"version"})
+	})
+	w.Close()
+	os.Stderr = oldStderr
+	stderr, _ := io.ReadAll(r)
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	then.AssertThat(t, string(stderr), is.StringContaining("invalid switches config file"))
+}
+
+func TestRunFailsWithMalformedExplicitSwitchesConfig(t *testing.T) {
+	filename := writeMalformedDefaultSwitchesConfig(t)
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--switches", filename, "version"})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeGeneralError))
+	then.AssertThat(t, output, is.StringContaining("invalid switches config file"))
+}
+
+func TestApplySwitchConfigFailsForPortGroupOfMalformedConfig(t *testing.T) {
+	_, switchesErr := loadSwitchesConfig(writeMalformedDefaultSwitchesConfig(t))
+	cycle := &PoeCyclePowerCommand{Address: "lab", PortSpec: []string{"1,@cameras"}}
+	args := &GlobalOptions{switchConfig: applySwitchConfig(reflect.ValueOf(cycle), map[string]SwitchConfig{}), switchesErr: switchesErr}
+
+	_, err := portsWithSpec(args, cycle.Ports, cycle.PortSpec)
+
+	then.AssertThat(t, err, is.EqualTo(switchesErr))
+
+	ports, err := portsWithSpec(args, []int{3}, []string{"1,3"})
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, ports, is.EqualTo([]int{3, 1}))
+}
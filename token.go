@@ -6,6 +6,7 @@ import (
 	"hash/adler32"
 	"io"
 	"io/fs"
+	"ntgrrc/pkg/netgear"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,6 +14,10 @@ import (
 
 const separator = ":"
 
+// addressSeparator starts the switch's address after the token, as the file name is a hash of it.
+// Files of former versions don't have an address.
+const addressSeparator = "\n"
+
 func storeToken(args *GlobalOptions, host string, token string) error {
 	err := ensureConfigPathExists(args.TokenDir)
 	if err != nil {
@@ -21,7 +26,7 @@ func storeToken(args *GlobalOptions, host string, token string) error {
 	if args.Verbose {
 		fmt.Println("Storing login token " + tokenFilename(args.TokenDir, host))
 	}
-	data := fmt.Sprintf("%s%s%s", args.model, separator, token)
+	data := fmt.Sprintf("%s%s%s%s%s", args.model, separator, token, addressSeparator, host)
 	return os.WriteFile(tokenFilename(args.TokenDir, host), []byte(data), 0644)
 }
 
@@ -42,14 +47,15 @@ func readTokenAndModel2GlobalOptions(args *GlobalOptions, host string) (NetgearM
 	}
 	bytes, err := os.ReadFile(tokenFilename(args.TokenDir, host))
 	if errors.Is(err, fs.ErrNotExist) {
-		return "", "", errors.New("no session (token) exists. please login first")
+		return "", "", netgear.NewAuthError("no session (token) exists. please login first", nil)
 	}
-	data := strings.SplitN(string(bytes), separator, 2)
+	content, _, _ := strings.Cut(string(bytes), addressSeparator)
+	data := strings.SplitN(content, separator, 2)
 	if len(data) != 2 {
-		return "", "", errors.New("you did an upgrade from a former ntgrcc version. please login again")
+		return "", "", netgear.NewAuthError("you did an upgrade from a former ntgrcc version. please login again", nil)
 	}
 	if !isSupportedModel(data[0]) {
-		return "", "", errors.New("unknown model stored in token. please login again")
+		return "", "", netgear.NewAuthError("unknown model stored in token. please login again", nil)
 	}
 	args.model = NetgearModel(data[0])
 	args.token = data[1]
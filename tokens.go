@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type TokensCommand struct {
+	TokensListCommand  TokensListCommand  `cmd:"" name:"list" help:"list the switches with a stored session, the tokens are not shown" default:"1"`
+	TokensClearCommand TokensClearCommand `cmd:"" name:"clear" help:"remove stored sessions of one or all switches"`
+}
+
+type TokensListCommand struct {
+}
+
+type TokensClearCommand struct {
+	Address string `optional:"" help:"the Netgear switch's IP address or host name, which session to remove" short:"a" xor:"target"`
+	All     bool   `optional:"" help:"remove the sessions of all switches" name:"all" xor:"target"`
+}
+
+// storedToken is a token file, the address is unknown for files of former versions
+type storedToken struct {
+	Address string
+	Model   NetgearModel
+	File    string
+}
+
+func (list *TokensListCommand) Run(args *GlobalOptions) error {
+	tokens, err := findStoredTokens(args.TokenDir)
+	if err != nil {
+		return err
+	}
+	prettyPrintStoredTokens(args.OutputFormat, tokens)
+	return nil
+}
+
+func (clear *TokensClearCommand) Run(args *GlobalOptions) error {
+	if !clear.All && clear.Address == "" {
+		return errors.New("either give --address or --all")
+	}
+	var files []string
+	if clear.All {
+		tokens, err := findStoredTokens(args.TokenDir)
+		if err != nil {
+			return err
+		}
+		for _, token := range tokens {
+			files = append(files, token.File)
+		}
+	} else {
+		files = append(files, tokenFilename(args.TokenDir, clear.Address))
+	}
+
+	removed := 0
+	for _, file := range files {
+		err := os.Remove(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if args.Verbose {
+			fmt.Println("removed token " + file)
+		}
+		removed++
+	}
+	fmt.Printf("removed %d stored session(s)\n", removed)
+	return nil
+}
+
+// findStoredTokens reads all token files of the token directory, sorted by address
+func findStoredTokens(tokenDir string) ([]storedToken, error) {
+	files, err := filepath.Glob(filepath.Join(dotConfigDirName(tokenDir), "token-*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []storedToken
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		content, address, _ := strings.Cut(string(data), addressSeparator)
+		model, _, _ := strings.Cut(content, separator)
+		if address == "" {
+			address = unknown
+		}
+		tokens = append(tokens, storedToken{Address: address, Model: NetgearModel(model), File: file})
+	}
+	sort.SliceStable(tokens, func(i, j int) bool { return tokens[i].Address < tokens[j].Address })
+	return tokens, nil
+}
+
+func prettyPrintStoredTokens(format OutputFormat, tokens []storedToken) {
+	var header = []string{"Address", "Model", "Token", "File"}
+	var content [][]string
+	for _, token := range tokens {
+		content = append(content, []string{token.Address, string(token.Model), "<redacted>", token.File})
+	}
+	switch format {
+	case MarkdownFormat:
+		printMarkdownTable(header, content)
+	case JsonFormat:
+		printJsonDataTable("tokens", header, content)
+	default:
+		panic("not implemented format: " + format)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/corbym/gocrest/has"
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestTokensListShowsAddressesWithoutTokens(t *testing.T) {
+	tokenDir := t.TempDir()
+	for host, model := range map[string]NetgearModel{"lab-switch": GS316EP, "192.168.0.2": GS305EP} {
+		args := &GlobalOptions{TokenDir: tokenDir, model: model}
+		then.AssertThat(t, storeToken(args, host, "secret-"+host), is.Nil())
+	}
+	// a token file of a former version, without the address
+	writeTestToken(t, tokenDir, "old-switch", "secret-old", GS308EP)
+
+	var exitCode int
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "-f", "json", "tokens", "list"})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	then.AssertThat(t, output, is.Not(is.StringContaining("secret")))
+	var result map[string][]map[string]string
+	then.AssertThat(t, json.Unmarshal([]byte(output), &result), is.Nil())
+	tokens := result["tokens"]
+	then.AssertThat(t, tokens, has.Length[map[string]string](3))
+	then.AssertThat(t, tokens[0]["Address"], is.EqualTo("192.168.0.2"))
+	then.AssertThat(t, tokens[0]["Model"], is.EqualTo("GS305EP"))
+	then.AssertThat(t, tokens[1]["Address"], is.EqualTo("lab-switch"))
+	then.AssertThat(t, tokens[2]["Address"], is.EqualTo(unknown))
+	then.AssertThat(t, tokens[2]["Model"], is.EqualTo("GS308EP"))
+}
+
+func TestStoredTokenWithAddressIsReadable(t *testing.T) {
+	tokenDir := t.TempDir()
+	then.AssertThat(t, storeToken(&GlobalOptions{TokenDir: tokenDir, model: GS316EP}, "lab-switch", "abc"), is.Nil())
+
+	model, token, err := readTokenAndModel2GlobalOptions(&GlobalOptions{TokenDir: tokenDir}, "lab-switch")
+
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, model, is.EqualTo(GS316EP))
+	then.AssertThat(t, token, is.EqualTo("abc"))
+}
+
+func TestTokensClear(t *testing.T) {
+	tokenDir := t.TempDir()
+	for _, host := range []string{"switch1", "switch2", "switch3"} {
+		writeTestToken(t, tokenDir, host, "token", GS305EP)
+	}
+
+	var exitCode int
+	captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "tokens", "clear", "--address", "switch1"})
+	})
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	_, err := os.Stat(tokenFilename(tokenDir, "switch1"))
+	then.AssertThat(t, os.IsNotExist(err), is.True())
+	_, err = os.Stat(tokenFilename(tokenDir, "switch2"))
+	then.AssertThat(t, err, is.Nil())
+
+	output := captureOutput(func() {
+		exitCode = run([]string{"--token-dir", tokenDir, "tokens", "clear", "--all"})
+	})
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeOK))
+	then.AssertThat(t, output, is.StringContaining("removed 2 stored session(s)"))
+	tokens, err := findStoredTokens(tokenDir)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, tokens, has.Length[storedToken](0))
+}
+
+func TestTokensClearRequiresTarget(t *testing.T) {
+	var exitCode int
+	captureOutput(func() {
+		exitCode = run([]string{"--token-dir", t.TempDir(), "tokens", "clear"})
+	})
+
+	then.AssertThat(t, exitCode, is.EqualTo(exitCodeGeneralError))
+}
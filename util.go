@@ -1,7 +1,7 @@
 package main
 
 import (
-	"errors"
+	"ntgrrc/pkg/netgear"
 	"strconv"
 	"strings"
 )
@@ -23,13 +23,31 @@ func suffixToLength(s string, length int) string {
 }
 
 func parseFloat32(text string) float32 {
-	i64, _ := strconv.ParseFloat(text, 32)
-	return float32(i64)
+	f, _ := parseFloat32OK(text)
+	return f
 }
 
 func parseInt32(text string) int32 {
-	i64, _ := strconv.ParseInt(text, 10, 32)
-	return int32(i64)
+	i, _ := parseInt32OK(text)
+	return i
+}
+
+// parseFloat32OK is like parseFloat32, but tells a genuine 0 apart from text like "N/A", for which ok is false
+func parseFloat32OK(text string) (float32, bool) {
+	f64, err := strconv.ParseFloat(strings.TrimSpace(text), 32)
+	if err != nil {
+		return 0, false
+	}
+	return float32(f64), true
+}
+
+// parseInt32OK is like parseInt32, but tells a genuine 0 apart from text like "N/A", for which ok is false
+func parseInt32OK(text string) (int32, bool) {
+	i64, err := strconv.ParseInt(strings.TrimSpace(text), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int32(i64), true
 }
 
 func ensureModelIs30x(args *GlobalOptions, host string) error {
@@ -38,8 +56,8 @@ func ensureModelIs30x(args *GlobalOptions, host string) error {
 		return err
 	}
 	if !isModel30x(model) {
-		return errors.New("This command is not yet supported for your Netgear model. " +
-			"You might want to support the project by creating an issue on Github")
+		return netgear.NewModelError("This command is not yet supported for your Netgear model. "+
+			"You might want to support the project by creating an issue on Github", nil)
 	}
 	return nil
 }
@@ -16,3 +16,30 @@ func TestSuffixToLength(t *testing.T) {
 	s = suffixToLength("12345", 3)
 	then.AssertThat(t, s, is.EqualTo("12345"))
 }
+
+func TestParseNumbersTellZeroApartFromUnparseableText(t *testing.T) {
+	tests := []struct {
+		text          string
+		expectedInt   int32
+		expectedFloat float32
+		expectedOk    bool
+	}{
+		{"N/A", 0, 0, false},
+		{"", 0, 0, false},
+		{"-5", -5, -5, true},
+		{"0", 0, 0, true},
+		{" 53\n", 53, 53, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			i, ok := parseInt32OK(test.text)
+			then.AssertThat(t, i, is.EqualTo(test.expectedInt))
+			then.AssertThat(t, ok, is.EqualTo(test.expectedOk))
+
+			f, ok := parseFloat32OK(test.text)
+			then.AssertThat(t, f, is.EqualTo(test.expectedFloat))
+			then.AssertThat(t, ok, is.EqualTo(test.expectedOk))
+		})
+	}
+}
@@ -1,14 +1,42 @@
 package main
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
 
-// VERSION will be set at compile time - see Github actions...
+// VERSION, GIT_COMMIT and BUILD_TIME will be set at compile time - see Makefile and Github actions...
 var VERSION = "dev"
+var GIT_COMMIT = "unknown"
+var BUILD_TIME = "unknown"
+
+type VersionInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Built   string `json:"built"`
+}
 
 type VersionCommand struct {
 }
 
 func (version *VersionCommand) Run(args *GlobalOptions) error {
-	fmt.Println(VERSION)
+	switch args.OutputFormat {
+	case JsonFormat:
+		jsonData, err := json.MarshalIndent(currentVersionInfo(), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonData))
+	default:
+		fmt.Println(VERSION)
+	}
 	return nil
 }
+
+func currentVersionInfo() VersionInfo {
+	return VersionInfo{
+		Version: VERSION,
+		Commit:  GIT_COMMIT,
+		Built:   BUILD_TIME,
+	}
+}
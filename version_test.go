@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/corbym/gocrest/is"
+	"github.com/corbym/gocrest/then"
+)
+
+func TestVersionCommandPrintsPlainVersion(t *testing.T) {
+	args := createTestGlobalOptions(false, false, MarkdownFormat)
+
+	output := captureOutput(func() {
+		err := (&VersionCommand{}).Run(args)
+		then.AssertThat(t, err, is.Nil())
+	})
+
+	then.AssertThat(t, strings.TrimSpace(output), is.EqualTo(VERSION))
+}
+
+func TestVersionCommandPrintsJson(t *testing.T) {
+	oldVersion, oldCommit, oldBuilt := VERSION, GIT_COMMIT, BUILD_TIME
+	defer func() { VERSION, GIT_COMMIT, BUILD_TIME = oldVersion, oldCommit, oldBuilt }()
+	VERSION, GIT_COMMIT, BUILD_TIME = "v1.2.3", "abc1234", "2024-01-02_03:04:05"
+	args := createTestGlobalOptions(false, false, JsonFormat)
+
+	output := captureOutput(func() {
+		err := (&VersionCommand{}).Run(args)
+		then.AssertThat(t, err, is.Nil())
+	})
+
+	var info map[string]string
+	err := json.Unmarshal([]byte(output), &info)
+	then.AssertThat(t, err, is.Nil())
+	then.AssertThat(t, info["version"], is.EqualTo("v1.2.3"))
+	then.AssertThat(t, info["commit"], is.EqualTo("abc1234"))
+	then.AssertThat(t, info["built"], is.EqualTo("2024-01-02_03:04:05"))
+}